@@ -0,0 +1,117 @@
+// Package benchmarks provides well-known test problems with known
+// optima, for comparing Params choices on landscapes whose difficulty
+// is understood. Every Problem makes random genetic.Individuals and
+// knows the best score any of them can have, so searches can stop at
+// TargetFitness. As elsewhere in this package, fitness is maximized:
+// problems that are usually minimized are negated.
+package benchmarks
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Problem is a benchmark problem.
+type Problem struct {
+	Name string
+
+	// Generator makes random individuals.
+	Generator genetic.Generator
+
+	// Optimum is the best score an individual can have.
+	Optimum float64
+
+	// Lower, Upper and Function describe continuous problems, for use
+	// with optimizers that work on vectors directly, such as the es and
+	// cmaes subpackages. Function gives the same score as the
+	// individuals' Fitness.
+	Lower    []float64
+	Upper    []float64
+	Function func([]float64) (float64, error)
+
+	// Objectives and Front describe multi-objective problems.
+	// Objectives returns the objectives of a vector, which are
+	// minimized, and Front samples the given number of points on the
+	// true Pareto front.
+	Objectives func([]float64) []float64
+	Front      func(points int) [][]float64
+
+	// source is where the problem's individuals, and those bred
+	// from them, get their random numbers.
+	source *source
+}
+
+// Seed makes the problem's individuals, and every individual bred from
+// them, draw their random numbers from a source with the given seed
+// rather than the global one, so that a search seeded with Params.Seed
+// as well can be repeated exactly. It has no effect on problems that
+// weren't made by this package.
+func (p Problem) Seed(seed int64) {
+	if p.source != nil {
+		p.source.seed(seed)
+	}
+}
+
+// Population returns n random individuals.
+func (p Problem) Population(n int) ([]genetic.Individual, error) {
+	out := make([]genetic.Individual, n)
+	for i := range out {
+		var err error
+		if out[i], err = p.Generator(); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// source is a random source that is safe for concurrent use, as the
+// individuals of a problem may be bred by several searches at once.
+// Until it's seeded, and if it's nil, it draws from the global source.
+type source struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func (s *source) seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rng = rand.New(rand.NewSource(seed))
+}
+
+func (s *source) Float64() float64 {
+	if s == nil {
+		return rand.Float64()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rng == nil {
+		return rand.Float64()
+	}
+	return s.rng.Float64()
+}
+
+func (s *source) NormFloat64() float64 {
+	if s == nil {
+		return rand.NormFloat64()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rng == nil {
+		return rand.NormFloat64()
+	}
+	return s.rng.NormFloat64()
+}
+
+func (s *source) Intn(n int) int {
+	if s == nil {
+		return rand.Intn(n)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rng == nil {
+		return rand.Intn(n)
+	}
+	return s.rng.Intn(n)
+}
@@ -0,0 +1,240 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomjcleveland/genetic"
+)
+
+// bitString returns an individual of the problem with the given bits.
+func bitString(t *testing.T, p Problem, bits ...bool) *BitString {
+	ind, err := p.Generator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := ind.(*BitString)
+	out.Bits = bits
+	return out
+}
+
+func score(t *testing.T, ind genetic.Individual) float64 {
+	out, err := ind.Fitness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func Test_Trap(t *testing.T) {
+	p := Trap(3, 2)
+	assert.Equal(t, float64(6), p.Optimum)
+	assert.Equal(t, float64(6), score(t, bitString(t, p, true, true, true, true, true, true)))
+	assert.Equal(t, float64(4), score(t, bitString(t, p, false, false, false, false, false, false)))
+	assert.Equal(t, float64(3), score(t, bitString(t, p, true, false, false, false, false, false)))
+}
+
+func Test_Deceptive(t *testing.T) {
+	p := Deceptive(1)
+	assert.Equal(t, float64(30), score(t, bitString(t, p, true, true, true)))
+	assert.Equal(t, float64(28), score(t, bitString(t, p, false, false, false)))
+	assert.Equal(t, float64(14), score(t, bitString(t, p, true, false, false)))
+}
+
+// bruteForce returns the best score of any bit string of length n.
+func bruteForce(t *testing.T, p Problem, n int) float64 {
+	best := math.Inf(-1)
+	for i := 0; i < 1<<uint(n); i++ {
+		bits := make([]bool, n)
+		for j := range bits {
+			bits[j] = i&(1<<uint(j)) != 0
+		}
+		best = math.Max(best, score(t, bitString(t, p, bits...)))
+	}
+	return best
+}
+
+func Test_NK_Optimum_MatchesBruteForce(t *testing.T) {
+	for _, k := range []int{0, 1, 3, 5} {
+		p := NK(12, k, int64(k))
+		assert.InDelta(t, bruteForce(t, p, 12), p.Optimum, 1e-9)
+	}
+}
+
+func Test_Knapsack_Optimum_MatchesBruteForce(t *testing.T) {
+	p := RandomKnapsack(14, 1)
+	assert.Equal(t, bruteForce(t, p, 14), p.Optimum)
+
+	p = Knapsack([]int{5, 4, 3}, []int{10, 40, 30}, 7)
+	assert.Equal(t, float64(70), p.Optimum)
+	assert.Equal(t, float64(-5), score(t, bitString(t, p, true, true, true)))
+}
+
+func Test_Continuous_Optima(t *testing.T) {
+	tests := map[string]struct {
+		problem Problem
+		at      float64
+	}{
+		"Rastrigin":  {Rastrigin(5), 0},
+		"Rosenbrock": {Rosenbrock(5), 1},
+		"Ackley":     {Ackley(5), 0},
+		"Schwefel":   {Schwefel(5), 420.9687462275036},
+		"Griewank":   {Griewank(5), 0},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			x := []float64{test.at, test.at, test.at, test.at, test.at}
+			got, err := test.problem.Function(x)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.InDelta(t, test.problem.Optimum, got, 1e-9)
+
+			pop, err := test.problem.Population(100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, ind := range pop {
+				assert.True(t, score(t, ind) <= test.problem.Optimum)
+				for i, xi := range ind.(*Vector).X {
+					assert.True(t, xi >= test.problem.Lower[i] && xi <= test.problem.Upper[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_Vector_WithVector_Clamps(t *testing.T) {
+	ind, err := Rastrigin(2).Generator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := ind.(*Vector).WithVector([]float64{-10, 10}).(*Vector)
+	assert.Equal(t, []float64{-5.12, 5.12}, v.X)
+}
+
+func Test_ZDT_Front(t *testing.T) {
+	for _, p := range []Problem{ZDT1(10), ZDT2(10), ZDT3(10), ZDT4(10), ZDT6(10)} {
+		t.Run(p.Name, func(t *testing.T) {
+			front := p.Front(100)
+			assert.NotEmpty(t, front)
+			for _, point := range front {
+				assert.Equal(t, 2, len(point))
+			}
+
+			// On the front, g is 1 and the individual scores the optimum
+			x := make([]float64, 10)
+			x[0] = 0.5
+			ind := &Vector{X: x, problem: vectorOf(t, p).problem}
+			assert.InDelta(t, p.Optimum, score(t, ind), 1e-9)
+			objectives := ind.Objectives()
+			assert.InDelta(t, objectives[0], p.Objectives(x)[0], 1e-9)
+		})
+	}
+}
+
+func Test_DTLZ_Front(t *testing.T) {
+	x := make([]float64, 12)
+	for i := range x {
+		x[i] = rand.Float64()
+	}
+	for i := 2; i < len(x); i++ {
+		x[i] = 0.5
+	}
+
+	p := DTLZ1(12, 3)
+	sum := float64(0)
+	for _, f := range p.Objectives(x) {
+		sum += f
+	}
+	assert.InDelta(t, 0.5, sum, 1e-9)
+	for _, point := range p.Front(10) {
+		assert.InDelta(t, 0.5, point[0]+point[1]+point[2], 1e-9)
+	}
+
+	p = DTLZ2(12, 3)
+	sum = 0
+	for _, f := range p.Objectives(x) {
+		sum += f * f
+	}
+	assert.InDelta(t, 1, sum, 1e-9)
+	for _, point := range p.Front(10) {
+		assert.InDelta(t, 1, point[0]*point[0]+point[1]*point[1]+point[2]*point[2], 1e-9)
+	}
+	got, err := p.Function(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.InDelta(t, p.Optimum, got, 1e-9)
+}
+
+func vectorOf(t *testing.T, p Problem) *Vector {
+	ind, err := p.Generator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ind.(*Vector)
+}
+
+func Test_OneMax_Run(t *testing.T) {
+	p := OneMax(50)
+	pop, err := p.Population(50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctrl, err := genetic.NewController(genetic.Params{
+		Elitism:         2,
+		Mutation:        0.02,
+		Crossover:       0.9,
+		TargetFitness:   p.Optimum,
+		SelectionMethod: genetic.Tournament(3),
+		InitPop:         pop,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFunc()
+
+	ctrl.Start(ctx)
+	if err := ctrl.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	fittest, err := ctrl.Fittest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, p.Optimum, score(t, fittest))
+}
+
+func Test_Problem_Seed_Repeatable(t *testing.T) {
+	for _, p := range []func() Problem{
+		func() Problem { return OneMax(20) },
+		func() Problem { return Rastrigin(3) },
+	} {
+		var runs [][]string
+		for i := 0; i < 2; i++ {
+			problem := p()
+			problem.Seed(5)
+			pop, err := problem.Population(2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			child, err := pop[0].Crossover(pop[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			mutant, err := child.Mutate(0.5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			runs = append(runs, []string{fmt.Sprint(pop), fmt.Sprint(child), fmt.Sprint(mutant)})
+		}
+		assert.Equal(t, runs[0], runs[1])
+	}
+}
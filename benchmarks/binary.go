@@ -0,0 +1,268 @@
+package benchmarks
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// BitString is a fixed-length binary genome. It implements
+// genetic.Individual and genetic.PairCrossover.
+type BitString struct {
+	Bits []bool
+
+	fitness func([]bool) float64
+	source  *source
+}
+
+// Fitness implements genetic.Individual.
+func (b *BitString) Fitness() (float64, error) {
+	return b.fitness(b.Bits), nil
+}
+
+// Mutate implements genetic.Individual, flipping
+// every bit with the given probability.
+func (b *BitString) Mutate(rate float64) (genetic.Individual, error) {
+	out := &BitString{Bits: append([]bool{}, b.Bits...), fitness: b.fitness, source: b.source}
+	for i := range out.Bits {
+		if b.source.Float64() < rate {
+			out.Bits[i] = !out.Bits[i]
+		}
+	}
+	return out, nil
+}
+
+// Crossover implements genetic.Individual with uniform crossover.
+func (b *BitString) Crossover(partner genetic.Individual) (genetic.Individual, error) {
+	child, _, err := b.PairCrossover(partner)
+	return child, err
+}
+
+// PairCrossover implements genetic.PairCrossover with uniform
+// crossover, giving each child the bits the other didn't get.
+func (b *BitString) PairCrossover(partner genetic.Individual) (genetic.Individual, genetic.Individual, error) {
+	mate, ok := partner.(*BitString)
+	if !ok {
+		return nil, nil, fmt.Errorf("expected Individual to be *BitString, got %T", partner)
+	}
+	x := &BitString{Bits: append([]bool{}, b.Bits...), fitness: b.fitness, source: b.source}
+	y := &BitString{Bits: append([]bool{}, mate.Bits...), fitness: b.fitness, source: b.source}
+	for i := range x.Bits {
+		if b.source.Intn(2) == 0 {
+			x.Bits[i], y.Bits[i] = y.Bits[i], x.Bits[i]
+		}
+	}
+	return x, y, nil
+}
+
+func (b *BitString) String() string {
+	out := bytes.NewBuffer(nil)
+	for _, bit := range b.Bits {
+		if bit {
+			out.WriteByte('1')
+		} else {
+			out.WriteByte('0')
+		}
+	}
+	return out.String()
+}
+
+// bitStrings returns the problem called name of maximizing
+// fitness over random bit strings of length n.
+func bitStrings(name string, n int, fitness func([]bool) float64, optimum float64) Problem {
+	src := &source{}
+	return Problem{
+		Name: name,
+		Generator: func() (genetic.Individual, error) {
+			out := &BitString{Bits: make([]bool, n), fitness: fitness, source: src}
+			for i := range out.Bits {
+				out.Bits[i] = src.Intn(2) == 0
+			}
+			return out, nil
+		},
+		Optimum: optimum,
+		source:  src,
+	}
+}
+
+func ones(bits []bool) int {
+	out := 0
+	for _, bit := range bits {
+		if bit {
+			out++
+		}
+	}
+	return out
+}
+
+// OneMax returns the problem of maximizing the number of ones
+// in a string of n bits.
+func OneMax(n int) Problem {
+	return bitStrings(fmt.Sprintf("OneMax(%d)", n), n, func(bits []bool) float64 { return float64(ones(bits)) }, float64(n))
+}
+
+// Trap returns a concatenation of the given number of fully deceptive
+// trap functions of k bits each (Deb and Goldberg, 1993). A block of k
+// ones scores k, but otherwise every one in a block costs a point off
+// k-1, so everything but the optimum leads towards all zeros.
+func Trap(k, blocks int) Problem {
+	return bitStrings(fmt.Sprintf("Trap(%d, %d)", k, blocks), k*blocks, func(bits []bool) float64 {
+		score := 0
+		for i := 0; i < blocks; i++ {
+			u := ones(bits[i*k : (i+1)*k])
+			if u == k {
+				score += k
+			} else {
+				score += k - 1 - u
+			}
+		}
+		return float64(score)
+	}, float64(k*blocks))
+}
+
+// deceptive3 holds the scores of Goldberg's order-3 deceptive
+// function, indexed by the three bits read as a binary number.
+var deceptive3 = [8]float64{28, 26, 22, 0, 14, 0, 0, 30}
+
+// Deceptive returns a concatenation of the given number of Goldberg's
+// (1989) order-3 deceptive functions, which favour 000 at every lower
+// order while 111 is optimal.
+func Deceptive(blocks int) Problem {
+	return bitStrings(fmt.Sprintf("Deceptive(%d)", blocks), 3*blocks, func(bits []bool) float64 {
+		score := float64(0)
+		for i := 0; i < blocks; i++ {
+			score += deceptive3[index(bits[3*i:3*i+3])]
+		}
+		return score
+	}, float64(30*blocks))
+}
+
+// index reads bits as a binary number, most significant bit first.
+func index(bits []bool) int {
+	out := 0
+	for _, bit := range bits {
+		out <<= 1
+		if bit {
+			out |= 1
+		}
+	}
+	return out
+}
+
+// NK returns a random NK landscape (Kauffman, 1993) of n bits, where
+// every bit contributes a random amount between 0 and 1 depending on
+// itself and the k bits that follow it, and the fitness is the average
+// contribution. Neighbourhoods don't wrap around, so the optimum can be
+// found exactly by dynamic programming. The landscape is determined by
+// the seed, and k must be less than n.
+func NK(n, k int, seed int64) Problem {
+	rng := rand.New(rand.NewSource(seed))
+	tables := make([][]float64, n)
+	for i := range tables {
+		size := k + 1
+		if i+size > n {
+			size = n - i
+		}
+		tables[i] = make([]float64, 1<<uint(size))
+		for j := range tables[i] {
+			tables[i][j] = rng.Float64()
+		}
+	}
+	return bitStrings(fmt.Sprintf("NK(%d, %d, %d)", n, k, seed), n, func(bits []bool) float64 {
+		sum := float64(0)
+		for i, table := range tables {
+			end := i + k + 1
+			if end > n {
+				end = n
+			}
+			sum += table[index(bits[i:end])]
+		}
+		return sum / float64(n)
+	}, nkOptimum(tables, k)/float64(n))
+}
+
+// nkOptimum returns the highest possible sum of contributions, going
+// from left to right and keeping, for every setting of the last k bits,
+// the best sum of the contributions that are already determined.
+func nkOptimum(tables [][]float64, k int) float64 {
+	n := len(tables)
+	mask := 1<<uint(k) - 1
+	best := make([]float64, 1<<uint(k))
+	for j := k; j < n; j++ {
+		next := make([]float64, len(best))
+		for i := range next {
+			next[i] = math.Inf(-1)
+		}
+		for state, sum := range best {
+			for bit := 0; bit < 2; bit++ {
+				window := state<<1 | bit
+				score := sum + tables[j-k][window]
+				if score > next[window&mask] {
+					next[window&mask] = score
+				}
+			}
+		}
+		best = next
+	}
+	out := math.Inf(-1)
+	for state, sum := range best {
+		// The last k contributions only depend on the last k bits
+		for i := n - k; i < n; i++ {
+			size := uint(n - i)
+			sum += tables[i][state&(1<<size-1)]
+		}
+		out = math.Max(out, sum)
+	}
+	return out
+}
+
+// Knapsack returns the 0/1 knapsack problem of choosing items with the
+// given weights and values, so that their total value is as high as
+// possible without their total weight exceeding the capacity. Overweight
+// selections score the negated excess weight. The optimum is found by
+// dynamic programming, which takes time proportional to the number of
+// items times the capacity.
+func Knapsack(weights, values []int, capacity int) Problem {
+	return bitStrings(fmt.Sprintf("Knapsack(%d items)", len(weights)), len(weights), func(bits []bool) float64 {
+		weight, value := 0, 0
+		for i, bit := range bits {
+			if bit {
+				weight += weights[i]
+				value += values[i]
+			}
+		}
+		if weight > capacity {
+			return float64(capacity - weight)
+		}
+		return float64(value)
+	}, float64(knapsackOptimum(weights, values, capacity)))
+}
+
+// RandomKnapsack returns a Knapsack of n items with weights and values
+// between 1 and 100, determined by the seed, and a capacity of half
+// the total weight.
+func RandomKnapsack(n int, seed int64) Problem {
+	rng := rand.New(rand.NewSource(seed))
+	weights, values := make([]int, n), make([]int, n)
+	total := 0
+	for i := range weights {
+		weights[i], values[i] = 1+rng.Intn(100), 1+rng.Intn(100)
+		total += weights[i]
+	}
+	return Knapsack(weights, values, total/2)
+}
+
+func knapsackOptimum(weights, values []int, capacity int) int {
+	best := make([]int, capacity+1)
+	for i, w := range weights {
+		for c := capacity; c >= w; c-- {
+			if v := best[c-w] + values[i]; v > best[c] {
+				best[c] = v
+			}
+		}
+	}
+	return best[capacity]
+}
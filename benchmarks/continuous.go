@@ -0,0 +1,209 @@
+package benchmarks
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Vector is a real-valued genome within bounds. It implements
+// genetic.Individual, genetic.PairCrossover and genetic.RealVector,
+// so it also works with differential evolution.
+type Vector struct {
+	X []float64
+
+	problem *continuous
+}
+
+// continuous holds what the vectors of one problem share.
+type continuous struct {
+	lower, upper []float64
+	fitness      func([]float64) float64
+	objectives   func([]float64) []float64
+	source       *source
+}
+
+// Fitness implements genetic.Individual.
+func (v *Vector) Fitness() (float64, error) {
+	return v.problem.fitness(v.X), nil
+}
+
+// Objectives returns the objectives of multi-objective problems,
+// which are minimized, and nil otherwise.
+func (v *Vector) Objectives() []float64 {
+	if v.problem.objectives == nil {
+		return nil
+	}
+	return v.problem.objectives(v.X)
+}
+
+// Mutate implements genetic.Individual, adding Gaussian noise with a
+// standard deviation of a tenth of the width of the bounds to every
+// gene with the given probability.
+func (v *Vector) Mutate(rate float64) (genetic.Individual, error) {
+	out := append([]float64{}, v.X...)
+	for i := range out {
+		if v.problem.source.Float64() < rate {
+			out[i] += v.problem.source.NormFloat64() * (v.problem.upper[i] - v.problem.lower[i]) / 10
+		}
+	}
+	return v.WithVector(out), nil
+}
+
+// Crossover implements genetic.Individual with BLX-0.5 crossover.
+func (v *Vector) Crossover(partner genetic.Individual) (genetic.Individual, error) {
+	child, _, err := v.PairCrossover(partner)
+	return child, err
+}
+
+// PairCrossover implements genetic.PairCrossover with BLX-0.5
+// crossover (Eshelman and Schaffer, 1993), which draws every gene
+// uniformly from the interval spanned by the parents' genes, widened
+// by half its length on either side.
+func (v *Vector) PairCrossover(partner genetic.Individual) (genetic.Individual, genetic.Individual, error) {
+	mate, ok := partner.(*Vector)
+	if !ok {
+		return nil, nil, fmt.Errorf("expected Individual to be *Vector, got %T", partner)
+	}
+	x, y := make([]float64, len(v.X)), make([]float64, len(v.X))
+	for i := range x {
+		lo, hi := math.Min(v.X[i], mate.X[i]), math.Max(v.X[i], mate.X[i])
+		lo, hi = lo-(hi-lo)/2, hi+(hi-lo)/2
+		x[i], y[i] = lo+v.problem.source.Float64()*(hi-lo), lo+v.problem.source.Float64()*(hi-lo)
+	}
+	return v.WithVector(x), v.WithVector(y), nil
+}
+
+// Vector implements genetic.RealVector.
+func (v *Vector) Vector() []float64 {
+	return v.X
+}
+
+// WithVector implements genetic.RealVector, clamping
+// the genes to the bounds.
+func (v *Vector) WithVector(x []float64) genetic.Individual {
+	out := make([]float64, len(x))
+	for i, xi := range x {
+		out[i] = math.Max(v.problem.lower[i], math.Min(v.problem.upper[i], xi))
+	}
+	return &Vector{X: out, problem: v.problem}
+}
+
+func (v *Vector) String() string {
+	return fmt.Sprint(v.X)
+}
+
+// newContinuous returns a Problem over the box of c.
+func newContinuous(name string, c *continuous, optimum float64) Problem {
+	c.source = &source{}
+	return Problem{
+		Name: name,
+		Generator: func() (genetic.Individual, error) {
+			x := make([]float64, len(c.lower))
+			for i := range x {
+				x[i] = c.lower[i] + c.source.Float64()*(c.upper[i]-c.lower[i])
+			}
+			return &Vector{X: x, problem: c}, nil
+		},
+		Optimum: optimum,
+		Lower:   c.lower,
+		Upper:   c.upper,
+		Function: func(x []float64) (float64, error) {
+			return c.fitness(x), nil
+		},
+		source: c.source,
+	}
+}
+
+// box returns the bounds of n dimensions that all range over [lo, hi].
+func box(n int, lo, hi float64) ([]float64, []float64) {
+	lower, upper := make([]float64, n), make([]float64, n)
+	for i := range lower {
+		lower[i], upper[i] = lo, hi
+	}
+	return lower, upper
+}
+
+// Rastrigin returns the negated Rastrigin function of n dimensions
+// over [-5.12, 5.12], which is highly multimodal. The optimum of 0
+// is at the origin.
+func Rastrigin(n int) Problem {
+	lower, upper := box(n, -5.12, 5.12)
+	return newContinuous(fmt.Sprintf("Rastrigin(%d)", n), &continuous{lower: lower, upper: upper, fitness: rastrigin}, 0)
+}
+
+func rastrigin(x []float64) float64 {
+	sum := 10 * float64(len(x))
+	for _, xi := range x {
+		sum += xi*xi - 10*math.Cos(2*math.Pi*xi)
+	}
+	return -sum
+}
+
+// Rosenbrock returns the negated Rosenbrock function of n dimensions
+// over [-5, 10], whose optimum of 0 at (1, ..., 1) lies in a long,
+// curved valley.
+func Rosenbrock(n int) Problem {
+	lower, upper := box(n, -5, 10)
+	return newContinuous(fmt.Sprintf("Rosenbrock(%d)", n), &continuous{lower: lower, upper: upper, fitness: rosenbrock}, 0)
+}
+
+func rosenbrock(x []float64) float64 {
+	sum := float64(0)
+	for i := 0; i+1 < len(x); i++ {
+		a, b := x[i+1]-x[i]*x[i], 1-x[i]
+		sum += 100*a*a + b*b
+	}
+	return -sum
+}
+
+// Ackley returns the negated Ackley function of n dimensions over
+// [-32.768, 32.768], a nearly flat outer region around a deep hole.
+// The optimum of 0 is at the origin.
+func Ackley(n int) Problem {
+	lower, upper := box(n, -32.768, 32.768)
+	return newContinuous(fmt.Sprintf("Ackley(%d)", n), &continuous{lower: lower, upper: upper, fitness: ackley}, 0)
+}
+
+func ackley(x []float64) float64 {
+	squares, cosines := float64(0), float64(0)
+	for _, xi := range x {
+		squares += xi * xi
+		cosines += math.Cos(2 * math.Pi * xi)
+	}
+	nf := float64(len(x))
+	return 20*math.Exp(-0.2*math.Sqrt(squares/nf)) + math.Exp(cosines/nf) - 20 - math.E
+}
+
+// Schwefel returns the negated Schwefel function of n dimensions over
+// [-500, 500], whose optimum of 0 at (420.9687, ..., 420.9687) is far
+// from the next best local optima.
+func Schwefel(n int) Problem {
+	lower, upper := box(n, -500, 500)
+	return newContinuous(fmt.Sprintf("Schwefel(%d)", n), &continuous{lower: lower, upper: upper, fitness: schwefel}, 0)
+}
+
+func schwefel(x []float64) float64 {
+	sum := 418.9828872724338 * float64(len(x))
+	for _, xi := range x {
+		sum -= xi * math.Sin(math.Sqrt(math.Abs(xi)))
+	}
+	return -sum
+}
+
+// Griewank returns the negated Griewank function of n dimensions over
+// [-600, 600]. The optimum of 0 is at the origin.
+func Griewank(n int) Problem {
+	lower, upper := box(n, -600, 600)
+	return newContinuous(fmt.Sprintf("Griewank(%d)", n), &continuous{lower: lower, upper: upper, fitness: griewank}, 0)
+}
+
+func griewank(x []float64) float64 {
+	sum, product := float64(0), float64(1)
+	for i, xi := range x {
+		sum += xi * xi / 4000
+		product *= math.Cos(xi / math.Sqrt(float64(i+1)))
+	}
+	return -(sum - product + 1)
+}
@@ -0,0 +1,220 @@
+package benchmarks
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// newMultiObjective returns a Problem over the box between lower and
+// upper. Individuals score the negated distance function g, offset so
+// that the optimum of 0 means lying on the Pareto front; Objectives
+// gives the objectives themselves.
+func newMultiObjective(name string, lower, upper []float64, g func([]float64) float64, objectives func([]float64) []float64, front func(points int) [][]float64) Problem {
+	c := &continuous{lower: lower, upper: upper, fitness: func(x []float64) float64 { return -g(x) }, objectives: objectives}
+	p := newContinuous(name, c, 0)
+	p.Objectives = objectives
+	p.Front = front
+	return p
+}
+
+// zdt returns a two-objective ZDT problem (Zitzler, Deb and Thiele,
+// 2000) with the given f1, g and h, where f2 = g*h(f1, g), and the front
+// is f2 = h(f1, 1) for f1 between lo and 1.
+func zdt(name string, lower, upper []float64, f1, g func([]float64) float64, h func(f1, g float64) float64, lo float64, disconnected bool) Problem {
+	objectives := func(x []float64) []float64 {
+		a, b := f1(x), g(x)
+		return []float64{a, b * h(a, b)}
+	}
+	front := func(points int) [][]float64 {
+		var out [][]float64
+		for i := 0; i < points; i++ {
+			a := lo
+			if points > 1 {
+				a += (1 - lo) * float64(i) / float64(points-1)
+			}
+			out = append(out, []float64{a, h(a, 1)})
+		}
+		if disconnected {
+			out = nonDominated(out)
+		}
+		return out
+	}
+	return newMultiObjective(name, lower, upper, func(x []float64) float64 { return g(x) - 1 }, objectives, front)
+}
+
+// nonDominated returns the points of a two-objective
+// front that no other point dominates.
+func nonDominated(points [][]float64) [][]float64 {
+	sort.Slice(points, func(a, b int) bool { return points[a][0] < points[b][0] })
+	var out [][]float64
+	best := math.Inf(1)
+	for _, p := range points {
+		if p[1] < best {
+			out = append(out, p)
+			best = p[1]
+		}
+	}
+	return out
+}
+
+func first(x []float64) float64 {
+	return x[0]
+}
+
+// linearG is the g of ZDT1 to ZDT3.
+func linearG(x []float64) float64 {
+	sum := float64(0)
+	for _, xi := range x[1:] {
+		sum += xi
+	}
+	return 1 + 9*sum/float64(len(x)-1)
+}
+
+func convex(f1, g float64) float64 {
+	return 1 - math.Sqrt(f1/g)
+}
+
+func concave(f1, g float64) float64 {
+	return 1 - (f1/g)*(f1/g)
+}
+
+// ZDT1 returns the ZDT1 problem of n variables in [0, 1],
+// with a convex Pareto front. Individuals score 1-g.
+func ZDT1(n int) Problem {
+	lower, upper := box(n, 0, 1)
+	return zdt(fmt.Sprintf("ZDT1(%d)", n), lower, upper, first, linearG, convex, 0, false)
+}
+
+// ZDT2 returns the ZDT2 problem of n variables in [0, 1],
+// with a concave Pareto front. Individuals score 1-g.
+func ZDT2(n int) Problem {
+	lower, upper := box(n, 0, 1)
+	return zdt(fmt.Sprintf("ZDT2(%d)", n), lower, upper, first, linearG, concave, 0, false)
+}
+
+// ZDT3 returns the ZDT3 problem of n variables in [0, 1],
+// with a Pareto front of five disconnected parts. Individuals
+// score 1-g.
+func ZDT3(n int) Problem {
+	lower, upper := box(n, 0, 1)
+	h := func(f1, g float64) float64 {
+		return 1 - math.Sqrt(f1/g) - (f1/g)*math.Sin(10*math.Pi*f1)
+	}
+	return zdt(fmt.Sprintf("ZDT3(%d)", n), lower, upper, first, linearG, h, 0, true)
+}
+
+// ZDT4 returns the ZDT4 problem of n variables, the first in [0, 1]
+// and the others in [-5, 5], with a convex Pareto front and many local
+// fronts. Individuals score 1-g.
+func ZDT4(n int) Problem {
+	lower, upper := box(n, -5, 5)
+	lower[0], upper[0] = 0, 1
+	g := func(x []float64) float64 {
+		sum := 1 + 10*float64(len(x)-1)
+		for _, xi := range x[1:] {
+			sum += xi*xi - 10*math.Cos(4*math.Pi*xi)
+		}
+		return sum
+	}
+	return zdt(fmt.Sprintf("ZDT4(%d)", n), lower, upper, first, g, convex, 0, false)
+}
+
+// ZDT6 returns the ZDT6 problem of n variables in [0, 1], with a
+// concave Pareto front along which solutions are spread unevenly.
+// Individuals score 1-g.
+func ZDT6(n int) Problem {
+	lower, upper := box(n, 0, 1)
+	f1 := func(x []float64) float64 {
+		return 1 - math.Exp(-4*x[0])*math.Pow(math.Sin(6*math.Pi*x[0]), 6)
+	}
+	g := func(x []float64) float64 {
+		sum := float64(0)
+		for _, xi := range x[1:] {
+			sum += xi
+		}
+		return 1 + 9*math.Pow(sum/float64(len(x)-1), 0.25)
+	}
+	return zdt(fmt.Sprintf("ZDT6(%d)", n), lower, upper, f1, g, concave, 0.2807753191, false)
+}
+
+// dtlz returns a DTLZ problem (Deb, Thiele, Laumanns and Zitzler, 2002)
+// of n variables in [0, 1] and m objectives, where the last n-m+1
+// variables determine g, and the objectives are those of the given
+// shape scaled by 1+g. Individuals score -g. The front is sampled at
+// random points, determined by the given seed, with g at zero.
+func dtlz(name string, n, m int, g func([]float64) float64, shape func(x []float64, g float64) []float64) Problem {
+	lower, upper := box(n, 0, 1)
+	distance := func(x []float64) float64 { return g(x[m-1:]) }
+	objectives := func(x []float64) []float64 {
+		return shape(x, distance(x))
+	}
+	front := func(points int) [][]float64 {
+		rng := rand.New(rand.NewSource(1))
+		out := make([][]float64, points)
+		for i := range out {
+			x := make([]float64, m-1)
+			for j := range x {
+				x[j] = rng.Float64()
+			}
+			out[i] = shape(x, 0)
+		}
+		return out
+	}
+	return newMultiObjective(name, lower, upper, distance, objectives, front)
+}
+
+// DTLZ1 returns the DTLZ1 problem of n variables in [0, 1] and m
+// objectives, with a linear Pareto front where the objectives sum to
+// 0.5, and many local fronts. Individuals score -g.
+func DTLZ1(n, m int) Problem {
+	g := func(xm []float64) float64 {
+		sum := float64(len(xm))
+		for _, xi := range xm {
+			sum += (xi-0.5)*(xi-0.5) - math.Cos(20*math.Pi*(xi-0.5))
+		}
+		return 100 * sum
+	}
+	shape := func(x []float64, g float64) []float64 {
+		out := make([]float64, m)
+		for i := range out {
+			out[i] = 0.5 * (1 + g)
+			for j := 0; j < m-1-i; j++ {
+				out[i] *= x[j]
+			}
+			if i > 0 {
+				out[i] *= 1 - x[m-1-i]
+			}
+		}
+		return out
+	}
+	return dtlz(fmt.Sprintf("DTLZ1(%d, %d)", n, m), n, m, g, shape)
+}
+
+// DTLZ2 returns the DTLZ2 problem of n variables in [0, 1] and m
+// objectives, with a spherical Pareto front where the squares of the
+// objectives sum to 1. Individuals score -g.
+func DTLZ2(n, m int) Problem {
+	g := func(xm []float64) float64 {
+		sum := float64(0)
+		for _, xi := range xm {
+			sum += (xi - 0.5) * (xi - 0.5)
+		}
+		return sum
+	}
+	shape := func(x []float64, g float64) []float64 {
+		out := make([]float64, m)
+		for i := range out {
+			out[i] = 1 + g
+			for j := 0; j < m-1-i; j++ {
+				out[i] *= math.Cos(x[j] * math.Pi / 2)
+			}
+			if i > 0 {
+				out[i] *= math.Sin(x[m-1-i] * math.Pi / 2)
+			}
+		}
+		return out
+	}
+	return dtlz(fmt.Sprintf("DTLZ2(%d, %d)", n, m), n, m, g, shape)
+}
@@ -0,0 +1,71 @@
+package genetic
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Breeder breeds a generation in place of the controller's crossover
+// and mutation steps, as differential evolution does. Breed returns one
+// trial individual for every member of the population, in the same
+// order, and each trial competes only with the member it was bred for:
+// it replaces that member if it is at least as fit.
+type Breeder interface {
+	Breed(pop *Population) ([]Individual, error)
+
+	// Feedback reports, for every trial of the last call to Breed, how
+	// much fitter it was than the member it was bred for. Trials with
+	// non-negative improvements replaced their member.
+	Feedback(improvements []float64)
+}
+
+// populous is implemented by the Breeders of this package
+// that need a population of at least some size.
+type populous interface {
+	minPopulation() int
+}
+
+// Breeding creates a Breeder. Every controller creates its own, so
+// a Params value can be shared between searches.
+type Breeding func() (Breeder, error)
+
+// breedWith breeds a generation with a Breeder, scores the trials and
+// returns the survivors of one-to-one competition, sorted from fittest
+// to least fit.
+func (c *Controller) breedWith(b Breeder) ([]indWithScore, error) {
+	trials, err := b.Breed(c.population)
+	if err != nil {
+		return nil, fmt.Errorf("breeding step failed: %s", err)
+	}
+	if len(trials) != len(c.population.pop) {
+		return nil, fmt.Errorf("breeder returned %d trials for %d members", len(trials), len(c.population.pop))
+	}
+	scores, err := evaluateConcurrently(len(trials), c.params.Parallelism, c.params.Metrics, func(i int) (float64, error) {
+		return trials[i].Fitness()
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.evaluations += len(trials)
+
+	survivors := make([]indWithScore, len(trials))
+	improvements := make([]float64, len(trials))
+	successes := 0
+	for i, target := range c.population.pop {
+		improvements[i] = scores[i] - target.score
+		if improvements[i] < 0 {
+			survivors[i] = target
+			continue
+		}
+		survivors[i] = c.birth(trials[i], []uint64{target.id}, "breeding")
+		survivors[i].score = scores[i]
+		survivors[i].parentScore = target.score
+		if improvements[i] > 0 {
+			successes++
+		}
+	}
+	b.Feedback(improvements)
+	c.successRate = float64(successes) / float64(len(trials))
+	sort.Stable(sort.Reverse(pairs(survivors)))
+	return survivors, nil
+}
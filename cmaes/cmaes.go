@@ -0,0 +1,331 @@
+// Package cmaes implements the covariance matrix adaptation evolution
+// strategy (CMA-ES) for continuous problems, with optional IPOP and
+// BIPOP restarts. It shares its termination, observer and concurrent
+// evaluation machinery with genetic.Controller, and offers the same
+// Start, Wait and Fittest API.
+package cmaes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Restarts says whether and how the optimizer restarts once a run has
+// converged without meeting the target fitness.
+type Restarts int
+
+const (
+	// NoRestarts ends the search with genetic.ErrTerminated once
+	// the only run has converged.
+	NoRestarts Restarts = iota
+
+	// IPOP restarts with double the population size every time, which
+	// makes the search increasingly global (Auger and Hansen, 2005).
+	IPOP
+
+	// BIPOP alternates between IPOP's growing populations and runs
+	// with small populations and small, random step sizes, spending
+	// similar budgets on both (Hansen, 2009).
+	BIPOP
+)
+
+// Params holds all of the parameters for CMA-ES.
+type Params struct {
+	// Fitness scores a vector of object variables. As elsewhere in this
+	// package, higher is better; negate the objective to minimize it.
+	Fitness func([]float64) (float64, error)
+
+	// Mean is where the search starts. Its length is the number of
+	// dimensions. When Lower and Upper are set, it defaults to the
+	// middle of the box they describe.
+	Mean []float64
+
+	// Lower and Upper, if set, describe the box from which restarts
+	// draw their starting points. Otherwise, restarts begin at Mean.
+	Lower []float64
+	Upper []float64
+
+	// Sigma is the initial step size; roughly a third of the width of
+	// the region the optimum is expected in. The default is 0.3, or a
+	// third of the average width of the box when there is one.
+	Sigma float64
+
+	// Lambda is the population size of the first run. The default is
+	// 4+3*ln(n), where n is the number of dimensions.
+	Lambda int
+
+	// Restarts selects the restart strategy, and MaxRestarts limits the
+	// number of restarts; zero means there's no limit besides
+	// Termination.
+	Restarts    Restarts
+	MaxRestarts int
+
+	// TargetFitness is the fitness at which the search terminates.
+	TargetFitness float64
+
+	// Termination limits the search as a whole, across restarts.
+	Termination genetic.Termination
+
+	// Observer, if set, is told about every generation.
+	Observer genetic.Observer
+
+	// Parallelism is how many goroutines evaluate fitness.
+	Parallelism int
+}
+
+// Optimizer coordinates the running of CMA-ES.
+type Optimizer struct {
+	params Params
+	rng    *rand.Rand
+
+	generation  int
+	evaluations int
+	start       time.Time
+	restarts    int
+
+	// mu guards the fields below, which are read while
+	// the search runs in its own goroutine.
+	mu        sync.RWMutex
+	best      []float64
+	bestScore float64
+	stats     []genetic.Stats
+
+	err chan error
+}
+
+// New is the constructor for Optimizer. It returns an error if any of
+// the input parameters are not allowed.
+func New(params Params) (*Optimizer, error) {
+	if params.Fitness == nil {
+		return nil, errors.New("fitness function cannot be nil")
+	}
+	if (params.Lower == nil) != (params.Upper == nil) || len(params.Lower) != len(params.Upper) {
+		return nil, errors.New("lower and upper bounds must be given together, with the same length")
+	}
+	if params.Mean == nil && params.Lower != nil {
+		params.Mean = make([]float64, len(params.Lower))
+		for i := range params.Mean {
+			params.Mean[i] = (params.Lower[i] + params.Upper[i]) / 2
+		}
+	}
+	n := len(params.Mean)
+	if n == 0 {
+		return nil, errors.New("the initial mean or the bounds must give the number of dimensions")
+	}
+	if params.Lower != nil && len(params.Lower) != n {
+		return nil, fmt.Errorf("bounds have %d dimensions, mean has %d", len(params.Lower), n)
+	}
+	if params.Sigma == 0 {
+		params.Sigma = 0.3
+		if params.Lower != nil {
+			width := float64(0)
+			for i := range params.Lower {
+				width += params.Upper[i] - params.Lower[i]
+			}
+			params.Sigma = width / float64(n) / 3
+		}
+	}
+	if params.Sigma <= 0 {
+		return nil, errors.New("initial step size must be positive")
+	}
+	if params.Lambda == 0 {
+		params.Lambda = 4 + int(3*math.Log(float64(n)))
+	}
+	if params.Lambda < 2 {
+		return nil, errors.New("population size must be at least 2")
+	}
+	if params.Restarts < NoRestarts || params.Restarts > BIPOP {
+		return nil, fmt.Errorf("unknown restart strategy %d", params.Restarts)
+	}
+	if params.MaxRestarts < 0 {
+		return nil, errors.New("maximum number of restarts cannot be negative")
+	}
+	return &Optimizer{
+		params:    params,
+		rng:       rand.New(rand.NewSource(rand.Int63())),
+		bestScore: math.Inf(-1),
+		err:       make(chan error),
+	}, nil
+}
+
+// Run runs CMA-ES until a solution with the target fitness is
+// found, or the search terminates.
+func (o *Optimizer) Run() error {
+	o.Start(context.Background())
+	return o.Wait()
+}
+
+// Start begins the search in a new goroutine, and returns immediately.
+// The context parameter can be used to prematurely cancel it.
+func (o *Optimizer) Start(ctx context.Context) {
+	go func() {
+		o.err <- o.run(ctx)
+	}()
+}
+
+// Wait blocks until the search has finished.
+func (o *Optimizer) Wait() error {
+	if err, ok := <-o.err; ok {
+		return err
+	}
+	return errors.New("error channel is closed")
+}
+
+// Fittest returns the fittest solution found so far, and its score.
+func (o *Optimizer) Fittest() ([]float64, float64, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if o.best == nil {
+		return nil, 0, errors.New("no solution has been evaluated yet")
+	}
+	return append([]float64{}, o.best...), o.bestScore, nil
+}
+
+// Stats returns the statistics of every generation so far,
+// across all restarts.
+func (o *Optimizer) Stats() []genetic.Stats {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return append([]genetic.Stats{}, o.stats...)
+}
+
+// Restarts returns how many times the search has restarted.
+func (o *Optimizer) Restarts() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.restarts
+}
+
+func (o *Optimizer) run(ctx context.Context) error {
+	o.start = time.Now()
+	lambda, sigma := o.params.Lambda, o.params.Sigma
+
+	// BIPOP keeps track of the budget spent on each regime
+	largeLambda, largeBudget, smallBudget := o.params.Lambda, 0, 0
+	for {
+		before := o.evaluations
+		r := newRun(o.startingPoint(), sigma, lambda)
+		converged, err := o.runUntilConverged(ctx, r)
+		if err != nil || !converged {
+			return err
+		}
+		if lambda >= largeLambda {
+			largeBudget += o.evaluations - before
+		} else {
+			smallBudget += o.evaluations - before
+		}
+
+		if o.params.Restarts == NoRestarts || (o.params.MaxRestarts > 0 && o.restarts >= o.params.MaxRestarts) {
+			return genetic.ErrTerminated
+		}
+		o.mu.Lock()
+		o.restarts++
+		o.mu.Unlock()
+
+		switch {
+		case o.params.Restarts == BIPOP && smallBudget < largeBudget:
+			u := o.rng.Float64()
+			lambda = int(float64(o.params.Lambda) * math.Pow(0.5*float64(largeLambda)/float64(o.params.Lambda), u*u))
+			if lambda < 2 {
+				// Fewer than two offspring leave no parents to recombine.
+				lambda = 2
+			}
+			sigma = o.params.Sigma * math.Pow(10, -2*o.rng.Float64())
+		default:
+			largeLambda *= 2
+			lambda, sigma = largeLambda, o.params.Sigma
+		}
+	}
+}
+
+// runUntilConverged iterates a single run. It returns true if the run
+// converged, and false with a nil error if the target was met or the
+// search as a whole has terminated.
+func (o *Optimizer) runUntilConverged(ctx context.Context, r *run) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, genetic.ErrContextCancelled
+		default:
+		}
+
+		xs := r.sample(o.rng)
+		scores, err := genetic.EvaluateConcurrently(len(xs), o.params.Parallelism, func(i int) (float64, error) {
+			return o.params.Fitness(xs[i])
+		})
+		if err != nil {
+			return false, err
+		}
+		costs := make([]float64, len(scores))
+		for i, score := range scores {
+			costs[i] = -score
+		}
+		r.update(xs, costs)
+		o.record(xs, scores)
+
+		if o.bestScore >= o.params.TargetFitness {
+			return false, nil
+		}
+		if o.params.Termination.Done(o.stats) {
+			return false, genetic.ErrTerminated
+		}
+		if r.converged(costs) {
+			return true, nil
+		}
+	}
+}
+
+// record updates the best solution and the statistics
+// with a scored generation, and notifies the observer.
+func (o *Optimizer) record(xs [][]float64, scores []float64) {
+	o.generation++
+	o.evaluations += len(xs)
+	stats := genetic.Stats{
+		Generation:     o.generation,
+		Best:           math.Inf(-1),
+		Worst:          math.Inf(1),
+		PopulationSize: len(xs),
+		Evaluations:    o.evaluations,
+		Elapsed:        time.Since(o.start),
+	}
+
+	o.mu.Lock()
+	for i, score := range scores {
+		stats.Mean += score / float64(len(scores))
+		stats.Best = math.Max(stats.Best, score)
+		stats.Worst = math.Min(stats.Worst, score)
+		if score > o.bestScore {
+			o.best, o.bestScore = xs[i], score
+		}
+	}
+	for _, score := range scores {
+		stats.Diversity += (score - stats.Mean) * (score - stats.Mean) / float64(len(scores))
+	}
+	stats.Diversity = math.Sqrt(stats.Diversity)
+	o.stats = append(o.stats, stats)
+	o.mu.Unlock()
+
+	if o.params.Observer != nil {
+		o.params.Observer(stats)
+	}
+}
+
+// startingPoint returns the mean of a new run: the initial mean for the
+// first run, and a random point in the box for restarts if there is one.
+func (o *Optimizer) startingPoint() []float64 {
+	if o.generation == 0 || o.params.Lower == nil {
+		return o.params.Mean
+	}
+	out := make([]float64, len(o.params.Lower))
+	for i := range out {
+		out[i] = o.params.Lower[i] + o.rng.Float64()*(o.params.Upper[i]-o.params.Lower[i])
+	}
+	return out
+}
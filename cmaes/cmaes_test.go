@@ -0,0 +1,183 @@
+package cmaes
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomjcleveland/genetic"
+)
+
+func sphere(x []float64) (float64, error) {
+	sum := float64(0)
+	for _, xi := range x {
+		sum += xi * xi
+	}
+	return -sum, nil
+}
+
+func rosenbrock(x []float64) (float64, error) {
+	sum := float64(0)
+	for i := 0; i < len(x)-1; i++ {
+		sum += 100*math.Pow(x[i+1]-x[i]*x[i], 2) + math.Pow(1-x[i], 2)
+	}
+	return -sum, nil
+}
+
+func rastrigin(x []float64) (float64, error) {
+	sum := 10 * float64(len(x))
+	for _, xi := range x {
+		sum += xi*xi - 10*math.Cos(2*math.Pi*xi)
+	}
+	return -sum, nil
+}
+
+func Test_Run_Sphere_TargetMet(t *testing.T) {
+	opt, err := New(Params{
+		Fitness:       sphere,
+		Mean:          []float64{3, -2, 1, 4, -1},
+		TargetFitness: -1e-10,
+		Parallelism:   4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = opt.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, score, err := opt.Fittest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, score >= -1e-10)
+}
+
+func Test_Run_Rosenbrock_TargetMet(t *testing.T) {
+	opt, err := New(Params{
+		Fitness:       rosenbrock,
+		Mean:          make([]float64, 6),
+		Sigma:         0.5,
+		TargetFitness: -1e-8,
+		Restarts:      IPOP,
+		Termination:   genetic.Termination{MaxEvaluations: 200000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = opt.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, _, err := opt.Fittest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, xi := range x {
+		assert.InDelta(t, 1, xi, 1e-3)
+	}
+}
+
+func Test_Run_RastriginWithBIPOP_Restarts(t *testing.T) {
+	var observed []genetic.Stats
+	opt, err := New(Params{
+		Fitness:       rastrigin,
+		Lower:         []float64{-5, -5, -5},
+		Upper:         []float64{5, 5, 5},
+		TargetFitness: -1e-8,
+		Restarts:      BIPOP,
+		Termination:   genetic.Termination{MaxEvaluations: 300000},
+		Observer:      func(s genetic.Stats) { observed = append(observed, s) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = opt.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, opt.Stats(), observed)
+	_, score, _ := opt.Fittest()
+	assert.True(t, score >= -1e-8)
+}
+
+func Test_Run_BIPOPWithSmallLambda_TwoOffspringAtLeast(t *testing.T) {
+	opt, err := New(Params{
+		Fitness:       rastrigin,
+		Mean:          []float64{3, 3},
+		Sigma:         0.1,
+		Lambda:        2,
+		TargetFitness: 1,
+		Restarts:      BIPOP,
+		MaxRestarts:   3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, genetic.ErrTerminated, opt.Run())
+	assert.Equal(t, 3, opt.Restarts())
+	for _, s := range opt.Stats() {
+		assert.True(t, s.PopulationSize >= 2, "generation %d", s.Generation)
+		assert.False(t, math.IsNaN(s.Mean), "generation %d", s.Generation)
+	}
+}
+
+func Test_Run_NoRestartsAfterConvergence_ErrTerminated(t *testing.T) {
+	opt, err := New(Params{
+		Fitness:       rastrigin,
+		Mean:          []float64{3, 3, 3, 3},
+		Sigma:         0.1,
+		TargetFitness: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = opt.Run()
+
+	assert.Equal(t, genetic.ErrTerminated, err)
+	assert.Equal(t, 0, opt.Restarts())
+}
+
+func Test_New_TestTable_AllInvalidParams(t *testing.T) {
+	testTable := []struct {
+		label  string
+		params Params
+	}{
+		{label: "nil fitness", params: Params{Mean: []float64{0}}},
+		{label: "no dimensions", params: Params{Fitness: sphere}},
+		{label: "negative sigma", params: Params{Fitness: sphere, Mean: []float64{0}, Sigma: -1}},
+		{label: "tiny population", params: Params{Fitness: sphere, Mean: []float64{0}, Lambda: 1}},
+		{label: "mismatched bounds", params: Params{Fitness: sphere, Lower: []float64{0}, Upper: []float64{1, 1}}},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.label, func(t *testing.T) {
+			_, err := New(testCase.params)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func Test_eigen_SymmetricMatrix_Decomposed(t *testing.T) {
+	a := [][]float64{{4, 1, 2}, {1, 3, 0}, {2, 0, 5}}
+
+	values, vectors := eigen(a)
+
+	for k, value := range values {
+		for i := range a {
+			av := float64(0)
+			for j := range a {
+				av += a[i][j] * vectors[j][k]
+			}
+			assert.InDelta(t, value*vectors[i][k], av, 1e-9)
+		}
+	}
+}
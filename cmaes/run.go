@@ -0,0 +1,261 @@
+package cmaes
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// tolerance is used for the TolFun and TolX stopping criteria,
+// and maxCondition for the condition of the covariance matrix.
+const (
+	tolerance    = 1e-12
+	maxCondition = 1e14
+)
+
+// run is the state of a single, restart-free CMA-ES run. It minimizes,
+// so the optimizer hands it negated fitness scores.
+type run struct {
+	n      int
+	lambda int
+	mu     int
+
+	weights []float64
+	mueff   float64
+	cc      float64
+	cs      float64
+	c1      float64
+	cmu     float64
+	damps   float64
+	chiN    float64
+
+	mean     []float64
+	sigma    float64
+	sigma0   float64
+	pc       []float64
+	ps       []float64
+	c        [][]float64
+	b        [][]float64
+	d        []float64
+	invsqrtC [][]float64
+
+	generation int
+	evals      int
+	eigenEvals int
+	history    []float64
+	maxIter    int
+}
+
+func newRun(mean []float64, sigma float64, lambda int) *run {
+	n := len(mean)
+	r := &run{
+		n:      n,
+		lambda: lambda,
+		mu:     lambda / 2,
+		mean:   append([]float64{}, mean...),
+		sigma:  sigma,
+		sigma0: sigma,
+		pc:     make([]float64, n),
+		ps:     make([]float64, n),
+		d:      make([]float64, n),
+	}
+
+	// Recombination weights
+	sum := float64(0)
+	for i := 0; i < r.mu; i++ {
+		w := math.Log(float64(r.mu)+0.5) - math.Log(float64(i+1))
+		r.weights = append(r.weights, w)
+		sum += w
+	}
+	sumSq := float64(0)
+	for i := range r.weights {
+		r.weights[i] /= sum
+		sumSq += r.weights[i] * r.weights[i]
+	}
+	r.mueff = 1 / sumSq
+
+	// Adaptation constants, as recommended by Hansen
+	nf := float64(n)
+	r.cc = (4 + r.mueff/nf) / (nf + 4 + 2*r.mueff/nf)
+	r.cs = (r.mueff + 2) / (nf + r.mueff + 5)
+	r.c1 = 2 / ((nf+1.3)*(nf+1.3) + r.mueff)
+	r.cmu = math.Min(1-r.c1, 2*(r.mueff-2+1/r.mueff)/((nf+2)*(nf+2)+r.mueff))
+	r.damps = 1 + 2*math.Max(0, math.Sqrt((r.mueff-1)/(nf+1))-1) + r.cs
+	r.chiN = math.Sqrt(nf) * (1 - 1/(4*nf) + 1/(21*nf*nf))
+	r.maxIter = 100 + int(50*(nf+3)*(nf+3)/math.Sqrt(float64(lambda)))
+
+	r.c = identity(n)
+	r.b = identity(n)
+	r.invsqrtC = identity(n)
+	for i := range r.d {
+		r.d[i] = 1
+	}
+	return r
+}
+
+func identity(n int) [][]float64 {
+	out := make([][]float64, n)
+	for i := range out {
+		out[i] = make([]float64, n)
+		out[i][i] = 1
+	}
+	return out
+}
+
+// sample draws lambda new candidate solutions.
+func (r *run) sample(rng *rand.Rand) [][]float64 {
+	out := make([][]float64, r.lambda)
+	for k := range out {
+		z := make([]float64, r.n)
+		for i := range z {
+			z[i] = r.d[i] * rng.NormFloat64()
+		}
+		x := make([]float64, r.n)
+		for i := range x {
+			y := float64(0)
+			for j := range z {
+				y += r.b[i][j] * z[j]
+			}
+			x[i] = r.mean[i] + r.sigma*y
+		}
+		out[k] = x
+	}
+	return out
+}
+
+// update adapts the distribution to the sampled candidates and their
+// costs, which are minimized.
+func (r *run) update(xs [][]float64, costs []float64) {
+	r.generation++
+	r.evals += len(xs)
+
+	order := make([]int, len(xs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return costs[order[a]] < costs[order[b]] })
+	r.history = append(r.history, costs[order[0]])
+
+	old := r.mean
+	r.mean = make([]float64, r.n)
+	for i, w := range r.weights {
+		for j := range r.mean {
+			r.mean[j] += w * xs[order[i]][j]
+		}
+	}
+	yw := make([]float64, r.n)
+	for j := range yw {
+		yw[j] = (r.mean[j] - old[j]) / r.sigma
+	}
+
+	// Evolution paths
+	cy := mulVec(r.invsqrtC, yw)
+	psNorm := float64(0)
+	for i := range r.ps {
+		r.ps[i] = (1-r.cs)*r.ps[i] + math.Sqrt(r.cs*(2-r.cs)*r.mueff)*cy[i]
+		psNorm += r.ps[i] * r.ps[i]
+	}
+	psNorm = math.Sqrt(psNorm)
+	hsig := float64(0)
+	if psNorm/math.Sqrt(1-math.Pow(1-r.cs, 2*float64(r.evals)/float64(r.lambda)))/r.chiN < 1.4+2/(float64(r.n)+1) {
+		hsig = 1
+	}
+	for i := range r.pc {
+		r.pc[i] = (1-r.cc)*r.pc[i] + hsig*math.Sqrt(r.cc*(2-r.cc)*r.mueff)*yw[i]
+	}
+
+	// Covariance matrix
+	for i := 0; i < r.n; i++ {
+		for j := 0; j <= i; j++ {
+			rankMu := float64(0)
+			for k, w := range r.weights {
+				x := xs[order[k]]
+				rankMu += w * (x[i] - old[i]) * (x[j] - old[j]) / (r.sigma * r.sigma)
+			}
+			cij := (1-r.c1-r.cmu)*r.c[i][j] +
+				r.c1*(r.pc[i]*r.pc[j]+(1-hsig)*r.cc*(2-r.cc)*r.c[i][j]) +
+				r.cmu*rankMu
+			r.c[i][j], r.c[j][i] = cij, cij
+		}
+	}
+
+	// Step size
+	r.sigma *= math.Exp((r.cs / r.damps) * (psNorm/r.chiN - 1))
+
+	// Decompose C only every so often, since it's O(n³)
+	if float64(r.evals-r.eigenEvals) > float64(r.lambda)/(r.c1+r.cmu)/float64(r.n)/10 {
+		r.eigenEvals = r.evals
+		r.decompose()
+	}
+}
+
+func (r *run) decompose() {
+	values, vectors := eigen(r.c)
+	r.b = vectors
+	for i, v := range values {
+		r.d[i] = math.Sqrt(math.Max(v, 0))
+	}
+	for i := 0; i < r.n; i++ {
+		for j := 0; j < r.n; j++ {
+			sum := float64(0)
+			for k := 0; k < r.n; k++ {
+				if r.d[k] > 0 {
+					sum += r.b[i][k] * r.b[j][k] / r.d[k]
+				}
+			}
+			r.invsqrtC[i][j] = sum
+		}
+	}
+}
+
+func mulVec(m [][]float64, v []float64) []float64 {
+	out := make([]float64, len(m))
+	for i := range m {
+		for j := range v {
+			out[i] += m[i][j] * v[j]
+		}
+	}
+	return out
+}
+
+// converged reports whether the run should stop because progress has
+// stalled, following the usual CMA-ES stopping criteria: the best costs
+// of recent generations and the current ones lie too close together
+// (TolFun), the distribution has shrunk to nothing (TolX), the
+// covariance matrix is badly conditioned, or the run is simply too long.
+func (r *run) converged(costs []float64) bool {
+	if math.IsNaN(r.sigma) || math.IsInf(r.sigma, 0) || r.sigma == 0 {
+		return true
+	}
+	if r.generation >= r.maxIter {
+		return true
+	}
+
+	window := 10 + int(math.Ceil(30*float64(r.n)/float64(r.lambda)))
+	if len(r.history) >= window {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, c := range append(append([]float64{}, r.history[len(r.history)-window:]...), costs...) {
+			lo, hi = math.Min(lo, c), math.Max(hi, c)
+		}
+		if hi-lo < tolerance {
+			return true
+		}
+	}
+
+	tolX := true
+	for i := 0; i < r.n; i++ {
+		if r.sigma*math.Sqrt(r.c[i][i]) >= tolerance*r.sigma0 || r.sigma*math.Abs(r.pc[i]) >= tolerance*r.sigma0 {
+			tolX = false
+			break
+		}
+	}
+	if tolX {
+		return true
+	}
+
+	lo, hi := math.Inf(1), float64(0)
+	for _, d := range r.d {
+		lo, hi = math.Min(lo, d), math.Max(hi, d)
+	}
+	return lo == 0 || (hi*hi)/(lo*lo) > maxCondition
+}
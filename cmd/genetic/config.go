@@ -0,0 +1,288 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/tomjcleveland/genetic"
+	"github.com/tomjcleveland/genetic/benchmarks"
+	"github.com/tomjcleveland/genetic/tsp"
+)
+
+// Config describes a run.
+type Config struct {
+	Problem     ProblemConfig     `json:"problem"`
+	Population  int               `json:"population"`
+	Params      ParamsConfig      `json:"params"`
+	Termination TerminationConfig `json:"termination"`
+	Output      OutputConfig      `json:"output"`
+}
+
+// ProblemConfig names a built-in problem, and sizes it. Only the
+// built-in problems are supported: a search over a genome type of your
+// own needs a program that uses the genetic package directly.
+type ProblemConfig struct {
+	// Name is one of onemax, trap, deceptive, nk, knapsack, rastrigin,
+	// rosenbrock, ackley, schwefel, griewank, zdt1, zdt2, zdt3, zdt4,
+	// zdt6, dtlz1, dtlz2 and tsp.
+	Name string `json:"name"`
+
+	// Size is the number of bits, dimensions or items.
+	Size int `json:"size"`
+
+	// K is the block size of trap functions, and the number of
+	// neighbours in NK landscapes.
+	K int `json:"k"`
+
+	// Objectives is the number of objectives of DTLZ problems.
+	Objectives int `json:"objectives"`
+
+	// Seed determines random instances, of NK landscapes
+	// and knapsack problems.
+	Seed int64 `json:"seed"`
+
+	// File and OptimalTour are the TSPLIB .tsp file of a tsp problem,
+	// and optionally its .opt.tour file.
+	File        string `json:"file"`
+	OptimalTour string `json:"optimalTour"`
+}
+
+// ParamsConfig holds the genetic.Params values a config can set.
+type ParamsConfig struct {
+	Elitism     int     `json:"elitism"`
+	Mutation    float64 `json:"mutation"`
+	Crossover   float64 `json:"crossover"`
+	Parallelism int     `json:"parallelism"`
+
+	// Selection is "tournament" or "roulette", and TournamentSize
+	// the number of contestants in a tournament.
+	Selection      string `json:"selection"`
+	TournamentSize int    `json:"tournamentSize"`
+
+	// TargetFitness defaults to the problem's optimum.
+	TargetFitness *float64 `json:"targetFitness"`
+
+	// Seed seeds all random number generators. Zero picks a seed,
+	// which is recorded in the stats.
+	Seed int64 `json:"seed"`
+}
+
+// TerminationConfig holds the genetic.Termination values, with the
+// duration in the format of time.ParseDuration, such as "30s".
+type TerminationConfig struct {
+	MaxGenerations int    `json:"maxGenerations"`
+	MaxEvaluations int    `json:"maxEvaluations"`
+	MaxDuration    string `json:"maxDuration"`
+	Stagnation     int    `json:"stagnation"`
+}
+
+// OutputConfig names the files the results are written to.
+type OutputConfig struct {
+	// Best receives the fittest individual. The default is best.txt.
+	Best string `json:"best"`
+
+	// Stats receives the statistics of the run, as JSON. The
+	// default is stats.json.
+	Stats string `json:"stats"`
+}
+
+// loadConfig reads a config file, and fills in the defaults.
+func loadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(data)
+}
+
+func parseConfig(data []byte) (*Config, error) {
+	cfg := &Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %s", err)
+	}
+	if err := cfg.defaults(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// defaults fills in the values a config leaves out.
+func (cfg *Config) defaults() error {
+	if cfg.Population == 0 {
+		cfg.Population = 100
+	}
+	if cfg.Params.Selection == "" {
+		cfg.Params.Selection = "tournament"
+	}
+	if cfg.Params.TournamentSize == 0 {
+		cfg.Params.TournamentSize = 3
+	}
+	if cfg.Output.Best == "" {
+		cfg.Output.Best = "best.txt"
+	}
+	if cfg.Output.Stats == "" {
+		cfg.Output.Stats = "stats.json"
+	}
+	if cfg.Population < 1 {
+		return errors.New("population must be positive")
+	}
+	return nil
+}
+
+// problem builds the configured problem, whose individuals draw their
+// random numbers from a source with the given seed, or from the global
+// source if it's zero. Its optimum is +Inf when it isn't known.
+func (cfg *Config) problem(seed int64) (benchmarks.Problem, error) {
+	p, err := cfg.benchmark(seed)
+	if err != nil {
+		return p, err
+	}
+	if seed != 0 {
+		p.Seed(seed)
+	}
+	return p, nil
+}
+
+// benchmark builds the configured problem.
+func (cfg *Config) benchmark(seed int64) (benchmarks.Problem, error) {
+	p := cfg.Problem
+	size := func(def int) int {
+		if p.Size == 0 {
+			return def
+		}
+		return p.Size
+	}
+	k := func(def int) int {
+		if p.K == 0 {
+			return def
+		}
+		return p.K
+	}
+	switch strings.ToLower(p.Name) {
+	case "onemax":
+		return benchmarks.OneMax(size(100)), nil
+	case "trap":
+		if size(100)%k(4) != 0 {
+			return benchmarks.Problem{}, errors.New("trap size must be a multiple of k")
+		}
+		return benchmarks.Trap(k(4), size(100)/k(4)), nil
+	case "deceptive":
+		if size(99)%3 != 0 {
+			return benchmarks.Problem{}, errors.New("deceptive size must be a multiple of 3")
+		}
+		return benchmarks.Deceptive(size(99) / 3), nil
+	case "nk":
+		if k(4) >= size(32) {
+			return benchmarks.Problem{}, errors.New("NK landscapes need k less than the size")
+		}
+		return benchmarks.NK(size(32), k(4), p.Seed), nil
+	case "knapsack":
+		return benchmarks.RandomKnapsack(size(50), p.Seed), nil
+	case "rastrigin":
+		return benchmarks.Rastrigin(size(10)), nil
+	case "rosenbrock":
+		return benchmarks.Rosenbrock(size(10)), nil
+	case "ackley":
+		return benchmarks.Ackley(size(10)), nil
+	case "schwefel":
+		return benchmarks.Schwefel(size(10)), nil
+	case "griewank":
+		return benchmarks.Griewank(size(10)), nil
+	case "zdt1":
+		return benchmarks.ZDT1(size(30)), nil
+	case "zdt2":
+		return benchmarks.ZDT2(size(30)), nil
+	case "zdt3":
+		return benchmarks.ZDT3(size(30)), nil
+	case "zdt4":
+		return benchmarks.ZDT4(size(10)), nil
+	case "zdt6":
+		return benchmarks.ZDT6(size(10)), nil
+	case "dtlz1", "dtlz2":
+		m := p.Objectives
+		if m == 0 {
+			m = 3
+		}
+		if m < 2 || m > size(m+9) {
+			return benchmarks.Problem{}, errors.New("DTLZ problems need at least 2 objectives, and no more than the size")
+		}
+		if strings.ToLower(p.Name) == "dtlz1" {
+			return benchmarks.DTLZ1(size(m+4), m), nil
+		}
+		return benchmarks.DTLZ2(size(m+9), m), nil
+	case "tsp":
+		return tspProblem(p, seed)
+	}
+	return benchmarks.Problem{}, fmt.Errorf("unknown problem %q", p.Name)
+}
+
+// tspProblem loads the configured instance. Its tours are seeded here,
+// as the benchmarks.Problem wrapping them can't reach the instance.
+func tspProblem(p ProblemConfig, seed int64) (benchmarks.Problem, error) {
+	if p.File == "" {
+		return benchmarks.Problem{}, errors.New("tsp problems need a TSPLIB file")
+	}
+	in, err := tsp.Load(p.File)
+	if err != nil {
+		return benchmarks.Problem{}, err
+	}
+	optimum := math.Inf(1)
+	if p.OptimalTour != "" {
+		if err := in.LoadOptimalTour(p.OptimalTour); err != nil {
+			return benchmarks.Problem{}, err
+		}
+		optimum = in.TargetFitness(1e-9)
+	}
+	if seed != 0 {
+		in.Seed(seed)
+	}
+	return benchmarks.Problem{Name: in.Name, Generator: in.Generator(), Optimum: optimum}, nil
+}
+
+// params builds the genetic.Params of the config,
+// for the given problem and initial population.
+func (cfg *Config) params(problem benchmarks.Problem, pop []genetic.Individual) (genetic.Params, error) {
+	out := genetic.Params{
+		Elitism:       cfg.Params.Elitism,
+		Mutation:      cfg.Params.Mutation,
+		Crossover:     cfg.Params.Crossover,
+		Parallelism:   cfg.Params.Parallelism,
+		TargetFitness: problem.Optimum,
+		Seed:          cfg.Params.Seed,
+		InitPop:       pop,
+	}
+	if cfg.Params.TargetFitness != nil {
+		out.TargetFitness = *cfg.Params.TargetFitness
+	}
+	switch strings.ToLower(cfg.Params.Selection) {
+	case "tournament":
+		out.SelectionMethod = genetic.Tournament(cfg.Params.TournamentSize)
+	case "roulette":
+		out.SelectionMethod = genetic.Roulette()
+	default:
+		return out, fmt.Errorf("unknown selection method %q", cfg.Params.Selection)
+	}
+
+	t := cfg.Termination
+	out.Termination = genetic.Termination{
+		MaxGenerations: t.MaxGenerations,
+		MaxEvaluations: t.MaxEvaluations,
+		Stagnation:     t.Stagnation,
+	}
+	if t.MaxDuration != "" {
+		d, err := time.ParseDuration(t.MaxDuration)
+		if err != nil {
+			return out, fmt.Errorf("invalid maximum duration: %s", err)
+		}
+		out.Termination.MaxDuration = d
+	}
+	if math.IsInf(out.TargetFitness, 1) && out.Termination == (genetic.Termination{}) {
+		return out, errors.New("the problem's optimum isn't known, so a target fitness or a termination limit is needed")
+	}
+	return out, nil
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomjcleveland/genetic/benchmarks"
+)
+
+func Test_parseConfig_Empty_Defaults(t *testing.T) {
+	cfg, err := parseConfig([]byte(`{"problem": {"name": "onemax"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 100, cfg.Population)
+	assert.Equal(t, "tournament", cfg.Params.Selection)
+	assert.Equal(t, 3, cfg.Params.TournamentSize)
+	assert.Equal(t, "best.txt", cfg.Output.Best)
+	assert.Equal(t, "stats.json", cfg.Output.Stats)
+}
+
+func Test_parseConfig_Invalid_Error(t *testing.T) {
+	for _, data := range []string{`{`, `{"population": -1}`, `{"population": "ten"}`} {
+		_, err := parseConfig([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func Test_problem_Names_Built(t *testing.T) {
+	for _, name := range []string{"onemax", "trap", "deceptive", "nk", "knapsack", "rastrigin",
+		"rosenbrock", "ackley", "schwefel", "griewank", "zdt1", "zdt2", "zdt3", "zdt4", "zdt6", "dtlz1", "DTLZ2"} {
+		cfg := &Config{Problem: ProblemConfig{Name: name}}
+		p, err := cfg.problem(0)
+		assert.NoError(t, err, name)
+		assert.NotNil(t, p.Generator, name)
+	}
+}
+
+func Test_problem_Invalid_Error(t *testing.T) {
+	tests := []ProblemConfig{
+		{Name: "unknown"},
+		{Name: "trap", Size: 10, K: 4},
+		{Name: "deceptive", Size: 10},
+		{Name: "nk", Size: 4, K: 4},
+		{Name: "dtlz2", Objectives: 1},
+		{Name: "tsp"},
+		{Name: "tsp", File: "missing.tsp"},
+	}
+	for _, test := range tests {
+		cfg := &Config{Problem: test}
+		_, err := cfg.problem(0)
+		assert.Error(t, err, test.Name)
+	}
+}
+
+func Test_problem_TSP_OptimumTarget(t *testing.T) {
+	cfg := &Config{Problem: ProblemConfig{
+		Name:        "tsp",
+		File:        "../../tsp/testdata/burma14.tsp",
+		OptimalTour: "../../tsp/testdata/burma14.opt.tour",
+	}}
+	p, err := cfg.problem(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "burma14", p.Name)
+	assert.InDelta(t, -3323, p.Optimum, 1e-3)
+}
+
+func Test_params_Config_Translated(t *testing.T) {
+	target := 10.0
+	cfg, err := parseConfig([]byte(`{
+		"problem": {"name": "onemax", "size": 20},
+		"params": {"elitism": 2, "mutation": 0.1, "crossover": 0.8, "parallelism": 4, "selection": "roulette", "seed": 7},
+		"termination": {"maxGenerations": 10, "maxDuration": "1m30s", "stagnation": 5}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Params.TargetFitness = &target
+	p, err := cfg.problem(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err := cfg.params(p, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, params.Elitism)
+	assert.Equal(t, 0.1, params.Mutation)
+	assert.Equal(t, 0.8, params.Crossover)
+	assert.Equal(t, 4, params.Parallelism)
+	assert.Equal(t, int64(7), params.Seed)
+	assert.Equal(t, 10.0, params.TargetFitness)
+	assert.NotNil(t, params.SelectionMethod)
+	assert.Equal(t, 10, params.Termination.MaxGenerations)
+	assert.Equal(t, 5, params.Termination.Stagnation)
+	assert.Equal(t, 90.0, params.Termination.MaxDuration.Seconds())
+}
+
+func Test_params_Invalid_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"selection", Config{Params: ParamsConfig{Selection: "best"}}},
+		{"duration", Config{Params: ParamsConfig{Selection: "tournament"}, Termination: TerminationConfig{MaxDuration: "soon"}}},
+		{"no limit", Config{Params: ParamsConfig{Selection: "tournament"}}},
+	}
+	for _, test := range tests {
+		_, err := test.cfg.params(benchmarks.Problem{Optimum: math.Inf(1)}, nil)
+		assert.Error(t, err, test.name)
+	}
+}
+
+func Test_run_OneMax_OutputsWritten(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genetic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cfg, err := parseConfig([]byte(`{
+		"problem": {"name": "onemax", "size": 20},
+		"population": 50,
+		"params": {"elitism": 1, "mutation": 0.05, "crossover": 0.9, "seed": 3},
+		"termination": {"maxGenerations": 200}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Output.Best = filepath.Join(dir, "best.txt")
+	cfg.Output.Stats = filepath.Join(dir, "stats.json")
+
+	var stderr bytes.Buffer
+	result, err := run(context.Background(), cfg, &stderr)
+	assert.NoError(t, err)
+	assert.Equal(t, "target", result.Stopped)
+	assert.Equal(t, 20.0, result.BestScore)
+	assert.True(t, strings.HasPrefix(stderr.String(), "generation 0: best"))
+
+	best, err := ioutil.ReadFile(cfg.Output.Best)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("1", 20)+"\n", string(best))
+
+	data, err := ioutil.ReadFile(cfg.Output.Stats)
+	assert.NoError(t, err)
+	var written Result
+	assert.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, int64(3), written.Seed)
+	assert.Equal(t, len(result.History), len(written.History))
+}
+
+func Test_run_Terminated_OutputsWritten(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genetic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cfg := &Config{
+		Problem:     ProblemConfig{Name: "rastrigin", Size: 5},
+		Population:  20,
+		Params:      ParamsConfig{Mutation: 0.1, Crossover: 0.9, Selection: "tournament", TournamentSize: 2},
+		Termination: TerminationConfig{MaxGenerations: 3},
+		Output:      OutputConfig{Best: filepath.Join(dir, "best.txt"), Stats: filepath.Join(dir, "stats.json")},
+	}
+	result, err := run(context.Background(), cfg, ioutil.Discard)
+	assert.NoError(t, err)
+	assert.Equal(t, "terminated", result.Stopped)
+	assert.NotZero(t, result.Seed)
+	_, err = os.Stat(cfg.Output.Stats)
+	assert.NoError(t, err)
+}
+
+func Test_run_SameSeed_SameSearch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genetic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	var histories [][]float64
+	for i := 0; i < 2; i++ {
+		cfg := &Config{
+			Problem:     ProblemConfig{Name: "rastrigin", Size: 5},
+			Population:  20,
+			Params:      ParamsConfig{Mutation: 0.1, Crossover: 0.9, Selection: "tournament", TournamentSize: 2, Seed: 11},
+			Termination: TerminationConfig{MaxGenerations: 5},
+			Output:      OutputConfig{Best: filepath.Join(dir, "best.txt"), Stats: filepath.Join(dir, "stats.json")},
+		}
+		result, err := run(context.Background(), cfg, ioutil.Discard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var best []float64
+		for _, s := range result.History {
+			best = append(best, s.Best, s.Mean)
+		}
+		histories = append(histories, best)
+	}
+	assert.Equal(t, histories[0], histories[1])
+	assert.Equal(t, &logged, log.Writer())
+	assert.Empty(t, logged.String())
+}
+
+func Test_dispatch_UnknownCommand_Error(t *testing.T) {
+	var stderr bytes.Buffer
+	assert.Error(t, dispatch([]string{"fly"}, &stderr))
+	assert.Contains(t, stderr.String(), "usage")
+	assert.Error(t, dispatch(nil, &stderr))
+	assert.Error(t, dispatch([]string{"run"}, &stderr))
+}
+
+func Test_runExperiment_TwoConfigs_ResultsWritten(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genetic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cfg, err := parseExperimentConfig([]byte(`{
+		"configs": [
+			{"name": "tournament", "problem": {"name": "onemax", "size": 20}, "population": 30,
+			 "params": {"mutation": 0.05, "crossover": 0.9, "tournamentSize": 4}, "termination": {"maxGenerations": 10}},
+			{"name": "roulette", "problem": {"name": "onemax", "size": 20}, "population": 30,
+			 "params": {"mutation": 0.05, "crossover": 0.9, "selection": "roulette"}, "termination": {"maxGenerations": 10}}
+		],
+		"repetitions": 3,
+		"parallelism": 2
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "results", cfg.Output)
+	cfg.Output = filepath.Join(dir, "results")
+
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	var stderr bytes.Buffer
+	results, err := runExperiment(context.Background(), cfg, &stderr)
+	assert.NoError(t, err)
+	assert.Equal(t, &logged, log.Writer())
+	assert.Empty(t, logged.String())
+	assert.Len(t, results.Runs, 6)
+	assert.Equal(t, "4", results.Manifest.Configs[0].Labels["tournamentSize"])
+	assert.Contains(t, stderr.String(), "tournament vs roulette")
+	for _, name := range []string{"runs.csv", "summary.csv", "comparisons.csv", "manifest.json"} {
+		_, err := os.Stat(filepath.Join(cfg.Output, name))
+		assert.NoError(t, err, name)
+	}
+}
+
+func Test_parseExperimentConfig_InvalidConfig_Error(t *testing.T) {
+	_, err := parseExperimentConfig([]byte(`{"configs": [{"name": "a", "population": -1}]}`))
+	assert.Error(t, err)
+
+	cfg, err := parseExperimentConfig([]byte(`{"configs": [{"name": "a", "problem": {"name": "unknown"}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cfg.params()
+	assert.Error(t, err)
+}
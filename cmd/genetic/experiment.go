@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+
+	"github.com/tomjcleveland/genetic"
+	"github.com/tomjcleveland/genetic/experiment"
+)
+
+// ExperimentConfig describes an experiment: named run configs, each
+// run several times with the same seeds.
+type ExperimentConfig struct {
+	Configs []NamedConfig `json:"configs"`
+
+	// Repetitions is how many times every config is run.
+	// The default is 10.
+	Repetitions int `json:"repetitions"`
+
+	// Seed is the seed of every config's first run, with the
+	// following runs using the following seeds. The default is 1.
+	Seed int64 `json:"seed"`
+
+	// Parallelism is how many runs are performed at once.
+	Parallelism int `json:"parallelism"`
+
+	// Output is the directory the results are written to.
+	// The default is results.
+	Output string `json:"output"`
+}
+
+// NamedConfig is a run config with a name. Its seed and
+// output files are ignored.
+type NamedConfig struct {
+	Name string `json:"name"`
+	Config
+}
+
+func parseExperimentConfig(data []byte) (*ExperimentConfig, error) {
+	cfg := &ExperimentConfig{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %s", err)
+	}
+	if cfg.Output == "" {
+		cfg.Output = "results"
+	}
+	for i := range cfg.Configs {
+		if err := cfg.Configs[i].defaults(); err != nil {
+			return nil, fmt.Errorf("config %q: %s", cfg.Configs[i].Name, err)
+		}
+	}
+	return cfg, nil
+}
+
+// params builds the parameters of the experiment.
+func (cfg *ExperimentConfig) params() (experiment.Params, error) {
+	out := experiment.Params{
+		Repetitions: cfg.Repetitions,
+		Seed:        cfg.Seed,
+		Parallelism: cfg.Parallelism,
+	}
+	for _, c := range cfg.Configs {
+		problem, err := c.problem(0)
+		if err != nil {
+			return out, fmt.Errorf("config %q: %s", c.Name, err)
+		}
+		params, err := c.params(problem, nil)
+		if err != nil {
+			return out, fmt.Errorf("config %q: %s", c.Name, err)
+		}
+		params.PopulationSize = c.Population
+		// The controllers log every generation, which the observer replaces.
+		params.Logger = log.New(ioutil.Discard, "", 0)
+		labels := map[string]string{"problem": problem.Name, "selection": c.Params.Selection}
+		if c.Params.Selection == "tournament" {
+			labels["tournamentSize"] = strconv.Itoa(c.Params.TournamentSize)
+		}
+		out.Configs = append(out.Configs, experiment.Config{
+			Name:       c.Name,
+			Params:     params,
+			Population: c.initializer(),
+			Labels:     labels,
+		})
+	}
+	return out, nil
+}
+
+// initializer builds the population of a run from a problem of its
+// own, so that the individuals draw from a source seeded by the run.
+func (c NamedConfig) initializer() genetic.Initializer {
+	return func(size int, rng *rand.Rand) ([]genetic.Individual, error) {
+		problem, err := c.problem(rng.Int63())
+		if err != nil {
+			return nil, err
+		}
+		return problem.Population(size)
+	}
+}
+
+// experimentCommand implements "genetic experiment <experiment.json>".
+func experimentCommand(args []string, stderr io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: genetic experiment <experiment.json>")
+	}
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	cfg, err := parseExperimentConfig(data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	_, err = runExperiment(ctx, cfg, stderr)
+	return err
+}
+
+// runExperiment performs the experiment, reporting every run to stderr,
+// and writes the results to the output directory. A cancelled experiment
+// still has the runs it finished written.
+func runExperiment(ctx context.Context, cfg *ExperimentConfig, stderr io.Writer) (*experiment.Results, error) {
+	params, err := cfg.params()
+	if err != nil {
+		return nil, err
+	}
+	// Runs finish on several goroutines at once.
+	var mu sync.Mutex
+	params.Observer = func(r experiment.Run) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(stderr, "%s repetition %d (seed %d): best %.6g, success %t, evaluations %d\n",
+			r.Config, r.Repetition, r.Seed, r.Best, r.Success, r.Evaluations)
+	}
+	e, err := experiment.NewExperiment(params)
+	if err != nil {
+		return nil, err
+	}
+
+	e.Start(ctx)
+	if err := e.Wait(); err != nil && err != genetic.ErrContextCancelled {
+		return nil, err
+	}
+	results := e.Results()
+	for _, s := range results.Summaries {
+		fmt.Fprintf(stderr, "%s: %d runs, success rate %.2f, mean best %.6g, median best %.6g\n",
+			s.Config, s.Runs, s.SuccessRate, s.MeanBest, s.MedianBest)
+	}
+	for _, c := range results.Comparisons {
+		fmt.Fprintf(stderr, "%s vs %s: Mann-Whitney p %.4g, effect %.3f; Wilcoxon p %.4g, effect %.3f\n",
+			c.A, c.B, c.MannWhitney.P, c.MannWhitney.Effect, c.Wilcoxon.P, c.Wilcoxon.Effect)
+	}
+	if err := results.Save(cfg.Output); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
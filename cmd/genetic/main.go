@@ -0,0 +1,80 @@
+// Command genetic runs the built-in benchmark problems from a JSON
+// config file:
+//
+//	genetic run config.json
+//
+// A config names the problem and sets the search's parameters and
+// termination limits, for example:
+//
+//	{
+//	  "problem": {"name": "trap", "size": 100, "k": 4},
+//	  "population": 200,
+//	  "params": {"elitism": 2, "mutation": 0.02, "crossover": 0.9, "selection": "tournament", "seed": 1},
+//	  "termination": {"maxGenerations": 500, "maxDuration": "1m"},
+//	  "output": {"best": "best.txt", "stats": "stats.json"}
+//	}
+//
+// Only the built-in problems can be named; there's no way to describe a
+// genome type of your own in a config. The seed drives both the search
+// and the problem's individuals, so a seeded run can be repeated.
+//
+// Progress is written to stderr, the fittest individual to the best file
+// and the statistics of every generation, as JSON, to the stats file.
+//
+// An experiment compares named configs, each a config as above, by
+// running each of them several times with the same seeds:
+//
+//	genetic experiment experiment.json
+//
+//	{
+//	  "configs": [
+//	    {"name": "tournament", "problem": {"name": "onemax"}, "params": {"selection": "tournament", "tournamentSize": 10}, "termination": {"maxGenerations": 100}},
+//	    {"name": "roulette", "problem": {"name": "onemax"}, "params": {"selection": "roulette"}, "termination": {"maxGenerations": 100}}
+//	  ],
+//	  "repetitions": 30,
+//	  "parallelism": 4,
+//	  "output": "results"
+//	}
+//
+// The runs, a summary of every config, pairwise Mann–Whitney U and
+// Wilcoxon signed-rank tests, and a manifest of the parameters, seeds
+// and build are written to the output directory.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: genetic <command> [arguments]
+
+commands:
+  run <config.json>                 run the search described by a config file
+  experiment <experiment.json>      compare configs over repeated, seeded runs
+`
+
+func main() {
+	if err := dispatch(os.Args[1:], os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func dispatch(args []string, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("no command given")
+	}
+	switch args[0] {
+	case "run":
+		return runCommand(args[1:], stderr)
+	case "experiment":
+		return experimentCommand(args[1:], stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stderr, usage)
+		return nil
+	}
+	fmt.Fprint(stderr, usage)
+	return fmt.Errorf("unknown command %q", args[0])
+}
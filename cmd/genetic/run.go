@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Result is what a run writes to the stats file.
+type Result struct {
+	Problem   string  `json:"problem"`
+	Seed      int64   `json:"seed"`
+	BestScore float64 `json:"bestScore"`
+
+	// Stopped is why the search stopped: "target", "terminated"
+	// or "cancelled".
+	Stopped string          `json:"stopped"`
+	History []genetic.Stats `json:"history"`
+}
+
+// runCommand implements "genetic run <config.json>".
+func runCommand(args []string, stderr io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: genetic run <config.json>")
+	}
+	cfg, err := loadConfig(args[0])
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	_, err = run(ctx, cfg, stderr)
+	return err
+}
+
+// run performs the search described by cfg, reporting every generation
+// to stderr, and writes the fittest individual and the run's statistics
+// to the configured files. A search that is terminated or cancelled
+// still has its results written.
+func run(ctx context.Context, cfg *Config, stderr io.Writer) (*Result, error) {
+	seed := cfg.Params.Seed
+	if seed == 0 {
+		seed = time.Now().UTC().UnixNano()
+	}
+
+	problem, err := cfg.problem(seed)
+	if err != nil {
+		return nil, err
+	}
+	pop, err := problem.Population(cfg.Population)
+	if err != nil {
+		return nil, err
+	}
+	params, err := cfg.params(problem, pop)
+	if err != nil {
+		return nil, err
+	}
+	params.Seed = seed
+	// The controller logs every generation, which the observer replaces.
+	params.Logger = log.New(ioutil.Discard, "", 0)
+	params.Observer = func(s genetic.Stats) {
+		fmt.Fprintf(stderr, "generation %d: best %.6g, mean %.6g, evaluations %d, elapsed %s\n",
+			s.Generation, s.Best, s.Mean, s.Evaluations, s.Elapsed.Round(time.Millisecond))
+	}
+
+	c, err := genetic.NewController(params)
+	if err != nil {
+		return nil, err
+	}
+	c.Start(ctx)
+	result := &Result{Problem: problem.Name, Seed: seed, Stopped: "target"}
+	switch err := c.Wait(); err {
+	case nil:
+	case genetic.ErrTerminated:
+		result.Stopped = "terminated"
+	case genetic.ErrContextCancelled:
+		result.Stopped = "cancelled"
+	default:
+		return nil, err
+	}
+
+	fittest, err := c.Fittest()
+	if err != nil {
+		return nil, err
+	}
+	result.History = c.Stats()
+	if len(result.History) > 0 {
+		result.BestScore = result.History[len(result.History)-1].Best
+	}
+	fmt.Fprintf(stderr, "stopped (%s) with best score %.6g\n", result.Stopped, result.BestScore)
+
+	if err := ioutil.WriteFile(cfg.Output.Best, []byte(fmt.Sprintln(fittest)), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write the fittest individual: %s", err)
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(cfg.Output.Stats, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write the stats: %s", err)
+	}
+	return result, nil
+}
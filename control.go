@@ -0,0 +1,77 @@
+package genetic
+
+import "context"
+
+// Pause holds the search before it breeds its next generation,
+// until Resume or Cancel is called, or its context is cancelled.
+func (c *Controller) Pause() {
+	c.control.Lock()
+	defer c.control.Unlock()
+	if !c.paused {
+		c.paused = true
+		c.resume = make(chan struct{})
+	}
+}
+
+// Resume continues a paused search.
+func (c *Controller) Resume() {
+	c.control.Lock()
+	defer c.control.Unlock()
+	if c.paused {
+		c.paused = false
+		close(c.resume)
+	}
+}
+
+// Paused reports whether the search has been paused.
+func (c *Controller) Paused() bool {
+	c.control.Lock()
+	defer c.control.Unlock()
+	return c.paused
+}
+
+// Cancel stops the search before it breeds its next generation, as
+// cancelling its context does, so Wait returns ErrContextCancelled.
+func (c *Controller) Cancel() {
+	c.cancelOnce.Do(func() { close(c.cancel) })
+}
+
+// Done returns a channel that's closed once the search is over,
+// before Wait returns.
+func (c *Controller) Done() <-chan struct{} {
+	return c.done
+}
+
+// Params returns the parameters of the search, with the defaults
+// NewController filled in, and the current PopulationSize and
+// Offspring if Sizing has changed them.
+func (c *Controller) Params() Params {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.params
+}
+
+// checkpoint returns ErrContextCancelled if the search has been
+// cancelled, and blocks while it is paused.
+func (c *Controller) checkpoint(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ErrContextCancelled
+		case <-c.cancel:
+			return ErrContextCancelled
+		default:
+		}
+		c.control.Lock()
+		paused, resume := c.paused, c.resume
+		c.control.Unlock()
+		if !paused {
+			return nil
+		}
+		select {
+		case <-resume:
+		case <-ctx.Done():
+		case <-c.cancel:
+		}
+	}
+}
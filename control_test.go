@@ -0,0 +1,69 @@
+package genetic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pausable(t *testing.T) *Controller {
+	ctrl, err := NewController(Params{
+		TargetFitness:   10,
+		SelectionMethod: Tournament(2),
+		InitPop:         make([]fakeIndividual, 4),
+		Termination:     Termination{MaxGenerations: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctrl
+}
+
+func Test_Controller_Paused_HeldUntilResumed(t *testing.T) {
+	ctrl := pausable(t)
+	ctrl.Pause()
+	assert.True(t, ctrl.Paused())
+	ctrl.Start(context.Background())
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Len(t, ctrl.Stats(), 1)
+	select {
+	case <-ctrl.Done():
+		t.Fatal("paused search finished")
+	default:
+	}
+
+	ctrl.Resume()
+	assert.False(t, ctrl.Paused())
+	assert.Equal(t, ErrTerminated, ctrl.Wait())
+	assert.Len(t, ctrl.Stats(), 4)
+	<-ctrl.Done()
+}
+
+func Test_Controller_CancelWhilePaused_ErrContextCancelled(t *testing.T) {
+	ctrl := pausable(t)
+	ctrl.Pause()
+	ctrl.Start(context.Background())
+	time.Sleep(10 * time.Millisecond)
+	ctrl.Cancel()
+	ctrl.Cancel()
+	assert.Equal(t, ErrContextCancelled, ctrl.Wait())
+}
+
+func Test_Controller_ContextCancelledWhilePaused_ErrContextCancelled(t *testing.T) {
+	ctrl := pausable(t)
+	ctrl.Pause()
+	ctx, cancel := context.WithCancel(context.Background())
+	ctrl.Start(ctx)
+	cancel()
+	assert.Equal(t, ErrContextCancelled, ctrl.Wait())
+}
+
+func Test_Controller_Params_DefaultsFilledIn(t *testing.T) {
+	params := pausable(t).Params()
+	assert.Equal(t, 4, params.PopulationSize)
+	assert.Equal(t, 2, params.ParentsPerMating)
+	assert.Equal(t, 4, params.Offspring)
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"math/rand"
+	"sync"
 	"time"
 
 	"context"
@@ -30,23 +32,144 @@ type Params struct {
 	Mutation float64
 
 	// Should we change the mutation rate based on search convergence?
+	// This is shorthand for a MutationSchedule of
+	// SrinivasPatnaik(Mutation, Mutation).
 	AdaptiveMutation bool
 
+	// MutationSchedule and CrossoverSchedule vary the mutation and
+	// crossover rates as the search progresses. By default, the rates
+	// stay at Mutation and Crossover.
+	MutationSchedule  RateSchedule
+	CrossoverSchedule RateSchedule
+
 	// 0-1; What portion of the population should undergo crossover?
 	Crossover float64
 
+	// How many parents take part in a single mating? The default is two.
+	// More than two requires the Individuals to implement MultiCrossover,
+	// unless CrossoverOperators mate them.
+	ParentsPerMating int
+
+	// How many offspring does a single mating produce? The default is one.
+	// Two children from two parents uses PairCrossover when the
+	// Individuals implement it.
+	OffspringPerMating int
+
 	// What is the target fitness score, at which point the
 	// algorithm will terminate?
 	TargetFitness float64
 
+	// Termination stops the search early, when it has run for too long
+	// without meeting TargetFitness. The default is no limit.
+	Termination Termination
+
+	// Observer, if set, is told about every generation.
+	Observer Observer
+
+	// Logger receives the fittest individual of every generation, and
+	// the news that the target was met. The default is the standard
+	// logger; a Logger writing to ioutil.Discard keeps the search quiet.
+	Logger *log.Logger
+
+	// History, if set, records every generation to a stream.
+	History *HistoryWriter
+
+	// HallOfFame is how many of the fittest distinct individuals ever
+	// seen to keep, for Controller.HallOfFame. Zero keeps none.
+	HallOfFame int
+
+	// HallOfFameDuplicate decides which individuals are the same
+	// solution, of which the hall of fame keeps only the fittest. The
+	// default compares them with reflect.DeepEqual.
+	HallOfFameDuplicate Duplicate
+
+	// HallOfFameElites is how many of the fittest members of the hall
+	// of fame are put back into every generation, in place of its least
+	// fit individuals, if they were lost. It can't exceed HallOfFame.
+	HallOfFameElites int
+
+	// Lineage, if true, records the parents, operator, generation of
+	// birth and score of every individual that joins the population,
+	// for Controller.Lineage. Records are never forgotten, so memory
+	// use grows with the length of the search.
+	Lineage bool
+
+	// Metrics, if set, collects measurements of the search for
+	// monitoring, with Prometheus or expvar.
+	Metrics *Metrics
+
 	// Parallelism dictates how many goroutines will be used to calculate
 	// the fitness of a population. The default is one.
 	Parallelism int
 
+	// Seed, if not zero, seeds the controller's random number generator,
+	// which drives selection, mating and the choice of operators. The
+	// Individuals' own randomness isn't affected.
+	Seed int64
+
 	// SelectionMethod is the method by which the genetic algorithm
 	// chooses a partner for crossover.
 	SelectionMethod SelectionMethod
 
+	// MatingScheme chooses all the parents of a mating. The default
+	// picks each parent with SelectionMethod.
+	MatingScheme MatingScheme
+
+	// PopulationSize (μ) is how many individuals survive each generation.
+	// The default is len(InitPop), and it can't be larger than that; a
+	// smaller value keeps only the fittest of the initial population.
+	// It's required with an Initializer, which builds at least that many.
+	PopulationSize int
+
+	// Offspring (λ) is how many children are bred each generation. The
+	// default is PopulationSize-Elitism, which together with MuCommaLambda
+	// gives a classic generational genetic algorithm.
+	Offspring int
+
+	// Survivors decides who makes it into the next generation: only the
+	// offspring (MuCommaLambda, the default) or parents and offspring
+	// alike (MuPlusLambda). The elite always survive.
+	Survivors SurvivorSelection
+
+	// MaxAge is how many generations an individual can survive before it
+	// is removed regardless of its fitness. Zero means there's no limit.
+	MaxAge int
+
+	// Sizing changes the population size as the search progresses. The
+	// default keeps it at PopulationSize. Offspring is scaled with it.
+	Sizing SizingPolicy
+
+	// MinPopulationSize and MaxPopulationSize bound the sizes Sizing can
+	// choose. The minimum defaults to two, and a zero maximum means
+	// there's no upper limit.
+	MinPopulationSize int
+	MaxPopulationSize int
+
+	// Generator creates new individuals when the population grows.
+	// It is required when Sizing is set.
+	Generator Generator
+
+	// CrossoverOperators and MutationOperators, when given, are used
+	// instead of the Individuals' own Crossover() and Mutate() methods.
+	// For every mating, OperatorSelection chooses which one to apply.
+	CrossoverOperators []CrossoverOperator
+	MutationOperators  []MutationOperator
+
+	// OperatorSelection chooses between registered operators. The
+	// default picks each operator with equal probability.
+	OperatorSelection OperatorSelection
+
+	// Breeding, if set, replaces the crossover and mutation steps with
+	// a Breeder, such as DifferentialEvolution. Every trial it breeds
+	// competes one-to-one with its parent, so the mating, offspring,
+	// survivor and rate parameters above don't apply.
+	Breeding Breeding
+
+	// Initializer, if set, builds an initial population of at least
+	// PopulationSize individuals in place of InitPop, drawing
+	// on the random source seeded by Seed.
+	Initializer Initializer
+
 	// The initial population. Must be a slice of Individuals.
 	InitPop interface{}
 }
@@ -54,6 +177,24 @@ type Params struct {
 type indWithScore struct {
 	Individual
 	score float64
+
+	// id identifies the individual in the population's pedigree;
+	// zero means it isn't being tracked.
+	id uint64
+
+	// born is the generation in which the individual was bred.
+	born int
+
+	// operator is how the individual was bred, which is
+	// recorded in the lineage, when it is tracked.
+	operator string
+
+	// crossoverOp and mutationOp are the registered operators that
+	// bred the individual, or -1, and parentScore is the score of its
+	// fittest parent. They let the controller credit the operators.
+	crossoverOp int
+	mutationOp  int
+	parentScore float64
 }
 
 type pairs []indWithScore
@@ -65,8 +206,37 @@ func (p pairs) Less(i, j int) bool { return p[i].score < p[j].score }
 // Controller coordinates the running of the
 // genetic algorithm.
 type Controller struct {
-	params     Params
-	population *Population
+	params      Params
+	population  *Population
+	generation  int
+	evaluations int
+	stats       []Stats
+	crossovers  *operatorSet
+	mutations   *operatorSet
+	breeder     Breeder
+	lineage     *Lineage
+	hallOfFame  *hallOfFame
+	bestEver    *indWithScore
+
+	crossoverRate *rateTracker
+	mutationRate  *rateTracker
+	successRate   float64
+	start         time.Time
+
+	// mu guards population, stats and the sizes Sizing changes in
+	// params, which are read while the search runs in its own goroutine.
+	mu sync.RWMutex
+
+	// control guards paused and resume, which
+	// Pause and Resume use to hold the search.
+	control sync.Mutex
+	paused  bool
+	resume  chan struct{}
+
+	// cancel is closed by Cancel, and done once the search is over.
+	cancel     chan struct{}
+	cancelOnce sync.Once
+	done       chan struct{}
 
 	err chan error
 }
@@ -75,25 +245,210 @@ type Controller struct {
 // if any of the input parameters are not allowed.
 func NewController(params Params) (*Controller, error) {
 	if !isProb(params.Crossover) {
-		return nil, errors.New("crossover factor must be between 0 and 1, inclusive")
+		return nil, invalid("Crossover", ErrOutOfRange, "must be between 0 and 1, inclusive")
 	}
 	if params.Elitism < 0 {
-		return nil, errors.New("elitism factor must be greater than 0")
+		return nil, invalid("Elitism", ErrOutOfRange, "cannot be negative")
 	}
 	if !isProb(params.Mutation) {
-		return nil, errors.New("mutation factor must be between 0 and 1, inclusive")
+		return nil, invalid("Mutation", ErrOutOfRange, "must be between 0 and 1, inclusive")
 	}
-	if params.SelectionMethod == nil {
-		return nil, errors.New("selection method cannot be nil")
+	if params.Parallelism < 0 {
+		return nil, invalid("Parallelism", ErrOutOfRange, "cannot be negative")
 	}
-	pop, err := NewPopulation(params.InitPop)
+	if err := params.Termination.validate(); err != nil {
+		return nil, err
+	}
+	if params.Survivors != MuCommaLambda && params.Survivors != MuPlusLambda {
+		return nil, invalid("Survivors", ErrOutOfRange, "unknown survivor selection %d", params.Survivors)
+	}
+	if params.SelectionMethod == nil && params.MatingScheme == nil && params.Breeding == nil {
+		return nil, invalid("SelectionMethod", ErrRequired, "a selection method, mating scheme or breeding is needed")
+	}
+	schemeField := "MatingScheme"
+	if params.MatingScheme == nil && params.SelectionMethod != nil {
+		params.MatingScheme = SelectionMating(params.SelectionMethod)
+		schemeField = "SelectionMethod"
+	}
+	if params.ParentsPerMating == 0 {
+		params.ParentsPerMating = 2
+	}
+	if params.ParentsPerMating < 2 {
+		return nil, invalid("ParentsPerMating", ErrOutOfRange, "must be at least 2")
+	}
+	if params.OffspringPerMating == 0 {
+		params.OffspringPerMating = 1
+	}
+	if params.OffspringPerMating < 1 {
+		return nil, invalid("OffspringPerMating", ErrOutOfRange, "must be at least 1")
+	}
+	if params.AdaptiveMutation && params.MutationSchedule == nil {
+		params.MutationSchedule = SrinivasPatnaik(params.Mutation, params.Mutation)
+	}
+	if params.MaxAge < 0 {
+		return nil, invalid("MaxAge", ErrOutOfRange, "cannot be negative")
+	}
+	if params.Sizing != nil && params.Generator == nil {
+		return nil, invalid("Generator", ErrRequired, "a generator is required to resize the population")
+	}
+	if params.MinPopulationSize == 0 {
+		params.MinPopulationSize = 2
+	}
+	if params.MinPopulationSize < 1 {
+		return nil, invalid("MinPopulationSize", ErrOutOfRange, "must be at least 1")
+	}
+	if params.MaxPopulationSize != 0 && params.MaxPopulationSize < params.MinPopulationSize {
+		return nil, invalid("MaxPopulationSize", ErrConflict, "cannot be smaller than the minimum, %d", params.MinPopulationSize)
+	}
+	rng := rand.New(rand.NewSource(rand.Int63()))
+	if params.Seed != 0 {
+		rng = rand.New(rand.NewSource(params.Seed))
+	}
+	initPop := params.InitPop
+	if params.Initializer != nil {
+		if params.InitPop != nil {
+			return nil, invalid("InitPop", ErrConflict, "cannot be set along with an initializer")
+		}
+		if params.PopulationSize < 1 {
+			return nil, invalid("PopulationSize", ErrRequired, "must be at least 1 for the initializer to build")
+		}
+		inds, err := params.Initializer(params.PopulationSize, rng)
+		if err != nil {
+			return nil, invalid("Initializer", err, "failed to initialize population: %s", err)
+		}
+		if len(inds) < params.PopulationSize {
+			return nil, invalid("Initializer", ErrConflict, "built %d individuals for a population of %d", len(inds), params.PopulationSize)
+		}
+		initPop = inds
+	}
+	pop, err := NewPopulation(initPop)
+	if err != nil {
+		return nil, invalid("InitPop", err, "failed to initialize population: %s", err)
+	}
+	pop.rng = rng
+	pop.logger = params.Logger
+	var scheme query
+	if params.MatingScheme != nil {
+		scheme = pop.describe(params.MatingScheme, params.ParentsPerMating)
+	}
+	depth := scheme.ancestry
+	if params.Lineage && depth < 1 {
+		// The lineage copies the parents of every newcomer
+		// from the pedigree in the generation it's born.
+		depth = 1
+	}
+	pop.family = newPedigree(depth)
+	if params.PopulationSize == 0 {
+		params.PopulationSize = len(pop.pop)
+	}
+	if params.PopulationSize < 1 || params.PopulationSize > len(pop.pop) {
+		return nil, invalid("PopulationSize", ErrExceedsPopulation, "must be between 1 and %d, the size of the initial population", len(pop.pop))
+	}
+	if params.Elitism > params.PopulationSize {
+		return nil, invalid("Elitism", ErrExceedsPopulation, "cannot be larger than the population size, %d", params.PopulationSize)
+	}
+	if scheme.err != nil {
+		return nil, scheme.err
+	}
+	if size := scheme.minPopulation; params.Breeding == nil && size > 0 {
+		if size > params.PopulationSize {
+			return nil, invalid(schemeField, ErrExceedsPopulation, "needs a population of %d, larger than the population size, %d", size, params.PopulationSize)
+		}
+		if params.Sizing != nil && size > params.MinPopulationSize {
+			return nil, invalid("MinPopulationSize", ErrConflict, "cannot be smaller than the %d members the selection needs", size)
+		}
+	}
+	if params.Offspring == 0 {
+		params.Offspring = params.PopulationSize - params.Elitism
+		if params.Offspring < 1 {
+			params.Offspring = 1
+		}
+	}
+	if params.Offspring < 1 {
+		return nil, invalid("Offspring", ErrOutOfRange, "must be at least 1")
+	}
+	if params.Survivors == MuCommaLambda && params.Elitism+params.Offspring < params.PopulationSize {
+		return nil, invalid("Offspring", ErrConflict, "elitism plus offspring must be at least the population size when only offspring survive")
+	}
+	if _, ok := pop.pop[0].Individual.(MultiCrossover); !ok && params.ParentsPerMating > 2 && len(params.CrossoverOperators) == 0 {
+		return nil, invalid("ParentsPerMating", ErrUnsupported, "%T must implement MultiCrossover to mate more than 2 parents", pop.pop[0].Individual)
+	}
+	for i := range pop.pop {
+		pop.pop[i].id = pop.family.add(nil, 0)
+		pop.pop[i].operator = "initial"
+	}
+	var crossoverNames, mutationNames []string
+	for _, op := range params.CrossoverOperators {
+		if op.Crossover == nil {
+			return nil, invalid("CrossoverOperators", ErrRequired, "crossover operator %q has no Crossover function", op.Name)
+		}
+		crossoverNames = append(crossoverNames, op.Name)
+	}
+	for _, op := range params.MutationOperators {
+		if op.Mutate == nil {
+			return nil, invalid("MutationOperators", ErrRequired, "mutation operator %q has no Mutate function", op.Name)
+		}
+		mutationNames = append(mutationNames, op.Name)
+	}
+	crossovers, err := newOperatorSet(crossoverNames, params.OperatorSelection)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize population: %s", err)
+		return nil, invalid("CrossoverOperators", err, "%s", err)
+	}
+	mutations, err := newOperatorSet(mutationNames, params.OperatorSelection)
+	if err != nil {
+		return nil, invalid("MutationOperators", err, "%s", err)
+	}
+	var breeder Breeder
+	if params.Breeding != nil {
+		breeder, err = params.Breeding()
+		if err != nil {
+			return nil, invalid("Breeding", err, "%s", err)
+		}
+	}
+	if b, ok := breeder.(populous); ok {
+		size := b.minPopulation()
+		if size > params.PopulationSize {
+			return nil, invalid("Breeding", ErrExceedsPopulation, "needs a population of %d, larger than the population size, %d", size, params.PopulationSize)
+		}
+		if params.Sizing != nil && size > params.MinPopulationSize {
+			return nil, invalid("MinPopulationSize", ErrConflict, "cannot be smaller than the %d members the breeding needs", size)
+		}
+	}
+	if params.HallOfFame < 0 {
+		return nil, invalid("HallOfFame", ErrOutOfRange, "cannot be negative")
+	}
+	if params.HallOfFameElites < 0 {
+		return nil, invalid("HallOfFameElites", ErrOutOfRange, "cannot be negative")
+	}
+	if params.HallOfFameElites > params.HallOfFame {
+		return nil, invalid("HallOfFameElites", ErrConflict, "cannot outnumber the hall of fame, %d", params.HallOfFame)
+	}
+	if params.HallOfFameElites > params.PopulationSize {
+		return nil, invalid("HallOfFameElites", ErrExceedsPopulation, "cannot be larger than the population size, %d", params.PopulationSize)
+	}
+	params.Metrics.setWorkers(params.Parallelism)
+	var hall *hallOfFame
+	if params.HallOfFame > 0 {
+		hall = newHallOfFame(params.HallOfFame, params.HallOfFameDuplicate)
+	}
+	var lineage *Lineage
+	if params.Lineage {
+		lineage = newLineage()
 	}
 	return &Controller{
 		params:     params,
 		population: pop,
+		crossovers: crossovers,
+		mutations:  mutations,
+		breeder:    breeder,
+		lineage:    lineage,
+		hallOfFame: hall,
+		cancel:     make(chan struct{}),
+		done:       make(chan struct{}),
 		err:        make(chan error),
+
+		crossoverRate: newRateTracker(params.CrossoverSchedule, params.Crossover),
+		mutationRate:  newRateTracker(params.MutationSchedule, params.Mutation),
 	}, nil
 }
 
@@ -102,45 +457,120 @@ func isProb(d float64) bool {
 }
 
 func (c *Controller) run(ctx context.Context) error {
+	c.start = time.Now()
+
 	// Score initial population
-	err := c.population.scoreAndSort(c.params.Parallelism)
+	initPop, err := calculateFitnessConcurrently(c.population.pop, c.params.Parallelism, c.params.Metrics)
 	if err != nil {
 		return err
 	}
+	c.evaluations += len(initPop)
+	if len(initPop) > c.params.PopulationSize {
+		initPop = initPop[:c.params.PopulationSize]
+	}
+	c.setPopulation(c.population.next(initPop))
 
 	// Loop through generations until target fitness is acheived.
 	for !c.population.TargetMet(c.params.TargetFitness) {
-		select {
-		case <-ctx.Done():
-			return ErrContextCancelled
-		default:
-		}
-		fittestScore, err := c.population.FittestScore()
-		if err != nil {
+		if err := c.checkpoint(ctx); err != nil {
 			return err
 		}
-		fittest, err := c.population.Fittest()
-		if err != nil {
-			return err
-		}
-		log.Printf("Fittest: %v", fittest)
-		log.Printf("Fittest Score: %.4f", fittestScore)
-
-		if err := c.performCrossovers(); err != nil {
-			return fmt.Errorf("crossover step failed: %s", err)
+		if c.params.Termination.Done(c.stats) {
+			return ErrTerminated
 		}
-		if err := c.performMutations(); err != nil {
-			return fmt.Errorf("mutation step failed: %s", err)
+		fittest := c.population.Top(1)[0]
+		c.population.logf("Fittest: %v", fittest.Individual)
+		c.population.logf("Fittest Score: %.4f", fittest.Score)
+
+		c.generation++
+		var survivors []indWithScore
+		var err error
+		if c.breeder != nil {
+			survivors, err = c.breedWith(c.breeder)
+		} else {
+			survivors, err = c.breed()
 		}
-		err = c.population.scoreAndSort(c.params.Parallelism)
 		if err != nil {
 			return err
 		}
+		if c.params.Sizing != nil {
+			survivors, err = c.resize(survivors)
+			if err != nil {
+				return fmt.Errorf("resizing step failed: %s", err)
+			}
+		}
+		if c.params.HallOfFameElites > 0 {
+			survivors = c.hallOfFame.reinstate(survivors, c.params.HallOfFameElites)
+		}
+		c.setPopulation(c.population.next(survivors))
+		c.population.family.prune(c.generation)
 	}
 
 	return nil
 }
 
+// breed performs the crossover and mutation steps, scores the
+// offspring and returns the survivors of the generation.
+func (c *Controller) breed() ([]indWithScore, error) {
+	offspring, err := c.performCrossovers()
+	if err != nil {
+		return nil, fmt.Errorf("crossover step failed: %s", err)
+	}
+	offspring, err = c.performMutations(offspring)
+	if err != nil {
+		return nil, fmt.Errorf("mutation step failed: %s", err)
+	}
+	offspring, err = calculateFitnessConcurrently(offspring, c.params.Parallelism, c.params.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	c.evaluations += len(offspring)
+	successes := 0
+	for _, child := range offspring {
+		c.crossovers.reward(child.crossoverOp, child.score-child.parentScore)
+		c.mutations.reward(child.mutationOp, child.score-child.parentScore)
+		if child.score > child.parentScore {
+			successes++
+		}
+	}
+	c.successRate = float64(successes) / float64(len(offspring))
+	return c.selectSurvivors(offspring), nil
+}
+
+// setPopulation replaces the current population, records
+// the statistics of the new generation and notifies the observer.
+func (c *Controller) setPopulation(pop *Population) {
+	c.mu.Lock()
+	c.population = pop
+	if c.lineage != nil {
+		c.lineage.add(pop.pop, pop.family)
+	}
+	if c.hallOfFame != nil {
+		c.hallOfFame.update(pop.pop)
+	}
+	if len(pop.pop) > 0 && (c.bestEver == nil || pop.pop[0].score > c.bestEver.score) {
+		best := pop.pop[0]
+		c.bestEver = &best
+	}
+	stats := pop.stats(c.generation, c.evaluations)
+	stats.Elapsed = time.Since(c.start)
+	stats.CrossoverOperators = c.crossovers.report()
+	stats.MutationOperators = c.mutations.report()
+	stats.CrossoverRate = c.crossoverRate.report()
+	stats.MutationRate = c.mutationRate.report()
+	stats.SuccessRate = c.successRate
+	c.stats = append(c.stats, stats)
+	c.mu.Unlock()
+
+	if c.params.History != nil {
+		c.params.History.record(stats, pop)
+	}
+	c.params.Metrics.observe(stats)
+	if c.params.Observer != nil {
+		c.params.Observer(stats)
+	}
+}
+
 // Run runs the genetic algorithm until an individual with
 // the target fitness level is found. It returns only after
 // finding this individual.
@@ -154,7 +584,10 @@ func (c *Controller) Run() error {
 // prematurely cancel a long-running search.
 func (c *Controller) Start(ctx context.Context) {
 	go func() {
-		c.err <- c.run(ctx)
+		err := c.run(ctx)
+		c.params.Metrics.failed(err)
+		close(c.done)
+		c.err <- err
 	}()
 }
 
@@ -167,64 +600,161 @@ func (c *Controller) Wait() error {
 }
 
 // Fittest returns the fittest individual in the current population.
+// Once the population has been evaluated, it goes by the scores of the
+// evaluation rather than calling Fitness again, so it's cheap to call
+// while the search runs.
 func (c *Controller) Fittest() (Individual, error) {
-	return c.population.Fittest()
+	c.mu.RLock()
+	pop, scored := c.population, len(c.stats) > 0
+	c.mu.RUnlock()
+	if scored {
+		return pop.Top(1)[0].Individual, nil
+	}
+	return pop.Fittest()
 }
 
-func (c *Controller) performCrossovers() error {
-	var err error
-	newGeneration := make([]Individual, len(c.population.pop))
-	for i, ind := range c.population.pop {
+// Stats returns the statistics of every generation so far,
+// starting with the initial population.
+func (c *Controller) Stats() []Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]Stats{}, c.stats...)
+}
 
-		// Should we perform crossover on this individual?
-		if c.params.Crossover > rand.Float64() && i >= c.params.Elitism {
-			parent, err := c.params.SelectionMethod(c.population)
-			if err != nil {
-				return err
+// performCrossovers breeds the next generation's offspring.
+func (c *Controller) performCrossovers() ([]indWithScore, error) {
+	size := len(c.population.pop)
+	offspring := make([]indWithScore, 0, c.params.Offspring)
+	for len(offspring) < c.params.Offspring {
+		chosen, err := c.params.MatingScheme(c.population, c.params.ParentsPerMating)
+		if err != nil {
+			return nil, err
+		}
+		if len(chosen) == 0 {
+			return nil, errors.New("mating scheme chose no parents")
+		}
+		parents := make([]Individual, len(chosen))
+		parentIDs := make([]uint64, len(chosen))
+		parentScore := -math.MaxFloat64
+		for i, index := range chosen {
+			if index < 0 || index >= size {
+				return nil, fmt.Errorf("mating scheme chose parent %d of %d", index, size)
 			}
-			child, err := ind.Crossover(parent)
+			parents[i] = c.population.pop[index].Individual
+			parentIDs[i] = c.population.pop[index].id
+			parentScore = math.Max(parentScore, c.population.pop[index].score)
+		}
+		remaining := c.params.Offspring - len(offspring)
+		if remaining > c.params.OffspringPerMating {
+			remaining = c.params.OffspringPerMating
+		}
+
+		// Should these parents undergo crossover?
+		crossoverRate := c.crossoverRate.rate(c.rateContext(parentScore))
+		if crossoverRate > c.population.rng.Float64() && len(parents) > 1 {
+			op, children, err := c.crossover(parents, remaining)
 			if err != nil {
-				return err
+				return nil, err
+			}
+			name := "crossover"
+			if op >= 0 {
+				name = c.params.CrossoverOperators[op].Name
+			}
+			for _, child := range children {
+				ind := c.birth(child, parentIDs, name)
+				ind.crossoverOp = op
+				ind.parentScore = parentScore
+				offspring = append(offspring, ind)
 			}
-			newGeneration[i] = child
 		} else {
-			// If not, it goes to the next generation unchanged
-			newGeneration[i] = ind.Individual
+			// If not, they are copied into the offspring
+			for i := 0; i < remaining && i < len(parents); i++ {
+				ind := c.birth(parents[i], parentIDs[i:i+1], "copy")
+				ind.parentScore = c.population.pop[chosen[i]].score
+				offspring = append(offspring, ind)
+			}
 		}
+	}
+	return offspring, nil
+}
+
+// crossover mates the parents with one of the registered crossover
+// operators, if there are any, and returns the operator's index along
+// with at most n children. Without registered operators, it uses the
+// Individuals' own methods and returns -1.
+func (c *Controller) crossover(parents []Individual, n int) (int, []Individual, error) {
+	if c.crossovers == nil {
+		children, err := crossover(parents, n)
+		return -1, children, err
+	}
+	op := c.crossovers.selector.Select(c.population.rng)
+	children, err := c.params.CrossoverOperators[op].Crossover(parents, n)
+	if err != nil {
+		return op, nil, err
+	}
+	if len(children) == 0 {
+		return op, nil, fmt.Errorf("crossover operator %q produced no offspring", c.params.CrossoverOperators[op].Name)
+	}
+	if len(children) > n {
+		children = children[:n]
+	}
+	return op, children, nil
+}
 
+// mutate mutates the individual with one of the registered mutation
+// operators, if there are any, and returns the operator's index along
+// with the mutant. Without registered operators, it uses the
+// Individual's own Mutate() method and returns -1.
+func (c *Controller) mutate(ind Individual, rate float64) (int, Individual, error) {
+	if c.mutations == nil {
+		mutant, err := ind.Mutate(rate)
+		return -1, mutant, err
 	}
-	c.population, err = NewPopulation(newGeneration)
-	return err
+	op := c.mutations.selector.Select(c.population.rng)
+	mutant, err := c.params.MutationOperators[op].Mutate(ind, rate)
+	return op, mutant, err
 }
 
-func (c *Controller) performMutations() error {
-	var err error
-	newGeneration := make([]Individual, len(c.population.pop))
-	for i, ind := range c.population.pop {
-		mutatationRate := c.params.Mutation
+// rateContext describes the current state of the
+// search to a RateSchedule.
+func (c *Controller) rateContext(score float64) RateContext {
+	return RateContext{
+		Generation:  c.generation,
+		Stats:       c.stats[len(c.stats)-1],
+		Score:       score,
+		SuccessRate: c.successRate,
+	}
+}
 
-		// Are we using an adaptive mutation rate?
-		if c.params.AdaptiveMutation {
-			adaptiveFactor, err := c.population.getAdaptiveMutationRate(ind.Individual)
-			if err != nil {
-				return err
-			}
-			mutatationRate = adaptiveFactor * c.params.Mutation
-		}
+// birth registers a newly bred individual in the pedigree.
+func (c *Controller) birth(ind Individual, parents []uint64, operator string) indWithScore {
+	return indWithScore{
+		Individual:  ind,
+		id:          c.population.family.add(parents, c.generation),
+		born:        c.generation,
+		operator:    operator,
+		crossoverOp: -1,
+		mutationOp:  -1,
+	}
+}
 
-		// Should we perform mutation on this individual?
-		if i >= c.params.Elitism {
-			mutated, err := ind.Mutate(mutatationRate)
-			if err != nil {
-				return err
-			}
-			newGeneration[i] = mutated
+// performMutations mutates every one of the offspring.
+func (c *Controller) performMutations(offspring []indWithScore) ([]indWithScore, error) {
+	mutated := make([]indWithScore, len(offspring))
+	for i, ind := range offspring {
+		mutationRate := c.mutationRate.rate(c.rateContext(ind.parentScore))
+		op, child, err := c.mutate(ind.Individual, mutationRate)
+		if err != nil {
+			return nil, err
+		}
+		mutated[i] = ind
+		mutated[i].Individual = child
+		mutated[i].mutationOp = op
+		if op >= 0 {
+			mutated[i].operator += "+" + c.params.MutationOperators[op].Name
 		} else {
-			// If not, it goes to the next generation unchanged
-			newGeneration[i] = ind.Individual
+			mutated[i].operator += "+mutation"
 		}
-
 	}
-	c.population, err = NewPopulation(newGeneration)
-	return err
+	return mutated, nil
 }
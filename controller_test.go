@@ -1,6 +1,7 @@
 package genetic
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,12 +52,117 @@ func Test_NewController_TestTable_AllInvalidParams(t *testing.T) {
 				SelectionMethod: Roulette(),
 			},
 		},
+		{
+			label: "negative hall of fame",
+			params: Params{
+				HallOfFame:      -1,
+				InitPop:         make([]fakeIndividual, 3),
+				SelectionMethod: Roulette(),
+			},
+		},
+		{
+			label: "more hall of fame elites than members",
+			params: Params{
+				HallOfFame:       1,
+				HallOfFameElites: 2,
+				InitPop:          make([]fakeIndividual, 3),
+				SelectionMethod:  Roulette(),
+			},
+		},
 		{
 			label: "selection method nil",
 			params: Params{
 				InitPop: make([]fakeIndividual, 3),
 			},
 		},
+		{
+			label: "one parent per mating",
+			params: Params{
+				ParentsPerMating: 1,
+				InitPop:          make([]fakeIndividual, 3),
+				SelectionMethod:  Roulette(),
+			},
+		},
+		{
+			label: "many parents without MultiCrossover",
+			params: Params{
+				ParentsPerMating: 3,
+				InitPop:          make([]fakeIndividual, 3),
+				SelectionMethod:  Roulette(),
+			},
+		},
+		{
+			label: "negative offspring per mating",
+			params: Params{
+				OffspringPerMating: -1,
+				InitPop:            make([]fakeIndividual, 3),
+				SelectionMethod:    Roulette(),
+			},
+		},
+		{
+			label: "population larger than InitPop",
+			params: Params{
+				PopulationSize:  4,
+				InitPop:         make([]fakeIndividual, 3),
+				SelectionMethod: Roulette(),
+			},
+		},
+		{
+			label: "too few offspring to replace parents",
+			params: Params{
+				Offspring:       1,
+				InitPop:         make([]fakeIndividual, 3),
+				SelectionMethod: Roulette(),
+			},
+		},
+		{
+			label: "negative maximum age",
+			params: Params{
+				MaxAge:          -1,
+				InitPop:         make([]fakeIndividual, 3),
+				SelectionMethod: Roulette(),
+			},
+		},
+		{
+			label: "sizing without generator",
+			params: Params{
+				Sizing:          Doubling(5),
+				InitPop:         make([]fakeIndividual, 3),
+				SelectionMethod: Roulette(),
+			},
+		},
+		{
+			label: "elitism larger than population",
+			params: Params{
+				Elitism:         4,
+				InitPop:         make([]fakeIndividual, 3),
+				SelectionMethod: Roulette(),
+			},
+		},
+		{
+			label: "negative parallelism",
+			params: Params{
+				Parallelism:     -1,
+				InitPop:         make([]fakeIndividual, 3),
+				SelectionMethod: Roulette(),
+			},
+		},
+		{
+			label: "negative termination limit",
+			params: Params{
+				Termination:     Termination{MaxGenerations: -1},
+				InitPop:         make([]fakeIndividual, 3),
+				SelectionMethod: Roulette(),
+			},
+		},
+		{
+			label: "unknown survivor selection",
+			params: Params{
+				Survivors:       SurvivorSelection(5),
+				InitPop:         make([]fakeIndividual, 3),
+				SelectionMethod: Roulette(),
+			},
+		},
 		{
 			label: "InitPop is not slice",
 			params: Params{
@@ -74,6 +180,22 @@ func Test_NewController_TestTable_AllInvalidParams(t *testing.T) {
 	}
 }
 
+func Test_NewController_Seed_Deterministic(t *testing.T) {
+	var draws [][]int64
+	for i := 0; i < 2; i++ {
+		ctrl, err := NewController(Params{
+			Seed:            42,
+			SelectionMethod: Roulette(),
+			InitPop:         []fakeIndividual{{id: 0}, {id: 1}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		draws = append(draws, []int64{ctrl.population.rng.Int63(), ctrl.population.rng.Int63()})
+	}
+	assert.Equal(t, draws[0], draws[1])
+}
+
 func Test_Run_PopWithTargetMet_ErrNilAndCorrectWinner(t *testing.T) {
 	fittest := fakeIndividual{id: 4, fitness: 5}
 	pop := []fakeIndividual{
@@ -114,4 +236,83 @@ func Test_Run_PopWithTargetMet_ErrNilAndCorrectWinner(t *testing.T) {
 		t.Fatal(err)
 	}
 	assert.Equal(t, fittest, ret)
+
+	stats := ctrl.Stats()
+	assert.Len(t, stats, 1)
+	assert.Equal(t, float64(5), stats[0].Best)
+	assert.InDelta(t, 2.2, stats[0].Mean, 1e-9)
+	assert.Equal(t, 5, stats[0].PopulationSize)
+	assert.Equal(t, 5, stats[0].Evaluations)
+}
+
+// sealedIndividual fails to evaluate once its search is over.
+type sealedIndividual struct {
+	fakeIndividual
+	sealed *bool
+}
+
+func (si sealedIndividual) Fitness() (float64, error) {
+	if *si.sealed {
+		return 0, errors.New("evaluated after the search")
+	}
+	return si.fitness, nil
+}
+
+func Test_Fittest_AfterRun_ScoresReused(t *testing.T) {
+	sealed := false
+	ctrl, err := NewController(Params{
+		TargetFitness:   2,
+		SelectionMethod: Tournament(2),
+		InitPop:         []sealedIndividual{{fakeIndividual{id: 1, fitness: 1}, &sealed}, {fakeIndividual{id: 2, fitness: 2}, &sealed}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, ctrl.Run())
+
+	sealed = true
+	fittest, err := ctrl.Fittest()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fittest.(sealedIndividual).id)
+}
+
+// countedIndividual counts its evaluations.
+type countedIndividual struct {
+	fakeIndividual
+	calls *int
+}
+
+func (ci countedIndividual) Crossover(ind Individual) (Individual, error) {
+	return ci, nil
+}
+
+func (ci countedIndividual) Mutate(rate float64) (Individual, error) {
+	return ci, nil
+}
+
+func (ci countedIndividual) Fitness() (float64, error) {
+	*ci.calls++
+	return ci.fitness, nil
+}
+
+func Test_Run_ManyGenerations_OnlyEvaluationsCounted(t *testing.T) {
+	calls := 0
+	initPop := make([]countedIndividual, 4)
+	for i := range initPop {
+		initPop[i] = countedIndividual{fakeIndividual{id: i, fitness: float64(i)}, &calls}
+	}
+	ctrl, err := NewController(Params{
+		Parallelism:     1,
+		TargetFitness:   10,
+		SelectionMethod: Tournament(2),
+		Termination:     Termination{MaxGenerations: 5},
+		InitPop:         initPop,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ErrTerminated, ctrl.Run())
+
+	stats := ctrl.Stats()
+	assert.Equal(t, stats[len(stats)-1].Evaluations, calls)
 }
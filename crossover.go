@@ -0,0 +1,59 @@
+package genetic
+
+import (
+	"errors"
+	"fmt"
+)
+
+// crossover mates the given parents and returns at most n children.
+// It uses the richest crossover interface the first parent offers:
+// MultiCrossover for anything other than one child from two parents,
+// PairCrossover for two children from two parents, and plain
+// Crossover() with alternating roles otherwise.
+func crossover(parents []Individual, n int) ([]Individual, error) {
+	if len(parents) < 2 {
+		return nil, fmt.Errorf("crossover needs at least two parents, got %d", len(parents))
+	}
+	first, mates := parents[0], parents[1:]
+
+	var children []Individual
+	if multi, ok := first.(MultiCrossover); ok && (len(mates) > 1 || n > 1) {
+		offspring, err := multi.MultiCrossover(mates)
+		if err != nil {
+			return nil, err
+		}
+		children = offspring
+	} else if len(mates) > 1 {
+		return nil, fmt.Errorf("%T does not implement MultiCrossover; can't mate %d parents", first, len(parents))
+	} else if pair, ok := first.(PairCrossover); ok && n > 1 {
+		for len(children) < n {
+			child1, child2, err := pair.PairCrossover(mates[0])
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child1, child2)
+		}
+	} else {
+		// Fall back on Crossover(), swapping the parents' roles
+		// for every other child.
+		for i := 0; i < n; i++ {
+			a, b := first, mates[0]
+			if i%2 == 1 {
+				a, b = b, a
+			}
+			child, err := a.Crossover(b)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child)
+		}
+	}
+
+	if len(children) == 0 {
+		return nil, errors.New("crossover produced no offspring")
+	}
+	if len(children) > n {
+		children = children[:n]
+	}
+	return children, nil
+}
@@ -0,0 +1,67 @@
+package genetic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_crossover_TwoParentsOneChild_CrossoverUsed(t *testing.T) {
+	parents := []Individual{fakeIndividual{id: 1}, fakeIndividual{id: 2}}
+
+	children, err := crossover(parents, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []Individual{fakeIndividual{id: 1, crossoverCount: 1}}, children)
+}
+
+func Test_crossover_TwoChildrenWithoutPairCrossover_RolesSwapped(t *testing.T) {
+	parents := []Individual{fakeIndividual{id: 1}, fakeIndividual{id: 2}}
+
+	children, err := crossover(parents, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []Individual{
+		fakeIndividual{id: 1, crossoverCount: 1},
+		fakeIndividual{id: 2, crossoverCount: 1},
+	}, children)
+}
+
+func Test_crossover_TwoChildrenWithPairCrossover_BothChildrenReturned(t *testing.T) {
+	p1 := fakePairIndividual{fakeIndividual{id: 1}}
+	p2 := fakePairIndividual{fakeIndividual{id: 2}}
+
+	children, err := crossover([]Individual{p1, p2}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []Individual{p1, p2}, children)
+}
+
+func Test_crossover_ManyParentsWithoutMultiCrossover_ErrNotNil(t *testing.T) {
+	parents := []Individual{fakeIndividual{id: 1}, fakeIndividual{id: 2}, fakeIndividual{id: 3}}
+
+	_, err := crossover(parents, 1)
+
+	assert.NotNil(t, err)
+}
+
+func Test_crossover_ManyParentsWithMultiCrossover_ChildrenTruncated(t *testing.T) {
+	parents := []Individual{
+		fakeMultiIndividual{fakeIndividual{id: 1}},
+		fakeMultiIndividual{fakeIndividual{id: 2}},
+		fakeMultiIndividual{fakeIndividual{id: 3}},
+	}
+
+	children, err := crossover(parents, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, parents[:2], children)
+}
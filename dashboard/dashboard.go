@@ -0,0 +1,219 @@
+// Package dashboard serves a live view of a running genetic.Controller:
+// a convergence chart of the best, mean and worst scores of every
+// generation, the population's diversity, the fittest individual and
+// the effective parameters, updated through Server-Sent Events, with
+// buttons to pause, resume and cancel the search.
+//
+// The Dashboard is an http.Handler. To mount it anywhere but the root,
+// strip the prefix:
+//
+//	http.Handle("/search/", http.StripPrefix("/search", dashboard.New(ctrl)))
+package dashboard
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+//go:embed static
+var static embed.FS
+
+// Dashboard serves the dashboard of a controller.
+type Dashboard struct {
+	// Interval is how often the events stream checks for new
+	// generations. The default is half a second.
+	Interval time.Duration
+
+	controller *genetic.Controller
+	mux        *http.ServeMux
+}
+
+// New returns a Dashboard for the given controller, which
+// may be running already or started later.
+func New(c *genetic.Controller) *Dashboard {
+	d := &Dashboard{
+		Interval:   500 * time.Millisecond,
+		controller: c,
+		mux:        http.NewServeMux(),
+	}
+	assets, _ := fs.Sub(static, "static")
+	d.mux.Handle("/", http.FileServer(http.FS(assets)))
+	d.mux.HandleFunc("/events", d.events)
+	d.mux.HandleFunc("/params", d.params)
+	d.mux.HandleFunc("/pause", d.control(c.Pause))
+	d.mux.HandleFunc("/resume", d.control(c.Resume))
+	d.mux.HandleFunc("/cancel", d.control(c.Cancel))
+	return d
+}
+
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mux.ServeHTTP(w, r)
+}
+
+// generation is a generation's statistics, as sent to the page.
+type generation struct {
+	Generation  int     `json:"generation"`
+	Best        float64 `json:"best"`
+	Mean        float64 `json:"mean"`
+	Worst       float64 `json:"worst"`
+	Diversity   float64 `json:"diversity"`
+	Evaluations int     `json:"evaluations"`
+	ElapsedMs   float64 `json:"elapsedMs"`
+}
+
+// update is the data of an event: the generations since the last event,
+// the fittest individual and the state of the search, which is
+// "running", "paused" or "finished".
+type update struct {
+	Generations []generation `json:"generations"`
+	Fittest     string       `json:"fittest"`
+	State       string       `json:"state"`
+}
+
+// finite replaces infinite and NaN scores, which JSON can't encode,
+// with the largest finite numbers.
+func finite(f float64) float64 {
+	switch {
+	case math.IsNaN(f):
+		return 0
+	case math.IsInf(f, 1):
+		return math.MaxFloat64
+	case math.IsInf(f, -1):
+		return -math.MaxFloat64
+	}
+	return f
+}
+
+// update returns the update for a client that has seen
+// the first sent generations.
+func (d *Dashboard) update(sent int) update {
+	out := update{State: "running", Generations: []generation{}}
+	select {
+	case <-d.controller.Done():
+		out.State = "finished"
+	default:
+		if d.controller.Paused() {
+			out.State = "paused"
+		}
+	}
+	stats := d.controller.Stats()
+	for _, s := range stats[sent:] {
+		out.Generations = append(out.Generations, generation{
+			Generation:  s.Generation,
+			Best:        finite(s.Best),
+			Mean:        finite(s.Mean),
+			Worst:       finite(s.Worst),
+			Diversity:   finite(s.Diversity),
+			Evaluations: s.Evaluations,
+			ElapsedMs:   s.Elapsed.Seconds() * 1000,
+		})
+	}
+	if len(stats) > 0 {
+		if fittest, err := d.controller.Fittest(); err == nil {
+			out.Fittest = fmt.Sprint(fittest)
+		}
+	}
+	return out
+}
+
+// events streams updates as Server-Sent Events, starting with every
+// generation so far, until the search is over or the client leaves.
+func (d *Dashboard) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming isn't supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+	sent, last := 0, ""
+	for {
+		u := d.update(sent)
+		data, err := json.Marshal(u)
+		if err != nil {
+			return
+		}
+		// Unchanged updates aren't sent again.
+		if len(u.Generations) > 0 || u.State+u.Fittest != last {
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+		sent += len(u.Generations)
+		last = u.State + u.Fittest
+		if u.State == "finished" {
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-d.controller.Done():
+		case <-ticker.C:
+		}
+	}
+}
+
+// params serves the effective parameters of the search as JSON.
+func (d *Dashboard) params(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(describe(d.controller.Params()))
+}
+
+// describe renders every parameter as text. Functions and other values
+// that can't be shown are "set" when they are, and left out when they
+// aren't, and operators are listed by name.
+func describe(params genetic.Params) map[string]string {
+	out := map[string]string{}
+	v := reflect.ValueOf(params)
+	for i := 0; i < v.NumField(); i++ {
+		name, field := v.Type().Field(i).Name, v.Field(i)
+		switch {
+		case name == "InitPop" || v.Type().Field(i).PkgPath != "":
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Struct:
+			var names []string
+			for j := 0; j < field.Len(); j++ {
+				if n := field.Index(j).FieldByName("Name"); n.IsValid() {
+					names = append(names, n.String())
+				}
+			}
+			if len(names) > 0 {
+				out[name] = strings.Join(names, ", ")
+			}
+		case field.Kind() == reflect.Func || field.Kind() == reflect.Interface ||
+			field.Kind() == reflect.Ptr || field.Kind() == reflect.Slice:
+			if !field.IsNil() {
+				out[name] = "set"
+			}
+		case field.Kind() == reflect.Struct:
+			out[name] = fmt.Sprintf("%+v", field.Interface())
+		default:
+			out[name] = fmt.Sprint(field.Interface())
+		}
+	}
+	return out
+}
+
+// control returns a handler that applies an action to
+// the search. Only POST requests are accepted.
+func (d *Dashboard) control(action func()) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		action()
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,163 @@
+package dashboard
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomjcleveland/genetic"
+)
+
+// number is an individual whose fitness is its value.
+type number float64
+
+func (n number) Crossover(genetic.Individual) (genetic.Individual, error) { return n, nil }
+func (n number) Mutate(float64) (genetic.Individual, error)               { return n + 1, nil }
+func (n number) Fitness() (float64, error)                                { return float64(n), nil }
+func (n number) String() string                                           { return "number" }
+
+func controller(t *testing.T, generations int) *genetic.Controller {
+	ctrl, err := genetic.NewController(genetic.Params{
+		Elitism:         1,
+		Mutation:        1,
+		TargetFitness:   math.Inf(1),
+		SelectionMethod: genetic.Tournament(2),
+		InitPop:         []number{1, 2, 3},
+		Termination:     genetic.Termination{MaxGenerations: generations},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctrl
+}
+
+func get(t *testing.T, h http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+	return rec
+}
+
+func Test_Dashboard_Assets_Served(t *testing.T) {
+	d := New(controller(t, 1))
+	for path, text := range map[string]string{
+		"/":          "Convergence",
+		"/app.js":    "EventSource",
+		"/style.css": "canvas",
+	} {
+		rec := get(t, d, path)
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Contains(t, rec.Body.String(), text, path)
+	}
+}
+
+func Test_Dashboard_Params_EffectiveParams(t *testing.T) {
+	rec := get(t, New(controller(t, 1)), "/params")
+	var params map[string]string
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &params))
+	assert.Equal(t, "1", params["Elitism"])
+	assert.Equal(t, "3", params["PopulationSize"])
+	assert.Equal(t, "set", params["SelectionMethod"])
+	assert.Equal(t, "+Inf", params["TargetFitness"])
+	assert.Contains(t, params["Termination"], "MaxGenerations:1")
+	assert.NotContains(t, params, "InitPop")
+	assert.NotContains(t, params, "Sizing")
+}
+
+func Test_describe_Operators_Named(t *testing.T) {
+	params := describe(genetic.Params{
+		CrossoverOperators: []genetic.CrossoverOperator{{Name: "one-point"}, {Name: "uniform"}},
+	})
+	assert.Equal(t, "one-point, uniform", params["CrossoverOperators"])
+	assert.NotContains(t, params, "MutationOperators")
+}
+
+func Test_Dashboard_Controls_Applied(t *testing.T) {
+	ctrl := controller(t, 1000000)
+	d := New(ctrl)
+	assert.Equal(t, http.StatusMethodNotAllowed, get(t, d, "/pause").Code)
+
+	post := func(path string) {
+		rec := httptest.NewRecorder()
+		d.ServeHTTP(rec, httptest.NewRequest("POST", path, nil))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	}
+	post("/pause")
+	assert.True(t, ctrl.Paused())
+	post("/resume")
+	assert.False(t, ctrl.Paused())
+
+	ctrl.Start(context.Background())
+	post("/cancel")
+	assert.Equal(t, genetic.ErrContextCancelled, ctrl.Wait())
+}
+
+func Test_Dashboard_Events_StreamedUntilFinished(t *testing.T) {
+	ctrl := controller(t, 3)
+	d := New(ctrl)
+	d.Interval = 5 * time.Millisecond
+	server := httptest.NewServer(d)
+	defer server.Close()
+
+	ctrl.Pause()
+	ctrl.Start(context.Background())
+	resp, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	var updates []update
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if !strings.HasPrefix(scanner.Text(), "data: ") {
+			continue
+		}
+		var u update
+		assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(scanner.Text(), "data: ")), &u))
+		updates = append(updates, u)
+		if u.State == "paused" {
+			ctrl.Resume()
+		}
+	}
+	assert.Equal(t, genetic.ErrTerminated, ctrl.Wait())
+
+	var generations []int
+	for _, u := range updates {
+		for _, g := range u.Generations {
+			generations = append(generations, g.Generation)
+		}
+	}
+	assert.Equal(t, []int{0, 1, 2, 3}, generations)
+	assert.Equal(t, "paused", updates[0].State)
+	assert.Equal(t, "finished", updates[len(updates)-1].State)
+	assert.Equal(t, "number", updates[len(updates)-1].Fittest)
+}
+
+func Test_Dashboard_Events_ClientLeaves(t *testing.T) {
+	ctrl := controller(t, 1)
+	d := New(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		d.ServeHTTP(rec, httptest.NewRequest("GET", "/events", nil).WithContext(ctx))
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("events stream didn't end")
+	}
+	body, _ := ioutil.ReadAll(rec.Body)
+	assert.Contains(t, string(body), `"state":"running"`)
+}
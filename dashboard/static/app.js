@@ -0,0 +1,98 @@
+"use strict";
+
+var generations = [];
+
+function draw(canvas, series) {
+  var ctx = canvas.getContext("2d");
+  ctx.clearRect(0, 0, canvas.width, canvas.height);
+  if (generations.length === 0) {
+    return;
+  }
+  var min = Infinity, max = -Infinity;
+  series.forEach(function (s) {
+    generations.forEach(function (g) {
+      min = Math.min(min, g[s.key]);
+      max = Math.max(max, g[s.key]);
+    });
+  });
+  if (min === max) {
+    min -= 1;
+    max += 1;
+  }
+  var pad = 30;
+  var x = function (i) {
+    return pad + (canvas.width - 2 * pad) * (generations.length === 1 ? 0 : i / (generations.length - 1));
+  };
+  var y = function (v) {
+    return canvas.height - pad - (canvas.height - 2 * pad) * (v - min) / (max - min);
+  };
+  ctx.fillStyle = "#666";
+  ctx.font = "11px sans-serif";
+  ctx.fillText(max.toPrecision(5), 2, pad - 5);
+  ctx.fillText(min.toPrecision(5), 2, canvas.height - pad + 12);
+  ctx.fillText("generation " + generations[generations.length - 1].generation, canvas.width - 110, canvas.height - 5);
+  series.forEach(function (s) {
+    ctx.strokeStyle = s.color;
+    ctx.beginPath();
+    generations.forEach(function (g, i) {
+      if (i === 0) {
+        ctx.moveTo(x(i), y(g[s.key]));
+      } else {
+        ctx.lineTo(x(i), y(g[s.key]));
+      }
+    });
+    ctx.stroke();
+  });
+}
+
+function render() {
+  draw(document.getElementById("convergence"), [
+    {key: "worst", color: "#a33"},
+    {key: "mean", color: "#27a"},
+    {key: "best", color: "#2a7"}
+  ]);
+  draw(document.getElementById("diversity"), [{key: "diversity", color: "#555"}]);
+  var last = generations[generations.length - 1];
+  if (last) {
+    document.getElementById("summary").textContent =
+      "generation " + last.generation + ", best " + last.best.toPrecision(6) +
+      ", " + last.evaluations + " evaluations, " + (last.elapsedMs / 1000).toFixed(1) + "s";
+  }
+}
+
+function control(action) {
+  fetch(action, {method: "POST"});
+}
+
+["pause", "resume", "cancel"].forEach(function (action) {
+  document.getElementById(action).addEventListener("click", function () {
+    control(action);
+  });
+});
+
+fetch("params").then(function (r) {
+  return r.json();
+}).then(function (params) {
+  var table = document.getElementById("params");
+  Object.keys(params).sort().forEach(function (name) {
+    var row = table.insertRow();
+    row.insertCell().textContent = name;
+    row.insertCell().textContent = params[name];
+  });
+});
+
+var events = new EventSource("events");
+events.onmessage = function (e) {
+  var update = JSON.parse(e.data);
+  generations = generations.concat(update.generations);
+  document.getElementById("state").textContent = update.state;
+  document.getElementById("fittest").textContent = update.fittest;
+  var finished = update.state === "finished";
+  document.getElementById("pause").disabled = finished || update.state === "paused";
+  document.getElementById("resume").disabled = finished || update.state !== "paused";
+  document.getElementById("cancel").disabled = finished;
+  if (finished) {
+    events.close();
+  }
+  render();
+};
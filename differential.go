@@ -0,0 +1,307 @@
+package genetic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// RealVector is implemented by Individuals whose genome is a vector of
+// real numbers, which differential evolution needs to work on.
+type RealVector interface {
+	Individual
+
+	// Vector returns the genome. It must not be modified.
+	Vector() []float64
+
+	// WithVector returns a new Individual with the given genome. It is
+	// the place to repair genomes that are out of bounds.
+	WithVector([]float64) Individual
+}
+
+// DEVariant is a differential evolution mutation strategy.
+type DEVariant int
+
+const (
+	// DERand1Bin mutates a random member: v = x_r1 + F(x_r2 - x_r3).
+	DERand1Bin DEVariant = iota
+
+	// DEBest1Bin mutates the fittest member: v = x_best + F(x_r1 - x_r2).
+	DEBest1Bin
+
+	// DECurrentToBest1Bin moves each member towards the fittest:
+	// v = x_i + F(x_best - x_i) + F(x_r1 - x_r2).
+	DECurrentToBest1Bin
+
+	// DECurrentToPBest1Bin is JADE's variant of DECurrentToBest1Bin,
+	// moving towards a random member of the fittest P instead.
+	DECurrentToPBest1Bin
+)
+
+// DEAdaptation is a way of adapting differential evolution's F and CR.
+type DEAdaptation int
+
+const (
+	// NoAdaptation uses the same F and CR throughout.
+	NoAdaptation DEAdaptation = iota
+
+	// JADE draws F and CR for every trial around means that move towards
+	// the values of successful trials (Zhang and Sanderson, 2009).
+	JADE
+
+	// SHADE keeps a memory of successful F and CR means, weighted by the
+	// improvement each trial brought (Tanabe and Fukunaga, 2013).
+	SHADE
+)
+
+// DEParams holds the parameters of differential evolution.
+type DEParams struct {
+	// Variant is the mutation strategy. The default is DERand1Bin.
+	Variant DEVariant
+
+	// F is the differential weight, and CR the crossover probability.
+	// With adaptation, they are the initial means. The defaults are 0.5
+	// and 0.9.
+	F  float64
+	CR float64
+
+	// P is the fraction of the fittest members DECurrentToPBest1Bin
+	// moves towards. The default is 0.1.
+	P float64
+
+	// Adaptation selects how F and CR adapt during the search.
+	Adaptation DEAdaptation
+
+	// LearningRate is how quickly JADE's means move. The default is 0.1.
+	LearningRate float64
+
+	// MemorySize is how many means SHADE remembers. The default is 10.
+	MemorySize int
+}
+
+// DifferentialEvolution returns a Breeding for differential evolution,
+// with binomial crossover. The Individuals must implement RealVector,
+// and the population needs at least four members.
+func DifferentialEvolution(params DEParams) Breeding {
+	return func() (Breeder, error) {
+		p := params
+		if p.F == 0 {
+			p.F = 0.5
+		}
+		if p.CR == 0 {
+			p.CR = 0.9
+		}
+		if p.P == 0 {
+			p.P = 0.1
+		}
+		if p.LearningRate == 0 {
+			p.LearningRate = 0.1
+		}
+		if p.MemorySize == 0 {
+			p.MemorySize = 10
+		}
+		if p.F < 0 || p.F > 2 {
+			return nil, errors.New("differential weight must be between 0 and 2, inclusive")
+		}
+		if !isProb(p.CR) || !isProb(p.P) || !isProb(p.LearningRate) {
+			return nil, errors.New("CR, P and learning rate must be between 0 and 1, inclusive")
+		}
+		if p.Variant < DERand1Bin || p.Variant > DECurrentToPBest1Bin {
+			return nil, fmt.Errorf("unknown differential evolution variant %d", p.Variant)
+		}
+		if p.Adaptation < NoAdaptation || p.Adaptation > SHADE {
+			return nil, fmt.Errorf("unknown differential evolution adaptation %d", p.Adaptation)
+		}
+		if p.MemorySize < 1 {
+			return nil, errors.New("memory size must be at least 1")
+		}
+		out := &differentialEvolution{
+			params:   p,
+			meanF:    p.F,
+			meanCR:   p.CR,
+			memoryF:  make([]float64, p.MemorySize),
+			memoryCR: make([]float64, p.MemorySize),
+		}
+		for i := range out.memoryF {
+			out.memoryF[i], out.memoryCR[i] = p.F, p.CR
+		}
+		return out, nil
+	}
+}
+
+type differentialEvolution struct {
+	params DEParams
+
+	// JADE's means, and SHADE's memory of them
+	meanF, meanCR     float64
+	memoryF, memoryCR []float64
+	nextMemory        int
+	lastF, lastCR     []float64
+}
+
+// minPopulation implements populous: a mutation
+// needs three members besides its target.
+func (d *differentialEvolution) minPopulation() int {
+	return 4
+}
+
+// Breed implements Breeder.
+func (d *differentialEvolution) Breed(pop *Population) ([]Individual, error) {
+	n := len(pop.pop)
+	if n < 4 {
+		return nil, errors.New("differential evolution needs at least 4 members")
+	}
+	vectors := make([][]float64, n)
+	for i, ind := range pop.pop {
+		rv, ok := ind.Individual.(RealVector)
+		if !ok {
+			return nil, fmt.Errorf("%T does not implement RealVector", ind.Individual)
+		}
+		vectors[i] = rv.Vector()
+	}
+	best := 0
+	for i, ind := range pop.pop {
+		if ind.score > pop.pop[best].score {
+			best = i
+		}
+	}
+	var fittest []int
+	if d.params.Variant == DECurrentToPBest1Bin {
+		fittest = make([]int, n)
+		for i := range fittest {
+			fittest[i] = i
+		}
+		sort.SliceStable(fittest, func(a, b int) bool { return pop.pop[fittest[a]].score > pop.pop[fittest[b]].score })
+		fittest = fittest[:int(math.Max(1, math.Round(d.params.P*float64(n))))]
+	}
+
+	d.lastF, d.lastCR = make([]float64, n), make([]float64, n)
+	trials := make([]Individual, n)
+	for i := range pop.pop {
+		f, cr := d.parameters(pop)
+		d.lastF[i], d.lastCR[i] = f, cr
+		r := distinct(pop, i, 3)
+		x := vectors[i]
+		var pBest []float64
+		if fittest != nil {
+			pBest = vectors[fittest[pop.rng.Intn(len(fittest))]]
+		}
+
+		donor := make([]float64, len(x))
+		for j := range donor {
+			switch d.params.Variant {
+			case DEBest1Bin:
+				donor[j] = vectors[best][j] + f*(vectors[r[0]][j]-vectors[r[1]][j])
+			case DECurrentToBest1Bin:
+				donor[j] = x[j] + f*(vectors[best][j]-x[j]) + f*(vectors[r[0]][j]-vectors[r[1]][j])
+			case DECurrentToPBest1Bin:
+				donor[j] = x[j] + f*(pBest[j]-x[j]) + f*(vectors[r[0]][j]-vectors[r[1]][j])
+			default:
+				donor[j] = vectors[r[0]][j] + f*(vectors[r[1]][j]-vectors[r[2]][j])
+			}
+		}
+
+		// Binomial crossover, taking at least one gene from the donor
+		trial := make([]float64, len(x))
+		always := pop.rng.Intn(len(x))
+		for j := range trial {
+			if j == always || pop.rng.Float64() < cr {
+				trial[j] = donor[j]
+			} else {
+				trial[j] = x[j]
+			}
+		}
+		trials[i] = pop.pop[i].Individual.(RealVector).WithVector(trial)
+	}
+	return trials, nil
+}
+
+// parameters draws F and CR for one trial.
+func (d *differentialEvolution) parameters(pop *Population) (float64, float64) {
+	meanF, meanCR := d.meanF, d.meanCR
+	switch d.params.Adaptation {
+	case NoAdaptation:
+		return d.params.F, d.params.CR
+	case SHADE:
+		k := pop.rng.Intn(len(d.memoryF))
+		meanF, meanCR = d.memoryF[k], d.memoryCR[k]
+	}
+	cr := math.Max(0, math.Min(1, meanCR+0.1*pop.rng.NormFloat64()))
+	for {
+		// Cauchy-distributed, regenerated while not positive
+		f := meanF + 0.1*math.Tan(math.Pi*(pop.rng.Float64()-0.5))
+		if f > 0 {
+			return math.Min(f, 1), cr
+		}
+	}
+}
+
+// Feedback implements Breeder, adapting F and CR.
+func (d *differentialEvolution) Feedback(improvements []float64) {
+	var fs, crs, weights []float64
+	for i, improvement := range improvements {
+		if improvement > 0 {
+			fs = append(fs, d.lastF[i])
+			crs = append(crs, d.lastCR[i])
+			weights = append(weights, improvement)
+		}
+	}
+	if len(fs) == 0 {
+		return
+	}
+	switch d.params.Adaptation {
+	case JADE:
+		c := d.params.LearningRate
+		d.meanCR = (1-c)*d.meanCR + c*weightedMean(crs, nil)
+		d.meanF = (1-c)*d.meanF + c*lehmerMean(fs, nil)
+	case SHADE:
+		d.memoryCR[d.nextMemory] = weightedMean(crs, weights)
+		d.memoryF[d.nextMemory] = lehmerMean(fs, weights)
+		d.nextMemory = (d.nextMemory + 1) % len(d.memoryF)
+	}
+}
+
+// distinct picks k distinct members of the population, none of them i.
+func distinct(pop *Population, i, k int) []int {
+	out := make([]int, 0, k)
+	for _, j := range pop.rng.Perm(len(pop.pop)) {
+		if j != i {
+			out = append(out, j)
+		}
+		if len(out) == k {
+			break
+		}
+	}
+	return out
+}
+
+// weightedMean returns the mean of values, weighted
+// equally when weights is nil.
+func weightedMean(values, weights []float64) float64 {
+	sum, total := float64(0), float64(0)
+	for i, v := range values {
+		w := float64(1)
+		if weights != nil {
+			w = weights[i]
+		}
+		sum += w * v
+		total += w
+	}
+	return sum / total
+}
+
+// lehmerMean returns the Lehmer mean of values, sum(w*v²)/sum(w*v),
+// weighted equally when weights is nil.
+func lehmerMean(values, weights []float64) float64 {
+	num, den := float64(0), float64(0)
+	for i, v := range values {
+		w := float64(1)
+		if weights != nil {
+			w = weights[i]
+		}
+		num += w * v * v
+		den += w * v
+	}
+	return num / den
+}
@@ -0,0 +1,128 @@
+package genetic
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func randomVectors(n, dims int) []fakeVector {
+	out := make([]fakeVector, n)
+	for i := range out {
+		out[i] = make(fakeVector, dims)
+		for j := range out[i] {
+			out[i][j] = rand.Float64()*10 - 5
+		}
+	}
+	return out
+}
+
+func Test_DifferentialEvolution_Sphere_TargetMet(t *testing.T) {
+	testTable := map[string]DEParams{
+		"DE/rand/1/bin":            {Variant: DERand1Bin},
+		"DE/best/1/bin":            {Variant: DEBest1Bin},
+		"DE/current-to-best/1/bin": {Variant: DECurrentToBest1Bin},
+		"JADE":                     {Variant: DECurrentToPBest1Bin, Adaptation: JADE},
+		"SHADE":                    {Variant: DECurrentToPBest1Bin, Adaptation: SHADE},
+	}
+
+	for label, params := range testTable {
+		t.Run(label, func(t *testing.T) {
+			ctrl, err := NewController(Params{
+				TargetFitness: -1e-6,
+				Parallelism:   4,
+				Breeding:      DifferentialEvolution(params),
+				InitPop:       randomVectors(30, 4),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancelFunc()
+
+			ctrl.Start(ctx)
+			if err := ctrl.Wait(); err != nil {
+				t.Fatal(err)
+			}
+			stats := ctrl.Stats()
+			assert.True(t, stats[len(stats)-1].Best >= -1e-6)
+		})
+	}
+}
+
+func Test_DifferentialEvolution_InvalidParams_ErrNotNil(t *testing.T) {
+	_, err := NewController(Params{
+		Breeding: DifferentialEvolution(DEParams{CR: 2}),
+		InitPop:  randomVectors(5, 2),
+	})
+
+	assert.NotNil(t, err)
+}
+
+func Test_DifferentialEvolution_NotRealVector_ErrNotNil(t *testing.T) {
+	ctrl, err := NewController(Params{
+		TargetFitness: 1,
+		Breeding:      DifferentialEvolution(DEParams{}),
+		InitPop:       make([]fakeIndividual, 5),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotNil(t, ctrl.Run())
+}
+
+func Test_NewController_DifferentialEvolutionTooSmall_ValidationError(t *testing.T) {
+	testTable := []struct {
+		label    string
+		params   Params
+		field    string
+		sentinel error
+	}{
+		{"population size", Params{PopulationSize: 3}, "Breeding", ErrExceedsPopulation},
+		{"sizing below breeding", Params{Sizing: Doubling(5), Generator: counter(3)}, "MinPopulationSize", ErrConflict},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.label, func(t *testing.T) {
+			testCase.params.Breeding = DifferentialEvolution(DEParams{})
+			testCase.params.InitPop = randomVectors(10, 2)
+			_, err := NewController(testCase.params)
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("got %v; want a *ValidationError", err)
+			}
+			assert.Equal(t, testCase.field, verr.Field)
+			assert.True(t, errors.Is(err, testCase.sentinel), err.Error())
+		})
+	}
+
+	_, err := NewController(Params{Breeding: DifferentialEvolution(DEParams{}), Sizing: Doubling(5), Generator: counter(3), MinPopulationSize: 4, InitPop: randomVectors(10, 2)})
+	assert.NoError(t, err)
+}
+
+func Test_breedWith_WorseTrials_ParentsKept(t *testing.T) {
+	breeding := DifferentialEvolution(DEParams{})
+	breeder, err := breeding()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pop, err := NewPopulation([]fakeVector{{0}, {0}, {0}, {0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctrl := &Controller{population: pop}
+
+	survivors, err := ctrl.breedWith(breeder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every trial is identical to its parent, so it replaces it
+	assert.Len(t, survivors, 4)
+	assert.Equal(t, float64(0), ctrl.successRate)
+}
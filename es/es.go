@@ -0,0 +1,227 @@
+// Package es implements self-adaptive evolution strategies on top of
+// genetic.Controller. Every individual carries its own mutation step
+// sizes, which evolve along with the object variables, so there's no
+// mutation rate to tune by hand.
+package es
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// StepSizes says how many mutation step sizes an individual carries.
+type StepSizes int
+
+const (
+	// OneStepSize uses a single step size for every object variable.
+	OneStepSize StepSizes = iota
+
+	// PerGeneStepSizes uses one step size per object variable, which
+	// lets the search scale each axis differently.
+	PerGeneStepSizes
+
+	// CorrelatedStepSizes adds a rotation angle for every pair of object
+	// variables to PerGeneStepSizes, so that mutations can follow
+	// valleys that don't run along the axes.
+	CorrelatedStepSizes
+)
+
+// Recombination says how the genes of several parents are combined.
+type Recombination int
+
+const (
+	// Intermediate recombination averages the parents' genes.
+	Intermediate Recombination = iota
+
+	// Discrete recombination copies each gene from a random parent.
+	Discrete
+)
+
+// Params holds all of the parameters for an evolution strategy.
+type Params struct {
+	// Fitness scores a vector of object variables. As elsewhere in this
+	// package, higher is better; negate the objective to minimize it.
+	Fitness func([]float64) (float64, error)
+
+	// Lower and Upper bound the object variables of the initial
+	// population. Their length is the number of dimensions.
+	Lower []float64
+	Upper []float64
+
+	// StepSizes says how many step sizes each individual carries.
+	StepSizes StepSizes
+
+	// InitialSigma is the step size individuals start with. The default
+	// is a tenth of the average width of the initial bounds.
+	InitialSigma float64
+
+	// MinSigma keeps step sizes from collapsing to zero. The
+	// default is 1e-10.
+	MinSigma float64
+
+	// ObjectRecombination and StrategyRecombination say how object
+	// variables and strategy parameters are recombined. The classic
+	// choice, and the default, is discrete recombination for object
+	// variables and intermediate recombination for step sizes.
+	ObjectRecombination   *Recombination
+	StrategyRecombination *Recombination
+
+	// Mu is the number of parents, Lambda the number of offspring per
+	// generation, and Rho the number of parents per recombination; one
+	// means there's no recombination. The defaults are 15, 100 and 2.
+	Mu     int
+	Lambda int
+	Rho    int
+
+	// Plus selects (μ+λ) rather than (μ,λ) survivor selection.
+	Plus bool
+
+	// TargetFitness is the fitness at which the search terminates.
+	TargetFitness float64
+
+	// Parallelism is how many goroutines evaluate fitness.
+	Parallelism int
+}
+
+// NewController returns a genetic.Controller that runs the evolution
+// strategy described by params on a random initial population.
+func NewController(params Params) (*genetic.Controller, error) {
+	s, err := params.strategy()
+	if err != nil {
+		return nil, err
+	}
+	var pop []genetic.Individual
+	for i := 0; i < params.Mu; i++ {
+		pop = append(pop, params.random(s))
+	}
+	gp := genetic.Params{
+		Mutation:           1,
+		Crossover:          1,
+		TargetFitness:      params.TargetFitness,
+		Parallelism:        params.Parallelism,
+		MatingScheme:       genetic.RandomPairing(),
+		ParentsPerMating:   params.Rho,
+		OffspringPerMating: 1,
+		PopulationSize:     params.Mu,
+		Offspring:          params.Lambda,
+		Survivors:          genetic.MuCommaLambda,
+		InitPop:            pop,
+	}
+	if params.Plus {
+		gp.Survivors = genetic.MuPlusLambda
+	}
+	if params.Rho < 2 {
+		gp.ParentsPerMating = 2
+		gp.Crossover = 0
+	}
+	return genetic.NewController(gp)
+}
+
+// Population returns n random individuals, with object variables drawn
+// uniformly from the initial bounds. It fills in the defaults of params,
+// and returns an error if any of them are not allowed.
+func (params *Params) Population(n int) ([]genetic.Individual, error) {
+	s, err := params.strategy()
+	if err != nil {
+		return nil, err
+	}
+	var out []genetic.Individual
+	for i := 0; i < n; i++ {
+		out = append(out, params.random(s))
+	}
+	return out, nil
+}
+
+func (params *Params) strategy() (*strategy, error) {
+	if params.Fitness == nil {
+		return nil, errors.New("fitness function cannot be nil")
+	}
+	n := len(params.Lower)
+	if n == 0 || len(params.Upper) != n {
+		return nil, errors.New("lower and upper bounds must have the same, non-zero length")
+	}
+	width := float64(0)
+	for i := range params.Lower {
+		if params.Upper[i] < params.Lower[i] {
+			return nil, fmt.Errorf("upper bound %d is below lower bound", i)
+		}
+		width += params.Upper[i] - params.Lower[i]
+	}
+	if params.StepSizes < OneStepSize || params.StepSizes > CorrelatedStepSizes {
+		return nil, fmt.Errorf("unknown step sizes %d", params.StepSizes)
+	}
+	if params.InitialSigma == 0 {
+		params.InitialSigma = width / float64(n) / 10
+	}
+	if params.MinSigma == 0 {
+		params.MinSigma = 1e-10
+	}
+	if params.InitialSigma <= 0 || params.MinSigma < 0 {
+		return nil, errors.New("step sizes must be positive")
+	}
+	if params.ObjectRecombination == nil {
+		discrete := Discrete
+		params.ObjectRecombination = &discrete
+	}
+	if params.StrategyRecombination == nil {
+		intermediate := Intermediate
+		params.StrategyRecombination = &intermediate
+	}
+	if params.Mu == 0 {
+		params.Mu = 15
+	}
+	if params.Lambda == 0 {
+		params.Lambda = 100
+	}
+	if params.Rho == 0 {
+		params.Rho = 2
+	}
+	if params.Mu < 1 || params.Lambda < 1 || params.Rho < 1 {
+		return nil, errors.New("mu, lambda and rho must be positive")
+	}
+	if !params.Plus && params.Lambda < params.Mu {
+		return nil, errors.New("lambda must be at least mu for (μ,λ) selection")
+	}
+
+	s := &strategy{
+		fitness:               params.Fitness,
+		stepSizes:             params.StepSizes,
+		minSigma:              params.MinSigma,
+		objectRecombination:   *params.ObjectRecombination,
+		strategyRecombination: *params.StrategyRecombination,
+		tau:                   1 / math.Sqrt(2*math.Sqrt(float64(n))),
+		tauPrime:              1 / math.Sqrt(2*float64(n)),
+		beta:                  0.0873,
+	}
+	if params.StepSizes == OneStepSize {
+		s.tau = 1 / math.Sqrt(float64(n))
+	}
+	return s, nil
+}
+
+func (params *Params) random(s *strategy) *Individual {
+	n := len(params.Lower)
+	ind := &Individual{
+		X:        make([]float64, n),
+		strategy: s,
+	}
+	for i := range ind.X {
+		ind.X[i] = params.Lower[i] + rand.Float64()*(params.Upper[i]-params.Lower[i])
+	}
+	if params.StepSizes == OneStepSize {
+		ind.Sigma = []float64{params.InitialSigma}
+	} else {
+		ind.Sigma = make([]float64, n)
+		for i := range ind.Sigma {
+			ind.Sigma[i] = params.InitialSigma
+		}
+	}
+	if params.StepSizes == CorrelatedStepSizes {
+		ind.Alpha = make([]float64, n*(n-1)/2)
+	}
+	return ind
+}
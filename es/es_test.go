@@ -0,0 +1,117 @@
+package es
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomjcleveland/genetic"
+)
+
+func sphere(x []float64) (float64, error) {
+	sum := float64(0)
+	for _, xi := range x {
+		sum += xi * xi
+	}
+	return -sum, nil
+}
+
+func Test_ES_Sphere_Run(t *testing.T) {
+	for name, stepSizes := range map[string]StepSizes{
+		"one step size": OneStepSize,
+		"per-gene":      PerGeneStepSizes,
+		"correlated":    CorrelatedStepSizes,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl, err := NewController(Params{
+				Fitness:       sphere,
+				Lower:         []float64{-5, -5, -5, -5},
+				Upper:         []float64{5, 5, 5, 5},
+				StepSizes:     stepSizes,
+				TargetFitness: -1e-6,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancelFunc()
+
+			ctrl.Start(ctx)
+			if err := ctrl.Wait(); err != nil {
+				t.Fatal(err)
+			}
+			fittest, err := ctrl.Fittest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			score, _ := fittest.Fitness()
+			assert.True(t, score >= -1e-6)
+		})
+	}
+}
+
+func Test_Mutate_StepSizesSelfAdapt(t *testing.T) {
+	params := Params{
+		Fitness:   sphere,
+		Lower:     []float64{0, 0, 0},
+		Upper:     []float64{1, 1, 1},
+		StepSizes: CorrelatedStepSizes,
+		MinSigma:  0.05,
+	}
+	pop, err := params.Population(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := pop[0].(*Individual)
+
+	mutant, err := parent.Mutate(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child := mutant.(*Individual)
+
+	assert.Len(t, child.Sigma, 3)
+	assert.Len(t, child.Alpha, 3)
+	assert.NotEqual(t, parent.Sigma, child.Sigma)
+	assert.NotEqual(t, parent.X, child.X)
+	for _, sigma := range child.Sigma {
+		assert.True(t, sigma >= 0.05)
+	}
+}
+
+func Test_MultiCrossover_Intermediate_ParentsAveraged(t *testing.T) {
+	intermediate := Intermediate
+	params := Params{
+		Fitness:             sphere,
+		Lower:               []float64{0, 0},
+		Upper:               []float64{1, 1},
+		ObjectRecombination: &intermediate,
+	}
+	pop, err := params.Population(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b, c := pop[0].(*Individual), pop[1].(*Individual), pop[2].(*Individual)
+
+	children, err := a.MultiCrossover([]genetic.Individual{b, c})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	child := children[0].(*Individual)
+	assert.InDelta(t, (a.X[0]+b.X[0]+c.X[0])/3, child.X[0], 1e-12)
+	assert.InDelta(t, (a.X[1]+b.X[1]+c.X[1])/3, child.X[1], 1e-12)
+}
+
+func Test_NewController_LambdaBelowMuWithComma_ErrNotNil(t *testing.T) {
+	_, err := NewController(Params{
+		Fitness: sphere,
+		Lower:   []float64{0},
+		Upper:   []float64{1},
+		Mu:      10,
+		Lambda:  5,
+	})
+
+	assert.NotNil(t, err)
+}
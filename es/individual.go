@@ -0,0 +1,157 @@
+package es
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Individual is a real-valued genome that carries its own mutation
+// step sizes, and optionally rotation angles, alongside the object
+// variables they apply to. It implements genetic.Individual and
+// genetic.MultiCrossover.
+type Individual struct {
+	// X holds the object variables.
+	X []float64
+
+	// Sigma holds the step sizes: a single one for every object
+	// variable, or one per variable.
+	Sigma []float64
+
+	// Alpha holds the rotation angles of correlated mutation, one for
+	// every pair of object variables. It is empty otherwise.
+	Alpha []float64
+
+	strategy *strategy
+}
+
+// strategy holds what the individuals of one search share.
+type strategy struct {
+	fitness               func([]float64) (float64, error)
+	stepSizes             StepSizes
+	minSigma              float64
+	objectRecombination   Recombination
+	strategyRecombination Recombination
+	tau, tauPrime, beta   float64
+}
+
+// Fitness implements genetic.Individual.
+func (ind *Individual) Fitness() (float64, error) {
+	return ind.strategy.fitness(ind.X)
+}
+
+// Mutate implements genetic.Individual. The step sizes are mutated
+// log-normally first, then the rotation angles, and finally the object
+// variables using the new step sizes. The rate is ignored; the step
+// sizes take its place.
+func (ind *Individual) Mutate(rate float64) (genetic.Individual, error) {
+	s := ind.strategy
+	out := &Individual{
+		X:        make([]float64, len(ind.X)),
+		Sigma:    make([]float64, len(ind.Sigma)),
+		Alpha:    make([]float64, len(ind.Alpha)),
+		strategy: s,
+	}
+
+	common := rand.NormFloat64()
+	for i, sigma := range ind.Sigma {
+		if len(ind.Sigma) == 1 {
+			sigma *= math.Exp(s.tau * common)
+		} else {
+			sigma *= math.Exp(s.tauPrime*common + s.tau*rand.NormFloat64())
+		}
+		out.Sigma[i] = math.Max(sigma, s.minSigma)
+	}
+	for i, alpha := range ind.Alpha {
+		alpha += s.beta * rand.NormFloat64()
+		if math.Abs(alpha) > math.Pi {
+			alpha -= 2 * math.Pi * math.Copysign(1, alpha)
+		}
+		out.Alpha[i] = alpha
+	}
+
+	delta := make([]float64, len(ind.X))
+	for i := range delta {
+		delta[i] = out.Sigma[i%len(out.Sigma)] * rand.NormFloat64()
+	}
+	rotate(delta, out.Alpha)
+	for i, x := range ind.X {
+		out.X[i] = x + delta[i]
+	}
+	return out, nil
+}
+
+// rotate applies the rotations of correlated mutation to the
+// uncorrelated mutation vector, one for every pair of variables.
+func rotate(delta, alpha []float64) {
+	if len(alpha) == 0 {
+		return
+	}
+	k := 0
+	for i := 0; i < len(delta)-1; i++ {
+		for j := i + 1; j < len(delta); j++ {
+			sin, cos := math.Sincos(alpha[k])
+			di, dj := delta[i], delta[j]
+			delta[i] = di*cos - dj*sin
+			delta[j] = di*sin + dj*cos
+			k++
+		}
+	}
+}
+
+// Crossover implements genetic.Individual by recombining with a
+// single partner.
+func (ind *Individual) Crossover(partner genetic.Individual) (genetic.Individual, error) {
+	children, err := ind.MultiCrossover([]genetic.Individual{partner})
+	if err != nil {
+		return nil, err
+	}
+	return children[0], nil
+}
+
+// MultiCrossover implements genetic.MultiCrossover. It recombines the
+// object variables and the strategy parameters of all the parents
+// separately, each with its own Recombination, into a single child.
+func (ind *Individual) MultiCrossover(mates []genetic.Individual) ([]genetic.Individual, error) {
+	parents := []*Individual{ind}
+	for _, mate := range mates {
+		parent, ok := mate.(*Individual)
+		if !ok {
+			return nil, fmt.Errorf("expected Individual to be *es.Individual, got %T", mate)
+		}
+		if len(parent.X) != len(ind.X) || len(parent.Sigma) != len(ind.Sigma) || len(parent.Alpha) != len(ind.Alpha) {
+			return nil, fmt.Errorf("can't recombine individuals of different shapes")
+		}
+		parents = append(parents, parent)
+	}
+	s := ind.strategy
+	child := &Individual{
+		X:        s.objectRecombination.combine(parents, func(p *Individual) []float64 { return p.X }),
+		Sigma:    s.strategyRecombination.combine(parents, func(p *Individual) []float64 { return p.Sigma }),
+		Alpha:    s.strategyRecombination.combine(parents, func(p *Individual) []float64 { return p.Alpha }),
+		strategy: s,
+	}
+	return []genetic.Individual{child}, nil
+}
+
+func (r Recombination) combine(parents []*Individual, genes func(*Individual) []float64) []float64 {
+	out := make([]float64, len(genes(parents[0])))
+	for i := range out {
+		switch r {
+		case Discrete:
+			out[i] = genes(parents[rand.Intn(len(parents))])[i]
+		default:
+			for _, p := range parents {
+				out[i] += genes(p)[i]
+			}
+			out[i] /= float64(len(parents))
+		}
+	}
+	return out
+}
+
+func (ind *Individual) String() string {
+	return fmt.Sprintf("x=%v σ=%v", ind.X, ind.Sigma)
+}
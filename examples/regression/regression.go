@@ -0,0 +1,66 @@
+package regression
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/tomjcleveland/genetic"
+	"github.com/tomjcleveland/genetic/gp"
+)
+
+// real is the only type of value our programs deal with.
+const real gp.Type = "real"
+
+// target is the function we're trying to get genetic
+// programming to rediscover from samples of it.
+func target(x float64) float64 {
+	return x*x*x*x + x*x*x + x*x + x
+}
+
+// samples are the points the programs are scored on.
+var samples = func() []float64 {
+	var out []float64
+	for x := -1.0; x <= 1; x += 0.1 {
+		out = append(out, x)
+	}
+	return out
+}()
+
+// fitness is the negated mean squared error on the samples.
+func fitness(t gp.Tree) (float64, error) {
+	sum := float64(0)
+	for _, x := range samples {
+		diff := t.Eval(gp.Env{"x": x}).(float64) - target(x)
+		sum += diff * diff
+	}
+	mse := sum / float64(len(samples))
+	if math.IsNaN(mse) || math.IsInf(mse, 0) {
+		return -math.MaxFloat32, nil
+	}
+	return -mse, nil
+}
+
+func params() gp.Params {
+	set := gp.NewPrimitiveSet(real)
+	set.AddPrimitive(gp.Arithmetic(real)...)
+	set.AddTerminal(
+		gp.Variable("x", real),
+		gp.EphemeralConstant("c", real, func() interface{} {
+			return float64(rand.Intn(5) - 2)
+		}),
+	)
+	return gp.Params{
+		Set:       set,
+		Fitness:   fitness,
+		Parsimony: 1e-6,
+	}
+}
+
+func testPopulation(n int) []genetic.Individual {
+	p := params()
+	pop, err := p.Population(n)
+	if err != nil {
+		panic(err)
+	}
+	return pop
+}
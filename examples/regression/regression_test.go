@@ -0,0 +1,38 @@
+package regression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomjcleveland/genetic"
+	"golang.org/x/net/context"
+)
+
+func Test_Regression_Run(t *testing.T) {
+	ctrl, err := genetic.NewController(genetic.Params{
+		Elitism:         2,
+		Mutation:        0.1,
+		Crossover:       0.9,
+		TargetFitness:   -1e-3,
+		Parallelism:     4,
+		SelectionMethod: genetic.Tournament(4),
+		InitPop:         testPopulation(500),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancelFunc()
+
+	ctrl.Start(ctx)
+	if err := ctrl.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	fittest, err := ctrl.Fittest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	score, _ := fittest.Fitness()
+	assert.True(t, score >= -1e-3)
+}
@@ -0,0 +1,4 @@
+// Package salesman solves the traveling salesman problem with the tsp
+// package, on cities that lie on a circle so that the optimal tour is
+// known. The search itself is in salesman_test.go; run it with go test.
+package salesman
@@ -1,26 +1,56 @@
 package salesman
 
 import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
 	"testing"
 	"time"
 
 	"golang.org/x/net/context"
 
-	"math/rand"
-
 	"github.com/tomjcleveland/genetic"
+	"github.com/tomjcleveland/genetic/tsp"
 )
 
+// circle returns an instance whose cities lie at random points on a
+// circle. Visiting them in order around the circle is the optimal tour,
+// so the search's gap to the optimum can be reported.
+func circle(t *testing.T, cities int) *tsp.Instance {
+	angles := make([]float64, cities)
+	for i := range angles {
+		angles[i] = 2 * math.Pi * rand.Float64()
+	}
+	coords := make([][2]float64, cities)
+	for i, angle := range angles {
+		coords[i] = [2]float64{1000 * math.Cos(angle), 1000 * math.Sin(angle)}
+	}
+	in := tsp.Euclidean(fmt.Sprintf("circle%d", cities), coords)
+
+	optimal := make([]int, cities)
+	for i := range optimal {
+		optimal[i] = i
+	}
+	sort.Slice(optimal, func(a, b int) bool { return angles[optimal[a]] < angles[optimal[b]] })
+	if err := in.SetOptimalTour(optimal); err != nil {
+		t.Fatal(err)
+	}
+	return in
+}
+
 func Test_Salesman_Run(t *testing.T) {
+	in := circle(t, 20)
 	ctrl, err := genetic.NewController(genetic.Params{
-		Elitism:          3,
-		Mutation:         0.8,
-		Crossover:        0.7,
-		TargetFitness:    10,
-		Parallelism:      10,
-		SelectionMethod:  genetic.Tournament(10),
-		InitPop:          testPopulation(50),
-		AdaptiveMutation: true,
+		Elitism:         3,
+		Mutation:        0.05,
+		Crossover:       0.7,
+		TargetFitness:   in.TargetFitness(1e-9),
+		Parallelism:     10,
+		SelectionMethod: genetic.Tournament(5),
+		Observer:        in.Observer(os.Stderr),
+		InitPop:         in.Population(100),
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -51,31 +81,3 @@ func Test_Salesman_Run(t *testing.T) {
 		t.Fatal(err)
 	}
 }
-
-func testPopulation(n int) []genetic.Individual {
-	mapScale := 50
-	numCities := 10
-	paths := make([]genetic.Individual, n)
-
-	for i := 0; i < n; i++ {
-		seen := make(map[city]bool)
-		cities := make([]*city, numCities)
-		for j := 0; j < numCities; j++ {
-			curr := &city{
-				x: rand.Intn(mapScale),
-				y: rand.Intn(mapScale),
-			}
-			for seen[*curr] {
-				curr = &city{
-					x: rand.Intn(mapScale),
-					y: rand.Intn(mapScale),
-				}
-			}
-			seen[*curr] = true
-			cities[j] = curr
-		}
-		paths[i] = genetic.Individual(path(cities))
-	}
-
-	return paths
-}
@@ -0,0 +1,310 @@
+// Package experiment compares configurations of the genetic algorithm
+// on a problem. Every configuration is run with the same seeds, and the
+// runs are summarized by best-of-run score, time to target and success
+// rate, and compared pairwise with the Mann–Whitney U and Wilcoxon
+// signed-rank tests. The results are written as CSV along with a
+// manifest of the parameters, seeds and build.
+package experiment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Config is a named configuration of the genetic algorithm.
+type Config struct {
+	Name string
+
+	// Params are the parameters of the configuration's runs. InitPop and
+	// Initializer are replaced by Population for every run, and Seed by
+	// the seed of the run. A run succeeds when it reaches TargetFitness, so
+	// Termination should be set.
+	Params genetic.Params
+
+	// Population builds the initial population of a run, as the run's
+	// Params.Initializer, so it's given a random source seeded with the
+	// seed of the run. Its size is Params.PopulationSize, or 100 if that
+	// isn't set.
+	Population genetic.Initializer
+
+	// Labels describe the configuration in the manifest, for the
+	// parameters that don't describe themselves, such as the
+	// selection method.
+	Labels map[string]string
+}
+
+// Params holds the parameters of an experiment.
+type Params struct {
+	Configs []Config
+
+	// Repetitions is how many times every configuration is run.
+	// The default is 10.
+	Repetitions int
+
+	// Seed is the seed of every configuration's first run, and the
+	// following runs use the following seeds, so runs with the same
+	// seed can be paired. The default is 1. Runs are only reproducible
+	// when the Individuals get their randomness from the seed too, by
+	// way of the random source Config.Population is given.
+	Seed int64
+
+	// Parallelism is how many runs are performed at once.
+	// The default is one.
+	Parallelism int
+
+	// Observer, if set, is told about every finished run. It's called
+	// from the goroutines that perform the runs.
+	Observer func(Run)
+}
+
+func (params *Params) validate() error {
+	if len(params.Configs) == 0 {
+		return errors.New("no configurations to run")
+	}
+	names := map[string]bool{}
+	for _, c := range params.Configs {
+		if c.Name == "" {
+			return errors.New("every configuration needs a name")
+		}
+		if names[c.Name] {
+			return fmt.Errorf("configuration name %q is used twice", c.Name)
+		}
+		names[c.Name] = true
+		if c.Population == nil {
+			return fmt.Errorf("configuration %q has no population function", c.Name)
+		}
+		if c.Params.Termination == (genetic.Termination{}) {
+			return fmt.Errorf("configuration %q needs a termination limit", c.Name)
+		}
+	}
+	if params.Repetitions == 0 {
+		params.Repetitions = 10
+	}
+	if params.Seed == 0 {
+		params.Seed = 1
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = 1
+	}
+	if params.Repetitions < 1 || params.Parallelism < 1 {
+		return errors.New("repetitions and parallelism must be positive")
+	}
+	return nil
+}
+
+// Run is the outcome of a single run.
+type Run struct {
+	Config     string
+	Repetition int
+	Seed       int64
+
+	// Best is the best score of the run.
+	Best float64
+
+	// Success says the run reached the target fitness, and TimeToTarget
+	// and EvaluationsToTarget say when. For unsuccessful runs they are
+	// the run's length.
+	Success             bool
+	TimeToTarget        time.Duration
+	EvaluationsToTarget int
+
+	Generations int
+	Evaluations int
+	Elapsed     time.Duration
+}
+
+// Experiment coordinates the runs of an experiment.
+type Experiment struct {
+	params Params
+	start  time.Time
+
+	// mu guards the runs, which are read while
+	// the experiment runs in its own goroutine.
+	mu   sync.RWMutex
+	runs []Run
+
+	err chan error
+}
+
+// NewExperiment validates the parameters, and returns an Experiment.
+func NewExperiment(params Params) (*Experiment, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &Experiment{params: params, err: make(chan error)}, nil
+}
+
+// Run performs every run of the experiment.
+func (e *Experiment) Run() error {
+	e.Start(context.Background())
+	return e.Wait()
+}
+
+// Start begins the experiment in a new goroutine, and returns
+// immediately. The context parameter can be used to prematurely
+// cancel it.
+func (e *Experiment) Start(ctx context.Context) {
+	go func() {
+		e.err <- e.run(ctx)
+	}()
+}
+
+// Wait blocks until the experiment has finished.
+func (e *Experiment) Wait() error {
+	if err, ok := <-e.err; ok {
+		return err
+	}
+	return errors.New("error channel is closed")
+}
+
+// job is a run to be performed.
+type job struct {
+	config     *Config
+	repetition int
+}
+
+func (e *Experiment) run(ctx context.Context) error {
+	e.start = time.Now()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Repetitions come first, so an experiment that is cancelled
+	// has about as many runs of every configuration.
+	jobs := make(chan job)
+	go func() {
+		defer close(jobs)
+		for rep := 0; rep < e.params.Repetitions; rep++ {
+			for i := range e.params.Configs {
+				select {
+				case jobs <- job{&e.params.Configs[i], rep}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var failure error
+	for w := 0; w < e.params.Parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				run, err := e.perform(ctx, j)
+				if err == genetic.ErrContextCancelled {
+					continue
+				}
+				if err != nil {
+					once.Do(func() {
+						failure = fmt.Errorf("configuration %q: %s", j.config.Name, err)
+						cancel()
+					})
+					continue
+				}
+				e.mu.Lock()
+				e.runs = append(e.runs, run)
+				e.mu.Unlock()
+				if e.params.Observer != nil {
+					e.params.Observer(run)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if failure != nil {
+		return failure
+	}
+	if ctx.Err() != nil {
+		return genetic.ErrContextCancelled
+	}
+	return nil
+}
+
+// perform runs a configuration once.
+func (e *Experiment) perform(ctx context.Context, j job) (Run, error) {
+	out := Run{Config: j.config.Name, Repetition: j.repetition, Seed: e.params.Seed + int64(j.repetition)}
+	params := j.config.Params
+	params.Seed = out.Seed
+	if params.PopulationSize == 0 {
+		params.PopulationSize = 100
+	}
+	params.InitPop = nil
+	params.Initializer = j.config.Population
+	c, err := genetic.NewController(params)
+	if err != nil {
+		return out, err
+	}
+	c.Start(ctx)
+	if err := c.Wait(); err != nil && err != genetic.ErrTerminated {
+		return out, err
+	}
+
+	stats := c.Stats()
+	last := stats[len(stats)-1]
+	out.Best = math.Inf(-1)
+	out.Generations = last.Generation
+	out.Evaluations = last.Evaluations
+	out.Elapsed = last.Elapsed
+	out.TimeToTarget = last.Elapsed
+	out.EvaluationsToTarget = last.Evaluations
+	for _, s := range stats {
+		out.Best = math.Max(out.Best, s.Best)
+		if !out.Success && s.Best >= params.TargetFitness {
+			out.Success = true
+			out.TimeToTarget = s.Elapsed
+			out.EvaluationsToTarget = s.Evaluations
+		}
+	}
+	return out, nil
+}
+
+// Results returns the runs so far, summarized and compared.
+func (e *Experiment) Results() *Results {
+	e.mu.RLock()
+	runs := append([]Run{}, e.runs...)
+	e.mu.RUnlock()
+	sort.Slice(runs, func(i, j int) bool {
+		if runs[i].Repetition != runs[j].Repetition {
+			return runs[i].Repetition < runs[j].Repetition
+		}
+		return e.index(runs[i].Config) < e.index(runs[j].Config)
+	})
+
+	out := &Results{Runs: runs, Manifest: e.manifest()}
+	for _, c := range e.params.Configs {
+		var mine []Run
+		for _, r := range runs {
+			if r.Config == c.Name {
+				mine = append(mine, r)
+			}
+		}
+		out.Summaries = append(out.Summaries, summarize(c.Name, mine))
+	}
+	for i := range e.params.Configs {
+		for j := i + 1; j < len(e.params.Configs); j++ {
+			a, b := e.params.Configs[i].Name, e.params.Configs[j].Name
+			scoresA, scoresB, paired := pairs(runs, a, b)
+			out.Comparisons = append(out.Comparisons, compare(a, b, scoresA, scoresB, paired))
+		}
+	}
+	return out
+}
+
+// index returns the position of the named configuration.
+func (e *Experiment) index(name string) int {
+	for i, c := range e.params.Configs {
+		if c.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,214 @@
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomjcleveland/genetic"
+	"github.com/tomjcleveland/genetic/benchmarks"
+)
+
+func config(name string, selection genetic.SelectionMethod) Config {
+	return Config{
+		Name: name,
+		Params: genetic.Params{
+			Elitism:         1,
+			Mutation:        0.03,
+			Crossover:       0.9,
+			SelectionMethod: selection,
+			TargetFitness:   30,
+			PopulationSize:  30,
+			Termination:     genetic.Termination{MaxGenerations: 15},
+		},
+		Population: oneMax,
+		Labels:     map[string]string{"selection": name},
+	}
+}
+
+// oneMax builds a population of a OneMax problem whose
+// individuals draw from the run's random source.
+func oneMax(size int, rng *rand.Rand) ([]genetic.Individual, error) {
+	p := benchmarks.OneMax(30)
+	p.Seed(rng.Int63())
+	return p.Population(size)
+}
+
+func Test_NewExperiment_InvalidParams_Error(t *testing.T) {
+	noPopulation := config("a", genetic.Roulette())
+	noPopulation.Population = nil
+	noTermination := config("a", genetic.Roulette())
+	noTermination.Params.Termination = genetic.Termination{}
+	tests := []struct {
+		name   string
+		params Params
+	}{
+		{"no configs", Params{}},
+		{"no name", Params{Configs: []Config{config("", genetic.Roulette())}}},
+		{"duplicate", Params{Configs: []Config{config("a", genetic.Roulette()), config("a", genetic.Roulette())}}},
+		{"no population", Params{Configs: []Config{noPopulation}}},
+		{"no termination", Params{Configs: []Config{noTermination}}},
+		{"repetitions", Params{Configs: []Config{config("a", genetic.Roulette())}, Repetitions: -1}},
+	}
+	for _, test := range tests {
+		_, err := NewExperiment(test.params)
+		assert.Error(t, err, test.name)
+	}
+}
+
+func Test_Experiment_Run_SummarizedAndCompared(t *testing.T) {
+	var mu sync.Mutex
+	observed := 0
+	e, err := NewExperiment(Params{
+		Configs:     []Config{config("tournament", genetic.Tournament(4)), config("roulette", genetic.Roulette())},
+		Repetitions: 4,
+		Seed:        10,
+		Parallelism: 3,
+		Observer: func(Run) {
+			mu.Lock()
+			observed++
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, e.Run())
+	assert.Equal(t, 8, observed)
+
+	results := e.Results()
+	assert.Len(t, results.Runs, 8)
+	for i, r := range results.Runs {
+		assert.Equal(t, i/2, r.Repetition)
+		assert.Equal(t, []string{"tournament", "roulette"}[i%2], r.Config)
+		assert.Equal(t, int64(10+i/2), r.Seed)
+		assert.True(t, r.TimeToTarget <= r.Elapsed)
+		assert.Equal(t, r.Best >= 30, r.Success)
+	}
+
+	assert.Len(t, results.Summaries, 2)
+	for _, s := range results.Summaries {
+		assert.Equal(t, 4, s.Runs)
+		assert.Equal(t, float64(s.Successes)/4, s.SuccessRate)
+		assert.True(t, s.MaxBest <= 30 && s.MeanBest <= s.MaxBest)
+	}
+
+	assert.Len(t, results.Comparisons, 1)
+	c := results.Comparisons[0]
+	assert.Equal(t, "tournament", c.A)
+	assert.Equal(t, "roulette", c.B)
+	assert.Equal(t, 4, c.Pairs)
+	assert.True(t, c.MannWhitney.P > 0 && c.MannWhitney.P <= 1)
+
+	m := results.Manifest
+	assert.Equal(t, []int64{10, 11, 12, 13}, m.Seeds)
+	assert.Equal(t, runtime.Version(), m.GoVersion)
+	assert.NotEmpty(t, m.GitRevision)
+	assert.Equal(t, int64(1), m.Configs[0].Params["Elitism"])
+	assert.Equal(t, "tournament", m.Configs[0].Labels["selection"])
+	assert.NotContains(t, m.Configs[0].Params, "SelectionMethod")
+	assert.NotContains(t, m.Configs[0].Params, "Seed")
+}
+
+func Test_Experiment_SameSeed_SameRuns(t *testing.T) {
+	var outcomes [][]Run
+	for i := 0; i < 2; i++ {
+		e, err := NewExperiment(Params{
+			Configs:     []Config{config("tournament", genetic.Tournament(4)), config("roulette", genetic.Roulette())},
+			Repetitions: 3,
+			Seed:        5,
+			Parallelism: 3,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NoError(t, e.Run())
+		var runs []Run
+		for _, r := range e.Results().Runs {
+			runs = append(runs, Run{Config: r.Config, Repetition: r.Repetition, Best: r.Best, Generations: r.Generations, Evaluations: r.Evaluations})
+		}
+		outcomes = append(outcomes, runs)
+	}
+	assert.Equal(t, outcomes[0], outcomes[1])
+}
+
+func Test_Results_Save_FilesWritten(t *testing.T) {
+	e, err := NewExperiment(Params{
+		Configs:     []Config{config("a", genetic.Tournament(2)), config("b", genetic.Tournament(3))},
+		Repetitions: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Run(); err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "experiment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	assert.NoError(t, e.Results().Save(filepath.Join(dir, "out")))
+
+	for name, rows := range map[string]int{"runs.csv": 5, "summary.csv": 3, "comparisons.csv": 2} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "out", name))
+		assert.NoError(t, err)
+		records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, rows, name)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "out", "manifest.json"))
+	assert.NoError(t, err)
+	var m Manifest
+	assert.NoError(t, json.Unmarshal(data, &m))
+	assert.Equal(t, 2, m.Repetitions)
+}
+
+func Test_Results_Manifest_InfiniteTarget(t *testing.T) {
+	c := config("a", genetic.Roulette())
+	c.Params.TargetFitness = math.Inf(1)
+	values := values(c.Params)
+	assert.Equal(t, "+Inf", values["TargetFitness"])
+	assert.Equal(t, map[string]interface{}{
+		"MaxGenerations": int64(15), "MaxEvaluations": int64(0), "MaxDuration": "0s", "Stagnation": int64(0),
+	}, values["Termination"])
+}
+
+func Test_Experiment_InvalidConfig_Error(t *testing.T) {
+	c := config("broken", genetic.Roulette())
+	c.Params.Crossover = 2
+	e, err := NewExperiment(Params{Configs: []Config{c}, Repetitions: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = e.Run()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "broken")
+}
+
+func Test_Experiment_Cancelled_ErrContextCancelled(t *testing.T) {
+	e, err := NewExperiment(Params{Configs: []Config{config("a", genetic.Roulette()), config("b", genetic.Roulette())}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	e.Start(ctx)
+	assert.Equal(t, genetic.ErrContextCancelled, e.Wait())
+
+	results := e.Results()
+	assert.Empty(t, results.Runs)
+	assert.Equal(t, 0, results.Summaries[0].Runs)
+	assert.True(t, math.IsNaN(results.Comparisons[0].MannWhitney.P))
+	assert.True(t, math.IsNaN(results.Comparisons[0].Wilcoxon.P))
+}
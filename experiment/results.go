@@ -0,0 +1,395 @@
+package experiment
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Results holds the runs of an experiment, summarized and compared.
+type Results struct {
+	// Runs are ordered by repetition, then configuration.
+	Runs []Run
+
+	// Summaries has one entry per configuration, in order.
+	Summaries []Summary
+
+	// Comparisons has one entry per pair of configurations.
+	Comparisons []Comparison
+
+	Manifest Manifest
+}
+
+// Summary aggregates the runs of a configuration.
+type Summary struct {
+	Config string
+	Runs   int
+
+	// Successes counts the runs that reached the target fitness.
+	Successes   int
+	SuccessRate float64
+
+	// MeanBest, MedianBest, StdDevBest and MaxBest summarize
+	// the best-of-run scores.
+	MeanBest   float64
+	MedianBest float64
+	StdDevBest float64
+	MaxBest    float64
+
+	// MeanTimeToTarget and MeanEvaluationsToTarget average over the
+	// successful runs, and are zero if there were none.
+	MeanTimeToTarget        time.Duration
+	MeanEvaluationsToTarget float64
+}
+
+func summarize(name string, runs []Run) Summary {
+	out := Summary{Config: name, Runs: len(runs)}
+	if len(runs) == 0 {
+		return out
+	}
+	best := make([]float64, len(runs))
+	var total time.Duration
+	for i, r := range runs {
+		best[i] = r.Best
+		out.MeanBest += r.Best
+		if r.Success {
+			out.Successes++
+			total += r.TimeToTarget
+			out.MeanEvaluationsToTarget += float64(r.EvaluationsToTarget)
+		}
+	}
+	n := float64(len(runs))
+	out.SuccessRate = float64(out.Successes) / n
+	out.MeanBest /= n
+	if out.Successes > 0 {
+		out.MeanTimeToTarget = total / time.Duration(out.Successes)
+		out.MeanEvaluationsToTarget /= float64(out.Successes)
+	}
+	sort.Float64s(best)
+	out.MaxBest = best[len(best)-1]
+	out.MedianBest = best[len(best)/2]
+	if len(best)%2 == 0 {
+		out.MedianBest = (best[len(best)/2-1] + best[len(best)/2]) / 2
+	}
+	if len(best) > 1 {
+		for _, b := range best {
+			out.StdDevBest += (b - out.MeanBest) * (b - out.MeanBest)
+		}
+		out.StdDevBest = math.Sqrt(out.StdDevBest / (n - 1))
+	}
+	return out
+}
+
+// Comparison compares the best-of-run scores of two configurations.
+// Positive effects mean A scored higher.
+type Comparison struct {
+	A, B string
+
+	// MannWhitney compares all of the runs of A and B.
+	MannWhitney Test
+
+	// Wilcoxon compares the runs of A and B paired by seed,
+	// leaving out seeds that only one of them was run with.
+	Wilcoxon Test
+	Pairs    int
+}
+
+// pairs returns the best-of-run scores of the two named configurations,
+// with their runs paired by seed at the front.
+func pairs(runs []Run, a, b string) (scoresA, scoresB []float64, paired int) {
+	bySeed := map[int64]float64{}
+	for _, r := range runs {
+		if r.Config == b {
+			bySeed[r.Seed] = r.Best
+		}
+	}
+	var unpairedA []float64
+	used := map[int64]bool{}
+	for _, r := range runs {
+		if r.Config != a {
+			continue
+		}
+		if best, ok := bySeed[r.Seed]; ok {
+			scoresA = append(scoresA, r.Best)
+			scoresB = append(scoresB, best)
+			used[r.Seed] = true
+		} else {
+			unpairedA = append(unpairedA, r.Best)
+		}
+	}
+	paired = len(scoresA)
+	scoresA = append(scoresA, unpairedA...)
+	for _, r := range runs {
+		if r.Config == b && !used[r.Seed] {
+			scoresB = append(scoresB, r.Best)
+		}
+	}
+	return scoresA, scoresB, paired
+}
+
+// compare tests the scores of two configurations. Tests that lack the
+// samples to be performed have a p-value of NaN.
+func compare(a, b string, scoresA, scoresB []float64, paired int) Comparison {
+	out := Comparison{A: a, B: b, Pairs: paired}
+	var err error
+	if out.MannWhitney, err = MannWhitney(scoresA, scoresB); err != nil {
+		out.MannWhitney.P = math.NaN()
+	}
+	if paired == 0 {
+		out.Wilcoxon.P = math.NaN()
+	} else {
+		out.Wilcoxon, _ = Wilcoxon(scoresA[:paired], scoresB[:paired])
+	}
+	return out
+}
+
+// Manifest records what an experiment ran, for reproducing it.
+type Manifest struct {
+	Configs     []ConfigManifest `json:"configs"`
+	Repetitions int              `json:"repetitions"`
+	Seeds       []int64          `json:"seeds"`
+	Parallelism int              `json:"parallelism"`
+	GitRevision string           `json:"gitRevision"`
+	GoVersion   string           `json:"goVersion"`
+	Started     time.Time        `json:"started"`
+	Elapsed     string           `json:"elapsed"`
+}
+
+// ConfigManifest records a configuration: the parameters that are
+// plain values, and its labels.
+type ConfigManifest struct {
+	Name   string                 `json:"name"`
+	Params map[string]interface{} `json:"params"`
+	Labels map[string]string      `json:"labels,omitempty"`
+}
+
+func (e *Experiment) manifest() Manifest {
+	out := Manifest{
+		Repetitions: e.params.Repetitions,
+		Parallelism: e.params.Parallelism,
+		GitRevision: revision(),
+		GoVersion:   runtime.Version(),
+		Started:     e.start,
+		Elapsed:     time.Since(e.start).Round(time.Millisecond).String(),
+	}
+	for i := 0; i < e.params.Repetitions; i++ {
+		out.Seeds = append(out.Seeds, e.params.Seed+int64(i))
+	}
+	for _, c := range e.params.Configs {
+		out.Configs = append(out.Configs, ConfigManifest{Name: c.Name, Params: values(c.Params), Labels: c.Labels})
+	}
+	return out
+}
+
+// values returns the fields of params that are plain values. Seed and
+// InitPop are left out, since they change from run to run.
+func values(params genetic.Params) map[string]interface{} {
+	out := map[string]interface{}{}
+	v := reflect.ValueOf(params)
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		if name == "Seed" || name == "InitPop" {
+			continue
+		}
+		if value, ok := plain(v.Field(i)); ok {
+			out[name] = value
+		}
+	}
+	return out
+}
+
+// plain returns a value that JSON can encode, if v is a number, a
+// string, a boolean or a struct of them. Infinite and NaN numbers
+// become strings.
+func plain(v reflect.Value) (interface{}, bool) {
+	switch v.Kind() {
+	case reflect.Bool, reflect.String:
+		return v.Interface(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if d, ok := v.Interface().(time.Duration); ok {
+			return d.String(), true
+		}
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Sprint(f), true
+		}
+		return v.Float(), true
+	case reflect.Struct:
+		out := map[string]interface{}{}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			value, ok := plain(v.Field(i))
+			if !ok {
+				return nil, false
+			}
+			out[v.Type().Field(i).Name] = value
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// revision returns the git revision the binary was built from, or
+// else the revision of the working directory, or "unknown".
+func revision() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		rev, modified := "", false
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				rev = s.Value
+			case "vcs.modified":
+				modified = s.Value == "true"
+			}
+		}
+		if rev != "" {
+			if modified {
+				rev += "-dirty"
+			}
+			return rev
+		}
+	}
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// WriteRuns writes every run as a row of CSV.
+func (r *Results) WriteRuns(w io.Writer) error {
+	rows := [][]string{{"config", "repetition", "seed", "best", "success", "time_to_target_ms",
+		"evaluations_to_target", "generations", "evaluations", "elapsed_ms"}}
+	for _, run := range r.Runs {
+		rows = append(rows, []string{
+			run.Config,
+			strconv.Itoa(run.Repetition),
+			strconv.FormatInt(run.Seed, 10),
+			float(run.Best),
+			strconv.FormatBool(run.Success),
+			milliseconds(run.TimeToTarget),
+			strconv.Itoa(run.EvaluationsToTarget),
+			strconv.Itoa(run.Generations),
+			strconv.Itoa(run.Evaluations),
+			milliseconds(run.Elapsed),
+		})
+	}
+	return writeCSV(w, rows)
+}
+
+// WriteSummaries writes the summary of every configuration as a row of CSV.
+func (r *Results) WriteSummaries(w io.Writer) error {
+	rows := [][]string{{"config", "runs", "successes", "success_rate", "mean_best", "median_best",
+		"stddev_best", "max_best", "mean_time_to_target_ms", "mean_evaluations_to_target"}}
+	for _, s := range r.Summaries {
+		rows = append(rows, []string{
+			s.Config,
+			strconv.Itoa(s.Runs),
+			strconv.Itoa(s.Successes),
+			float(s.SuccessRate),
+			float(s.MeanBest),
+			float(s.MedianBest),
+			float(s.StdDevBest),
+			float(s.MaxBest),
+			milliseconds(s.MeanTimeToTarget),
+			float(s.MeanEvaluationsToTarget),
+		})
+	}
+	return writeCSV(w, rows)
+}
+
+// WriteComparisons writes every comparison as a row of CSV.
+func (r *Results) WriteComparisons(w io.Writer) error {
+	rows := [][]string{{"a", "b", "mann_whitney_u", "mann_whitney_p", "mann_whitney_effect",
+		"pairs", "wilcoxon_w", "wilcoxon_p", "wilcoxon_effect"}}
+	for _, c := range r.Comparisons {
+		rows = append(rows, []string{
+			c.A,
+			c.B,
+			float(c.MannWhitney.Statistic),
+			float(c.MannWhitney.P),
+			float(c.MannWhitney.Effect),
+			strconv.Itoa(c.Pairs),
+			float(c.Wilcoxon.Statistic),
+			float(c.Wilcoxon.P),
+			float(c.Wilcoxon.Effect),
+		})
+	}
+	return writeCSV(w, rows)
+}
+
+// WriteManifest writes the manifest as JSON.
+func (r *Results) WriteManifest(w io.Writer) error {
+	data, err := json.MarshalIndent(r.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Save writes runs.csv, summary.csv, comparisons.csv and
+// manifest.json to the given directory, creating it if needed.
+func (r *Results) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"runs.csv", r.WriteRuns},
+		{"summary.csv", r.WriteSummaries},
+		{"comparisons.csv", r.WriteComparisons},
+		{"manifest.json", r.WriteManifest},
+	}
+	for _, file := range files {
+		f, err := os.Create(filepath.Join(dir, file.name))
+		if err != nil {
+			return err
+		}
+		err = file.write(f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %s", file.name, err)
+		}
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, rows [][]string) error {
+	out := csv.NewWriter(w)
+	if err := out.WriteAll(rows); err != nil {
+		return err
+	}
+	return out.Error()
+}
+
+func float(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func milliseconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds()*1000, 'f', 3, 64)
+}
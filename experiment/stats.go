@@ -0,0 +1,129 @@
+package experiment
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Test is the outcome of a two-sample rank test. P is two-sided, and
+// uses the normal approximation with a correction for ties, which is
+// close for ten or more samples per side.
+type Test struct {
+	// Statistic is U for the Mann–Whitney test, and the sum of the
+	// positive ranks, W+, for the Wilcoxon test.
+	Statistic float64
+	Z         float64
+	P         float64
+
+	// Effect is the size of the difference, between -1 and 1, positive
+	// when the first sample tends to be larger: the rank-biserial
+	// correlation for the Mann–Whitney test, and the matched-pairs
+	// rank-biserial correlation for the Wilcoxon test.
+	Effect float64
+}
+
+// ranks returns the ranks of the values, starting at one, with tied
+// values sharing their average rank. It also returns the sum of t³-t
+// over every group of t ties.
+func ranks(values []float64) ([]float64, float64) {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return values[order[i]] < values[order[j]] })
+	out := make([]float64, len(values))
+	ties := 0.0
+	for i := 0; i < len(order); {
+		j := i
+		for j < len(order) && values[order[j]] == values[order[i]] {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			out[order[k]] = rank
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+	return out, ties
+}
+
+// pValue returns the two-sided p-value of a standard normal z.
+func pValue(z float64) float64 {
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// continuity moves a deviation from the mean half a unit towards zero.
+func continuity(d float64) float64 {
+	switch {
+	case d > 0.5:
+		return d - 0.5
+	case d < -0.5:
+		return d + 0.5
+	}
+	return 0
+}
+
+// MannWhitney performs the Mann–Whitney U test of whether the values
+// of a tend to differ from the values of b. U counts the pairs in which
+// a's value is larger, with ties counting half. The samples needn't be
+// the same size.
+func MannWhitney(a, b []float64) (Test, error) {
+	if len(a) == 0 || len(b) == 0 {
+		return Test{}, errors.New("both samples need values")
+	}
+	n1, n2 := float64(len(a)), float64(len(b))
+	r, ties := ranks(append(append([]float64{}, a...), b...))
+	sum := 0.0
+	for _, rank := range r[:len(a)] {
+		sum += rank
+	}
+	u := sum - n1*(n1+1)/2
+	out := Test{Statistic: u, Effect: 2*u/(n1*n2) - 1, P: 1}
+	n := n1 + n2
+	variance := n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1)))
+	if variance > 0 {
+		out.Z = continuity(u-n1*n2/2) / math.Sqrt(variance)
+		out.P = pValue(out.Z)
+	}
+	return out, nil
+}
+
+// Wilcoxon performs the Wilcoxon signed-rank test of whether the
+// differences between paired values of a and b are centred on zero.
+// Pairs with no difference are dropped.
+func Wilcoxon(a, b []float64) (Test, error) {
+	if len(a) != len(b) {
+		return Test{}, errors.New("paired samples must be the same size")
+	}
+	var diffs, abs []float64
+	for i := range a {
+		if d := a[i] - b[i]; d != 0 {
+			diffs = append(diffs, d)
+			abs = append(abs, math.Abs(d))
+		}
+	}
+	out := Test{P: 1}
+	if len(diffs) == 0 {
+		return out, nil
+	}
+	r, ties := ranks(abs)
+	negative := 0.0
+	for i, d := range diffs {
+		if d > 0 {
+			out.Statistic += r[i]
+		} else {
+			negative += r[i]
+		}
+	}
+	n := float64(len(diffs))
+	out.Effect = (out.Statistic - negative) / (out.Statistic + negative)
+	variance := n*(n+1)*(2*n+1)/24 - ties/48
+	if variance > 0 {
+		out.Z = continuity(out.Statistic-n*(n+1)/4) / math.Sqrt(variance)
+		out.P = pValue(out.Z)
+	}
+	return out, nil
+}
@@ -0,0 +1,72 @@
+package experiment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ranks_Ties_AverageRanks(t *testing.T) {
+	r, ties := ranks([]float64{3, 1, 2, 2, 3, 3})
+	assert.Equal(t, []float64{5, 1, 2.5, 2.5, 5, 5}, r)
+	assert.Equal(t, 6.0+24.0, ties)
+}
+
+func Test_MannWhitney_Separated_Significant(t *testing.T) {
+	test, err := MannWhitney([]float64{1, 2, 3}, []float64{4, 5, 6})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, test.Statistic)
+	assert.Equal(t, -1.0, test.Effect)
+	assert.InDelta(t, -1.746, test.Z, 1e-3)
+	assert.InDelta(t, 0.0809, test.P, 1e-4)
+
+	test, err = MannWhitney([]float64{4, 5, 6}, []float64{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 9.0, test.Statistic)
+	assert.Equal(t, 1.0, test.Effect)
+}
+
+func Test_MannWhitney_Ties_Counted(t *testing.T) {
+	test, err := MannWhitney([]float64{1, 2, 2}, []float64{2, 3, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, test.Statistic)
+	assert.InDelta(t, -7.0/9, test.Effect, 1e-9)
+
+	test, err = MannWhitney([]float64{1, 1}, []float64{1, 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, test.P)
+	assert.Equal(t, 0.0, test.Effect)
+}
+
+func Test_MannWhitney_Empty_Error(t *testing.T) {
+	_, err := MannWhitney(nil, []float64{1})
+	assert.Error(t, err)
+}
+
+func Test_Wilcoxon_Shifted_Significant(t *testing.T) {
+	test, err := Wilcoxon([]float64{5, 6, 7, 8}, []float64{1, 2, 3, 4})
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, test.Statistic)
+	assert.Equal(t, 1.0, test.Effect)
+	assert.InDelta(t, 1.8, test.Z, 1e-9)
+	assert.InDelta(t, 0.0719, test.P, 1e-4)
+}
+
+func Test_Wilcoxon_Mixed_Effect(t *testing.T) {
+	test, err := Wilcoxon([]float64{1, 5, 3, 7}, []float64{2, 3, 3, 4})
+	assert.NoError(t, err)
+	// Differences -1, 2, 0 and 3 have ranks 1, 2 and 3.
+	assert.Equal(t, 5.0, test.Statistic)
+	assert.InDelta(t, 4.0/6, test.Effect, 1e-9)
+}
+
+func Test_Wilcoxon_NoDifferences_NotSignificant(t *testing.T) {
+	test, err := Wilcoxon([]float64{1, 2}, []float64{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, test.P)
+}
+
+func Test_Wilcoxon_UnequalSizes_Error(t *testing.T) {
+	_, err := Wilcoxon([]float64{1, 2}, []float64{1})
+	assert.Error(t, err)
+}
@@ -19,7 +19,7 @@ func (fi fakeIndividual) Crossover(ind Individual) (Individual, error) {
 	return fi, fi.err
 }
 
-func (fi fakeIndividual) Mutate() (Individual, error) {
+func (fi fakeIndividual) Mutate(rate float64) (Individual, error) {
 	fi.mutateCount++
 	return fi, fi.err
 }
@@ -28,3 +28,52 @@ func (fi fakeIndividual) Fitness() (float64, error) {
 	fi.fitnessCount++
 	return fi.fitness, fi.err
 }
+
+// fakePairIndividual is a fakeIndividual that also implements PairCrossover
+type fakePairIndividual struct {
+	fakeIndividual
+}
+
+func (fi fakePairIndividual) PairCrossover(ind Individual) (Individual, Individual, error) {
+	return fi, ind, fi.err
+}
+
+// fakeMultiIndividual is a fakeIndividual that also implements MultiCrossover,
+// returning a copy of itself for every parent.
+type fakeMultiIndividual struct {
+	fakeIndividual
+}
+
+func (fi fakeMultiIndividual) MultiCrossover(mates []Individual) ([]Individual, error) {
+	out := []Individual{fi}
+	out = append(out, mates...)
+	return out, fi.err
+}
+
+// fakeVector is a RealVector whose fitness is the negated
+// sphere function, with its optimum at the origin.
+type fakeVector []float64
+
+func (fv fakeVector) Crossover(ind Individual) (Individual, error) {
+	return fv, nil
+}
+
+func (fv fakeVector) Mutate(rate float64) (Individual, error) {
+	return fv, nil
+}
+
+func (fv fakeVector) Fitness() (float64, error) {
+	sum := float64(0)
+	for _, x := range fv {
+		sum += x * x
+	}
+	return -sum, nil
+}
+
+func (fv fakeVector) Vector() []float64 {
+	return fv
+}
+
+func (fv fakeVector) WithVector(v []float64) Individual {
+	return fakeVector(v)
+}
@@ -1,51 +1,97 @@
 package genetic
 
-import "sort"
+import (
+	"sort"
+	"time"
+)
 
 type result struct {
-	result indWithScore
-	err    error
+	index int
+	score float64
+	err   error
 }
 
-func calculateFitnessConcurrently(in []indWithScore, workers int) (out []indWithScore, err error) {
+// EvaluateConcurrently scores n candidates with the given fitness
+// function, using the given number of goroutines, and returns the
+// scores in order. It is the evaluation step the Controller uses, made
+// available to other optimizers. The first error stops the evaluation.
+func EvaluateConcurrently(n, workers int, fitness func(i int) (float64, error)) ([]float64, error) {
+	return evaluateConcurrently(n, workers, nil, fitness)
+}
+
+// evaluateConcurrently is EvaluateConcurrently, recording the
+// latency of every evaluation in metrics, if they're set.
+func evaluateConcurrently(n, workers int, metrics *Metrics, fitness func(i int) (float64, error)) ([]float64, error) {
+	if metrics != nil {
+		start := time.Now()
+		defer func() { metrics.evaluating(time.Since(start)) }()
+		inner := fitness
+		fitness = func(i int) (float64, error) {
+			begin := time.Now()
+			score, err := inner(i)
+			metrics.evaluated(time.Since(begin), err)
+			return score, err
+		}
+	}
 	if workers < 1 {
 		workers = 1
 	}
-	jobs := make(chan indWithScore)
+	jobs := make(chan int)
 	results := make(chan result, workers)
+	done := make(chan struct{})
+	defer close(done)
 
 	// Spin up workers
 	for i := 0; i < workers; i++ {
 		go func() {
-			for {
-				job, ok := <-jobs
-				if !ok {
-					break
+			for job := range jobs {
+				score, err := fitness(job)
+				select {
+				case results <- result{index: job, score: score, err: err}:
+				case <-done:
+					return
 				}
-				score, err := job.Individual.Fitness()
-				job.score = score
-				results <- result{result: job, err: err}
 			}
 		}()
 	}
 
 	// Add jobs
 	go func() {
-		for _, ind := range in {
-			jobs <- ind
+		defer close(jobs)
+		for i := 0; i < n; i++ {
+			select {
+			case jobs <- i:
+			case <-done:
+				return
+			}
 		}
-		close(jobs)
 	}()
 
 	// Get results
-	for i := 0; i < len(in); i++ {
+	out := make([]float64, n)
+	for i := 0; i < n; i++ {
 		outcome := <-results
 		if outcome.err != nil {
-			return nil, err
+			return nil, outcome.err
 		}
-		out = append(out, outcome.result)
+		out[outcome.index] = outcome.score
+	}
+
+	return out, nil
+}
+
+func calculateFitnessConcurrently(in []indWithScore, workers int, metrics *Metrics) ([]indWithScore, error) {
+	scores, err := evaluateConcurrently(len(in), workers, metrics, func(i int) (float64, error) {
+		return in[i].Individual.Fitness()
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]indWithScore, len(in))
+	for i, ind := range in {
+		out[i] = ind
+		out[i].score = scores[i]
 	}
-	close(results)
 	sort.Sort(sort.Reverse(pairs(out)))
 
 	return out, nil
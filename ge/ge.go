@@ -0,0 +1,180 @@
+// Package ge implements grammatical evolution on top of
+// genetic.Controller. Genomes are variable-length lists of integer
+// codons, which are mapped to programs, configurations or any other
+// text described by a BNF grammar, so every phenotype is
+// syntactically valid by construction.
+package ge
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Initialization says how the initial genomes are made.
+type Initialization int
+
+const (
+	// Sensible initialization builds derivation trees with ramped
+	// half-and-half, and encodes them as genomes. It's the default.
+	Sensible Initialization = iota
+
+	// PIGrow builds derivation trees of ramped depths with
+	// position independent grow, and encodes them as genomes.
+	PIGrow
+
+	// RandomCodons draws every codon of every genome at random.
+	RandomCodons
+)
+
+// Params holds all of the parameters for grammatical evolution.
+type Params struct {
+	// Grammar describes the phenotypes.
+	Grammar *Grammar
+
+	// Fitness scores a phenotype. As elsewhere in this package,
+	// higher is better.
+	Fitness func(phenotype string) (float64, error)
+
+	// CodonSize is the number of values a codon can take. It must be
+	// at least the number of productions of every rule. The default
+	// is 256.
+	CodonSize int
+
+	// Wraps is how many times mapping may wrap around the genome.
+	Wraps int
+
+	// MaxDepth is the maximum depth of derivation trees. The
+	// default is 17.
+	MaxDepth int
+
+	// InvalidScore is the score of genomes that don't map to a complete
+	// derivation. It should be lower than any real score. The default
+	// is -math.MaxFloat32.
+	InvalidScore float64
+
+	// Initialization says how the initial genomes are made.
+	Initialization Initialization
+
+	// MinInitDepth and MaxInitDepth bound the depths of the initial
+	// derivation trees. The defaults are the smallest depth the grammar
+	// allows, and five more than that, but no more than MaxDepth.
+	MinInitDepth int
+	MaxInitDepth int
+
+	// Tail is the length of the random tail added to initial genomes,
+	// as a fraction of the codons their derivations use. The default
+	// is 0.5.
+	Tail float64
+
+	// GenomeLength is the length of RandomCodons genomes. The
+	// default is 100.
+	GenomeLength int
+}
+
+// Population returns n initial individuals. It fills in the defaults
+// of params, and returns an error if any of them are not allowed.
+func (params *Params) Population(n int) ([]genetic.Individual, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	p := *params
+	out := make([]genetic.Individual, n)
+	for i := range out {
+		out[i] = &Individual{Codons: p.genome(i), params: &p}
+	}
+	return out, nil
+}
+
+// Generator returns a genetic.Generator of individuals made like those
+// of Population. It fills in the defaults of params, and returns an
+// error if any of them are not allowed.
+func (params *Params) Generator() (genetic.Generator, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	p := *params
+	i := 0
+	return func() (genetic.Individual, error) {
+		i++
+		return &Individual{Codons: p.genome(i), params: &p}, nil
+	}, nil
+}
+
+// genome returns the i-th initial genome. Depths are ramped over
+// consecutive genomes, and sensible initialization alternates between
+// full and grow at every depth.
+func (params *Params) genome(i int) []int {
+	if params.Initialization == RandomCodons {
+		out := make([]int, params.GenomeLength)
+		for j := range out {
+			out[j] = rand.Intn(params.CodonSize)
+		}
+		return out
+	}
+	g := params.Grammar
+	depth := params.MinInitDepth + (i/2)%(params.MaxInitDepth-params.MinInitDepth+1)
+	var d *derivation
+	if params.Initialization == PIGrow {
+		d = g.piGrow(depth)
+	} else {
+		d = g.sensible(depth, i%2 == 0)
+	}
+	used := len(g.genome(d, params.CodonSize, 0))
+	return g.genome(d, params.CodonSize, int(math.Ceil(params.Tail*float64(used))))
+}
+
+func (params *Params) validate() error {
+	if params.Grammar == nil {
+		return errors.New("grammar cannot be nil")
+	}
+	if params.Fitness == nil {
+		return errors.New("fitness function cannot be nil")
+	}
+	g := params.Grammar
+	if params.CodonSize == 0 {
+		params.CodonSize = 256
+	}
+	if params.MaxDepth == 0 {
+		params.MaxDepth = 17
+	}
+	if params.InvalidScore == 0 {
+		params.InvalidScore = -math.MaxFloat32
+	}
+	if params.MinInitDepth == 0 {
+		params.MinInitDepth = g.minDepth[g.Start]
+	}
+	if params.MaxInitDepth == 0 {
+		params.MaxInitDepth = params.MinInitDepth + 5
+		if params.MaxInitDepth > params.MaxDepth {
+			params.MaxInitDepth = params.MaxDepth
+		}
+	}
+	if params.Tail == 0 {
+		params.Tail = 0.5
+	}
+	if params.GenomeLength == 0 {
+		params.GenomeLength = 100
+	}
+	for name, prods := range g.Rules {
+		if len(prods) > params.CodonSize {
+			return fmt.Errorf("codon size %d is smaller than the %d productions of <%s>", params.CodonSize, len(prods), name)
+		}
+	}
+	if params.Wraps < 0 {
+		return errors.New("wraps cannot be negative")
+	}
+	if params.Initialization < Sensible || params.Initialization > RandomCodons {
+		return fmt.Errorf("unknown initialization %d", params.Initialization)
+	}
+	if params.MinInitDepth < g.minDepth[g.Start] || params.MaxInitDepth < params.MinInitDepth || params.MaxInitDepth > params.MaxDepth {
+		return fmt.Errorf("initial depths must be between %d, the smallest the grammar allows, and the maximum depth", g.minDepth[g.Start])
+	}
+	if params.Tail < 0 || params.GenomeLength < 1 {
+		return errors.New("tail cannot be negative, and genome length must be positive")
+	}
+	return nil
+}
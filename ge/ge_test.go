@@ -0,0 +1,206 @@
+package ge
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomjcleveland/genetic"
+)
+
+func zero(string) (float64, error) {
+	return 0, nil
+}
+
+func Test_Map(t *testing.T) {
+	g, err := ParseGrammar(expressions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// expr -> expr op expr, expr -> var -> x, op -> *, expr -> var -> 1.0
+	out, used, err := g.Map([]int{0, 2, 0, 5, 2, 1}, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "x * 1.0", out)
+	assert.Equal(t, 6, used)
+}
+
+func Test_Map_Wrapping(t *testing.T) {
+	g, err := ParseGrammar(expressions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// expr -> (expr), expr -> var -> 1.0, using the first codon twice
+	_, _, err = g.Map([]int{1, 2}, 0, 0)
+	assert.Equal(t, ErrInvalid, err)
+
+	out, used, err := g.Map([]int{1, 2}, 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "(1.0)", out)
+	assert.Equal(t, 3, used)
+}
+
+func Test_Map_MaxDepth_Invalid(t *testing.T) {
+	g, err := ParseGrammar(expressions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// (((x)))
+	codons := []int{1, 1, 1, 2, 0}
+	_, _, err = g.Map(codons, 0, 4)
+	assert.Equal(t, ErrInvalid, err)
+	out, _, err := g.Map(codons, 0, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "(((x)))", out)
+}
+
+func Test_Population_ValidAndWithinDepth(t *testing.T) {
+	g, err := ParseGrammar(expressions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, init := range map[string]Initialization{
+		"sensible": Sensible,
+		"pi grow":  PIGrow,
+	} {
+		t.Run(name, func(t *testing.T) {
+			params := Params{Grammar: g, Fitness: zero, Initialization: init, MaxInitDepth: 6}
+			pop, err := params.Population(100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, ind := range pop {
+				_, err := ind.(*Individual).Phenotype()
+				assert.NoError(t, err)
+				_, _, err = g.Map(ind.(*Individual).Codons, 0, 6)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_PIGrow_ReachesDepth(t *testing.T) {
+	g, err := ParseGrammar(expressions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		codons := g.genome(g.piGrow(5), 256, 0)
+		_, _, err := g.Map(codons, 0, 4)
+		assert.Equal(t, ErrInvalid, err)
+	}
+}
+
+func Test_PairCrossover_CutsWithinUsedCodons(t *testing.T) {
+	g, err := ParseGrammar(expressions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := &Params{Grammar: g, Fitness: zero}
+	if err := params.validate(); err != nil {
+		t.Fatal(err)
+	}
+	a := &Individual{Codons: []int{2, 0, 7, 7, 7}, params: params}
+	b := &Individual{Codons: []int{2, 1, 9, 9, 9}, params: params}
+	for i := 0; i < 20; i++ {
+		c, d, err := a.PairCrossover(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 10, len(c.(*Individual).Codons)+len(d.(*Individual).Codons))
+		// Only the first two codons are used, so the unused tails are swapped whole
+		assert.Equal(t, []int{9, 9, 9}, c.(*Individual).Codons[len(c.(*Individual).Codons)-3:])
+		assert.Equal(t, []int{7, 7, 7}, d.(*Individual).Codons[len(d.(*Individual).Codons)-3:])
+	}
+}
+
+func Test_Mutate_LeavesParentAlone(t *testing.T) {
+	params := &Params{Grammar: &Grammar{}, CodonSize: 256}
+	ind := &Individual{Codons: []int{1, 2, 3}, params: params}
+	mutant, err := ind.Mutate(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []int{1, 2, 3}, ind.Codons)
+	assert.Equal(t, 3, len(mutant.(*Individual).Codons))
+}
+
+func Test_Params_Invalid_Error(t *testing.T) {
+	g, err := ParseGrammar(expressions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]Params{
+		"no grammar":        {Fitness: zero},
+		"no fitness":        {Grammar: g},
+		"small codons":      {Grammar: g, Fitness: zero, CodonSize: 2},
+		"negative wraps":    {Grammar: g, Fitness: zero, Wraps: -1},
+		"shallow init":      {Grammar: g, Fitness: zero, MinInitDepth: 1},
+		"deep init":         {Grammar: g, Fitness: zero, MaxInitDepth: 30},
+		"unknown init":      {Grammar: g, Fitness: zero, Initialization: 5},
+		"negative tail":     {Grammar: g, Fitness: zero, Tail: -1},
+		"negative genome":   {Grammar: g, Fitness: zero, GenomeLength: -1},
+		"inverted init":     {Grammar: g, Fitness: zero, MinInitDepth: 6, MaxInitDepth: 4},
+		"init beyond limit": {Grammar: g, Fitness: zero, MaxDepth: 5, MaxInitDepth: 6},
+	}
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := params.Population(10)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_GE_Run(t *testing.T) {
+	g, err := ParseGrammar(`
+<word>   ::= <letter> | <letter><word>
+<letter> ::= a | b | c | d
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := "abcdcba"
+	params := Params{
+		Grammar: g,
+		Wraps:   2,
+		Fitness: func(s string) (float64, error) {
+			score := -float64(len(s) - len(target))
+			if score > 0 {
+				score = -score
+			}
+			for i := 0; i < len(s) && i < len(target); i++ {
+				if s[i] != target[i] {
+					score--
+				}
+			}
+			return score, nil
+		},
+	}
+	pop, err := params.Population(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctrl, err := genetic.NewController(genetic.Params{
+		Elitism:         2,
+		Mutation:        0.05,
+		Crossover:       0.9,
+		SelectionMethod: genetic.Tournament(4),
+		InitPop:         pop,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFunc()
+
+	ctrl.Start(ctx)
+	if err := ctrl.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	fittest, err := ctrl.Fittest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, target, strings.TrimSpace(fittest.(*Individual).String()))
+}
@@ -0,0 +1,267 @@
+package ge
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Symbol is one symbol of a production: either a non-terminal, named
+// without its angle brackets, or literal text.
+type Symbol struct {
+	Text        string
+	NonTerminal bool
+
+	quoted bool
+}
+
+// Production is one of the alternatives a rule can expand to.
+type Production []Symbol
+
+// Grammar is a context-free grammar in Backus-Naur form.
+type Grammar struct {
+	// Start is the non-terminal derivations start from: the one
+	// defined first.
+	Start string
+
+	// Rules holds the productions of every non-terminal.
+	Rules map[string][]Production
+
+	// minDepth is the depth of the shallowest derivation tree
+	// of every non-terminal, and recursive says which of them
+	// can derive themselves.
+	minDepth  map[string]int
+	recursive map[string]bool
+}
+
+// ruleStart matches the beginning of a rule definition.
+var ruleStart = regexp.MustCompile(`^\s*<([^>]+)>\s*::=`)
+
+// whitespace matches the runs of whitespace in unquoted literals.
+var whitespace = regexp.MustCompile(`\s+`)
+
+// ParseGrammar parses a grammar in BNF. Every rule starts on a new line
+// with "<name> ::=", and may continue over the following lines, with
+// productions separated by "|". Text in productions that is not a
+// non-terminal is literal, with runs of whitespace collapsed into a
+// single space and whitespace at either end removed; text that needs
+// to be kept exactly, or includes "|" or "<", can be quoted with
+// single or double quotes. Lines starting with "#" are comments.
+func ParseGrammar(bnf string) (*Grammar, error) {
+	g := &Grammar{Rules: make(map[string][]Production)}
+	var name string
+	var body []string
+	finish := func() error {
+		if name == "" {
+			return nil
+		}
+		prods, err := parseProductions(strings.Join(body, "\n"))
+		if err != nil {
+			return fmt.Errorf("rule <%s>: %s", name, err)
+		}
+		g.Rules[name] = append(g.Rules[name], prods...)
+		return nil
+	}
+	for i, line := range strings.Split(bnf, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		match := ruleStart.FindStringSubmatchIndex(line)
+		if match == nil {
+			if name == "" && strings.TrimSpace(line) != "" {
+				return nil, fmt.Errorf("line %d: expected a rule definition", i+1)
+			}
+			body = append(body, line)
+			continue
+		}
+		if err := finish(); err != nil {
+			return nil, err
+		}
+		name, body = line[match[2]:match[3]], []string{line[match[1]:]}
+		if g.Start == "" {
+			g.Start = name
+		}
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	if g.Start == "" {
+		return nil, errors.New("grammar has no rules")
+	}
+	if err := g.analyze(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// ParseGrammarFile parses the grammar in the given file.
+func ParseGrammarFile(path string) (*Grammar, error) {
+	bnf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGrammar(string(bnf))
+}
+
+// parseProductions splits the body of a rule into its productions.
+func parseProductions(body string) ([]Production, error) {
+	var out []Production
+	var current Production
+	literal := func(text string, quoted bool) {
+		current = append(current, Symbol{Text: text, quoted: quoted})
+	}
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			literal(text.String(), false)
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; c {
+		case '<':
+			end := strings.IndexByte(body[i:], '>')
+			if end < 0 {
+				return nil, errors.New("unterminated non-terminal")
+			}
+			flush()
+			current = append(current, Symbol{Text: body[i+1 : i+end], NonTerminal: true})
+			i += end
+		case '"', '\'':
+			end := strings.IndexByte(body[i+1:], c)
+			if end < 0 {
+				return nil, errors.New("unterminated quote")
+			}
+			flush()
+			literal(body[i+1:i+1+end], true)
+			i += end + 1
+		case '|':
+			flush()
+			out = append(out, tidy(current))
+			current = nil
+		default:
+			text.WriteByte(c)
+		}
+	}
+	flush()
+	return append(out, tidy(current)), nil
+}
+
+// tidy collapses and trims the unquoted whitespace of a production,
+// and merges adjacent literals.
+func tidy(p Production) Production {
+	for i := range p {
+		if p[i].NonTerminal || p[i].quoted {
+			continue
+		}
+		p[i].Text = whitespace.ReplaceAllString(p[i].Text, " ")
+		if i == 0 {
+			p[i].Text = strings.TrimLeft(p[i].Text, " ")
+		}
+		if i == len(p)-1 {
+			p[i].Text = strings.TrimRight(p[i].Text, " ")
+		}
+	}
+	var out Production
+	for _, s := range p {
+		if !s.NonTerminal && s.Text == "" {
+			continue
+		}
+		s.quoted = false
+		if n := len(out); n > 0 && !s.NonTerminal && !out[n-1].NonTerminal {
+			out[n-1].Text += s.Text
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// analyze checks that every non-terminal is defined and can finish,
+// and works out the depths and recursiveness initialization needs.
+func (g *Grammar) analyze() error {
+	for name, prods := range g.Rules {
+		for _, p := range prods {
+			for _, s := range p {
+				if _, ok := g.Rules[s.Text]; s.NonTerminal && !ok {
+					return fmt.Errorf("rule <%s> refers to undefined <%s>", name, s.Text)
+				}
+			}
+		}
+	}
+
+	g.minDepth = make(map[string]int)
+	for name := range g.Rules {
+		g.minDepth[name] = math.MaxInt32
+	}
+	for changed := true; changed; {
+		changed = false
+		for name, prods := range g.Rules {
+			for _, p := range prods {
+				if d := g.productionDepth(p); d < g.minDepth[name] {
+					g.minDepth[name], changed = d, true
+				}
+			}
+		}
+	}
+	for name, d := range g.minDepth {
+		if d == math.MaxInt32 {
+			return fmt.Errorf("rule <%s> can never finish", name)
+		}
+	}
+
+	g.recursive = make(map[string]bool)
+	for name := range g.Rules {
+		g.recursive[name] = g.reaches(name, name, map[string]bool{})
+	}
+	return nil
+}
+
+// productionDepth returns the depth of the
+// shallowest derivation tree that uses p first.
+func (g *Grammar) productionDepth(p Production) int {
+	depth := 1
+	for _, s := range p {
+		if s.NonTerminal {
+			if d := g.minDepth[s.Text]; d == math.MaxInt32 {
+				return d
+			} else if d+1 > depth {
+				depth = d + 1
+			}
+		}
+	}
+	return depth
+}
+
+// reaches reports whether from can derive something containing to.
+func (g *Grammar) reaches(from, to string, seen map[string]bool) bool {
+	for _, p := range g.Rules[from] {
+		for _, s := range p {
+			if !s.NonTerminal || seen[s.Text] {
+				continue
+			}
+			if s.Text == to {
+				return true
+			}
+			seen[s.Text] = true
+			if g.reaches(s.Text, to, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recursiveProduction reports whether p contains a recursive
+// non-terminal, so that it can lead to deeper derivations.
+func (g *Grammar) recursiveProduction(p Production) bool {
+	for _, s := range p {
+		if s.NonTerminal && g.recursive[s.Text] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package ge
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const expressions = `
+# Arithmetic expressions
+<expr> ::= <expr> <op> <expr>
+         | "("<expr>")"
+         | <var>
+<op>   ::= + | - | "*"
+<var>  ::= x | 1.0
+`
+
+func Test_ParseGrammar(t *testing.T) {
+	g, err := ParseGrammar(expressions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "expr", g.Start)
+	assert.Equal(t, 3, len(g.Rules["expr"]))
+	assert.Equal(t, Production{
+		{Text: "expr", NonTerminal: true},
+		{Text: " "},
+		{Text: "op", NonTerminal: true},
+		{Text: " "},
+		{Text: "expr", NonTerminal: true},
+	}, g.Rules["expr"][0])
+	assert.Equal(t, Production{{Text: "("}, {Text: "expr", NonTerminal: true}, {Text: ")"}}, g.Rules["expr"][1])
+	assert.Equal(t, []Production{{{Text: "+"}}, {{Text: "-"}}, {{Text: "*"}}}, g.Rules["op"])
+	assert.Equal(t, []Production{{{Text: "x"}}, {{Text: "1.0"}}}, g.Rules["var"])
+
+	assert.Equal(t, 2, g.minDepth["expr"])
+	assert.True(t, g.recursive["expr"])
+	assert.False(t, g.recursive["op"])
+}
+
+func Test_ParseGrammar_QuotedText_KeptExactly(t *testing.T) {
+	g, err := ParseGrammar(`<s> ::= "a | b" ' ' '<c>' | ""`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Production{{{Text: "a | b   <c>"}}, nil}, g.Rules["s"])
+}
+
+func Test_ParseGrammarFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "expressions.bnf")
+	if err := ioutil.WriteFile(path, []byte(expressions), 0644); err != nil {
+		t.Fatal(err)
+	}
+	g, err := ParseGrammarFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "expr", g.Start)
+
+	_, err = ParseGrammarFile(filepath.Join(dir, "missing.bnf"))
+	assert.Error(t, err)
+}
+
+func Test_ParseGrammar_Invalid_Error(t *testing.T) {
+	tests := map[string]string{
+		"empty":             "# nothing here",
+		"text before rules": "x\n<s> ::= x",
+		"undefined":         "<s> ::= <t>",
+		"never finishes":    "<s> ::= <s> x | <t>\n<t> ::= <s>",
+		"unterminated":      "<s> ::= <t",
+		"unclosed quote":    `<s> ::= "x`,
+	}
+	for name, bnf := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseGrammar(bnf)
+			assert.Error(t, err)
+		})
+	}
+}
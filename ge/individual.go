@@ -0,0 +1,86 @@
+package ge
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Individual is a genome of integer codons. It implements
+// genetic.Individual and genetic.PairCrossover.
+type Individual struct {
+	Codons []int
+
+	params *Params
+}
+
+// Phenotype maps the genome to its phenotype, returning
+// ErrInvalid if it doesn't map to a complete derivation.
+func (ind *Individual) Phenotype() (string, error) {
+	out, _, err := ind.params.Grammar.Map(ind.Codons, ind.params.Wraps, ind.params.MaxDepth)
+	return out, err
+}
+
+// Fitness implements genetic.Individual. Invalid
+// genomes score InvalidScore.
+func (ind *Individual) Fitness() (float64, error) {
+	phenotype, err := ind.Phenotype()
+	if err == ErrInvalid {
+		return ind.params.InvalidScore, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return ind.params.Fitness(phenotype)
+}
+
+// Crossover implements genetic.Individual with effective one-point crossover.
+func (ind *Individual) Crossover(partner genetic.Individual) (genetic.Individual, error) {
+	child, _, err := ind.PairCrossover(partner)
+	return child, err
+}
+
+// PairCrossover implements genetic.PairCrossover with effective
+// one-point crossover: each parent is cut at a random point within the
+// codons its mapping uses, and the children swap the parents' tails.
+// Cutting in the unused tail would only produce copies of the parents.
+func (ind *Individual) PairCrossover(partner genetic.Individual) (genetic.Individual, genetic.Individual, error) {
+	mate, ok := partner.(*Individual)
+	if !ok {
+		return nil, nil, fmt.Errorf("expected Individual to be *ge.Individual, got %T", partner)
+	}
+	i, j := ind.cut(), mate.cut()
+	a := append(append([]int{}, ind.Codons[:i]...), mate.Codons[j:]...)
+	b := append(append([]int{}, mate.Codons[:j]...), ind.Codons[i:]...)
+	return &Individual{Codons: a, params: ind.params}, &Individual{Codons: b, params: ind.params}, nil
+}
+
+// cut returns a random crossover point within the used codons.
+func (ind *Individual) cut() int {
+	_, used, _ := ind.params.Grammar.Map(ind.Codons, ind.params.Wraps, ind.params.MaxDepth)
+	if used > len(ind.Codons) || used == 0 {
+		used = len(ind.Codons)
+	}
+	return rand.Intn(used + 1)
+}
+
+// Mutate implements genetic.Individual, replacing every codon
+// with a random one with the given probability.
+func (ind *Individual) Mutate(rate float64) (genetic.Individual, error) {
+	out := &Individual{Codons: append([]int{}, ind.Codons...), params: ind.params}
+	for i := range out.Codons {
+		if rand.Float64() < rate {
+			out.Codons[i] = rand.Intn(ind.params.CodonSize)
+		}
+	}
+	return out, nil
+}
+
+func (ind *Individual) String() string {
+	phenotype, err := ind.Phenotype()
+	if err != nil {
+		return "<invalid>"
+	}
+	return phenotype
+}
@@ -0,0 +1,122 @@
+package ge
+
+import "math/rand"
+
+// derivation is a node of a derivation tree: a non-terminal, the
+// production chosen for it, and the derivations of the non-terminals
+// in that production.
+type derivation struct {
+	name     string
+	depth    int
+	choice   int
+	children []*derivation
+}
+
+// choose picks a random production of the named rule that fits into
+// a tree of the given maximum depth, when expanded at the given depth.
+// When recursive is set, recursive productions are preferred.
+func (g *Grammar) choose(name string, depth, maxDepth int, recursive bool) int {
+	var fits, deeper []int
+	for i, p := range g.Rules[name] {
+		if depth-1+g.productionDepth(p) > maxDepth {
+			continue
+		}
+		fits = append(fits, i)
+		if g.recursiveProduction(p) {
+			deeper = append(deeper, i)
+		}
+	}
+	if recursive && len(deeper) > 0 {
+		return deeper[rand.Intn(len(deeper))]
+	}
+	if len(fits) == 0 {
+		// Nothing fits, so settle for the shallowest production
+		best := 0
+		for i, p := range g.Rules[name] {
+			if g.productionDepth(p) < g.productionDepth(g.Rules[name][best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	return fits[rand.Intn(len(fits))]
+}
+
+// expand fills in the children of d once its production is chosen.
+func (g *Grammar) expand(d *derivation) []*derivation {
+	for _, s := range g.Rules[d.name][d.choice] {
+		if s.NonTerminal {
+			d.children = append(d.children, &derivation{name: s.Text, depth: d.depth + 1})
+		}
+	}
+	return d.children
+}
+
+// sensible builds a derivation tree of at most the given depth, top-down
+// and left to right, using either Koza's full method, which keeps
+// choosing recursive productions for as long as they fit, or his grow
+// method, which chooses among all productions that fit (Ryan and
+// Azad, 2003).
+func (g *Grammar) sensible(maxDepth int, full bool) *derivation {
+	root := &derivation{name: g.Start, depth: 1}
+	var build func(d *derivation)
+	build = func(d *derivation) {
+		d.choice = g.choose(d.name, d.depth, maxDepth, full)
+		for _, child := range g.expand(d) {
+			build(child)
+		}
+	}
+	build(root)
+	return root
+}
+
+// piGrow builds a derivation tree of the given depth with position
+// independent grow (Fagan et al., 2016): the non-terminal to expand next
+// is picked at random among the open ones, rather than always being the
+// leftmost, and whenever only one open non-terminal is left that could
+// lead deeper, it chooses a recursive production until the tree reaches
+// the given depth. That spreads the deep branches across the whole tree.
+func (g *Grammar) piGrow(maxDepth int) *derivation {
+	root := &derivation{name: g.Start, depth: 1}
+	open := []*derivation{root}
+	reached := 0
+	for len(open) > 0 {
+		i := rand.Intn(len(open))
+		d := open[i]
+		open = append(open[:i], open[i+1:]...)
+		last := true
+		for _, o := range open {
+			if g.recursive[o.name] {
+				last = false
+			}
+		}
+		d.choice = g.choose(d.name, d.depth, maxDepth, last && reached < maxDepth)
+		if d.depth > reached {
+			reached = d.depth
+		}
+		open = append(open, g.expand(d)...)
+	}
+	return root
+}
+
+// genome returns codons that map to the given derivation tree. Every
+// choice is encoded as a random codon of at most codonSize that gives
+// the same production, and the genome is followed by a random tail of
+// tail codons.
+func (g *Grammar) genome(root *derivation, codonSize, tail int) []int {
+	var out []int
+	var walk func(d *derivation)
+	walk = func(d *derivation) {
+		if n := len(g.Rules[d.name]); n > 1 {
+			out = append(out, d.choice+n*rand.Intn(codonSize/n))
+		}
+		for _, child := range d.children {
+			walk(child)
+		}
+	}
+	walk(root)
+	for i := 0; i < tail; i++ {
+		out = append(out, rand.Intn(codonSize))
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package ge
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrInvalid is returned when a genome doesn't map to a complete
+// derivation: it runs out of codons, even after wrapping, or the
+// derivation gets too deep.
+var ErrInvalid = errors.New("genome does not map to a complete derivation")
+
+// Map derives a phenotype from a genome of integer codons. Starting
+// from the start symbol, the leftmost non-terminal is repeatedly
+// replaced by the production chosen by the next codon, modulo the
+// number of productions; rules with a single production consume no
+// codon. When the codons run out, mapping wraps around to the first
+// codon, at most wraps times. A derivation tree deeper than maxDepth,
+// counting the start symbol as depth one, is invalid; zero means
+// there's no limit. Map also returns the number of codons used.
+func (g *Grammar) Map(codons []int, wraps, maxDepth int) (string, int, error) {
+	m := &mapper{grammar: g, codons: codons, wraps: wraps, maxDepth: maxDepth}
+	out := bytes.NewBuffer(nil)
+	if err := m.expand(g.Start, 1, out); err != nil {
+		return "", m.used, err
+	}
+	return out.String(), m.used, nil
+}
+
+type mapper struct {
+	grammar  *Grammar
+	codons   []int
+	wraps    int
+	maxDepth int
+	used     int
+}
+
+func (m *mapper) expand(name string, depth int, out *bytes.Buffer) error {
+	if m.maxDepth > 0 && depth > m.maxDepth {
+		return ErrInvalid
+	}
+	prods := m.grammar.Rules[name]
+	choice := 0
+	if len(prods) > 1 {
+		if len(m.codons) == 0 || m.used >= len(m.codons)*(m.wraps+1) {
+			return ErrInvalid
+		}
+		codon := m.codons[m.used%len(m.codons)]
+		if codon < 0 {
+			codon = -codon
+		}
+		choice = codon % len(prods)
+		m.used++
+	}
+	for _, s := range prods[choice] {
+		if !s.NonTerminal {
+			out.WriteString(s.Text)
+			continue
+		}
+		if err := m.expand(s.Text, depth+1, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Package gp implements tree-based genetic programming on top of
+// genetic.Controller. Programs are trees of typed primitives and
+// terminals; they're initialized with ramped half-and-half, bred with
+// subtree crossover and point, subtree and hoist mutation, and kept
+// from bloating by a depth limit, parsimony pressure and the Tarpeian
+// method.
+package gp
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Params holds all of the parameters for genetic programming.
+type Params struct {
+	// Set holds the primitives and terminals programs are built from.
+	Set *PrimitiveSet
+
+	// Fitness scores a program. As elsewhere in this package, higher
+	// is better; negate the error to minimize it.
+	Fitness func(Tree) (float64, error)
+
+	// MinDepth and MaxDepth bound the depths of the initial programs.
+	// The defaults are 2 and 6.
+	MinDepth int
+	MaxDepth int
+
+	// MutationDepth is the maximum depth of the subtrees grown by
+	// subtree mutation. The default is 4.
+	MutationDepth int
+
+	// DepthLimit is the maximum depth of offspring. Offspring that are
+	// deeper are replaced by a copy of their parent. The default is 17.
+	DepthLimit int
+
+	// PointMutation, SubtreeMutation and HoistMutation are the relative
+	// weights with which each mutation is chosen. Leaving all of them
+	// zero weighs them equally.
+	PointMutation   float64
+	SubtreeMutation float64
+	HoistMutation   float64
+
+	// Parsimony is subtracted from a program's fitness for every one of
+	// its nodes.
+	Parsimony float64
+
+	// TarpeianRate is the probability with which a program that is
+	// larger than average isn't evaluated at all, but given
+	// TarpeianScore instead (Poli, 2003). The average is a moving
+	// average over the programs evaluated recently.
+	TarpeianRate float64
+
+	// TarpeianScore should be lower than any real score. The
+	// default is -math.MaxFloat32.
+	TarpeianScore float64
+}
+
+// problem holds what the programs of one search share.
+type problem struct {
+	params Params
+
+	// mu guards the moving average of program sizes,
+	// since programs are evaluated concurrently.
+	mu          sync.Mutex
+	averageSize float64
+}
+
+// averageSizeWeight is the weight of each newly
+// evaluated program in the moving average of sizes.
+const averageSizeWeight = 0.01
+
+// Population returns n random programs, built with ramped half-and-half.
+// It fills in the defaults of params, and returns an error if any of
+// them are not allowed.
+func (params *Params) Population(n int) ([]genetic.Individual, error) {
+	p, err := params.problem()
+	if err != nil {
+		return nil, err
+	}
+	trees, err := params.Set.rampedHalfAndHalf(n, params.MinDepth, params.MaxDepth)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]genetic.Individual, n)
+	for i, t := range trees {
+		p.averageSize += float64(len(t)) / float64(n)
+		out[i] = &Individual{Tree: t, problem: p}
+	}
+	return out, nil
+}
+
+// Generator returns a genetic.Generator of random programs, which are
+// built with grow to a random depth between MinDepth and MaxDepth. It
+// fills in the defaults of params, and returns an error if any of them
+// are not allowed.
+func (params *Params) Generator() (genetic.Generator, error) {
+	p, err := params.problem()
+	if err != nil {
+		return nil, err
+	}
+	return func() (genetic.Individual, error) {
+		depth := params.MinDepth + rand.Intn(params.MaxDepth-params.MinDepth+1)
+		t, err := params.Set.grow(params.Set.Root, depth)
+		if err != nil {
+			return nil, err
+		}
+		return &Individual{Tree: t, problem: p}, nil
+	}, nil
+}
+
+func (params *Params) problem() (*problem, error) {
+	if params.Set == nil {
+		return nil, errors.New("primitive set cannot be nil")
+	}
+	if params.Fitness == nil {
+		return nil, errors.New("fitness function cannot be nil")
+	}
+	if err := params.Set.validate(); err != nil {
+		return nil, err
+	}
+	if params.MinDepth == 0 && params.MaxDepth == 0 {
+		params.MinDepth, params.MaxDepth = 2, 6
+	}
+	if params.MutationDepth == 0 {
+		params.MutationDepth = 4
+	}
+	if params.DepthLimit == 0 {
+		params.DepthLimit = 17
+	}
+	if params.PointMutation == 0 && params.SubtreeMutation == 0 && params.HoistMutation == 0 {
+		params.PointMutation, params.SubtreeMutation, params.HoistMutation = 1, 1, 1
+	}
+	if params.TarpeianScore == 0 {
+		params.TarpeianScore = -math.MaxFloat32
+	}
+	if params.MinDepth < 0 || params.MaxDepth < params.MinDepth {
+		return nil, errors.New("depths must be non-negative, with the minimum no greater than the maximum")
+	}
+	if params.MutationDepth < 0 || params.DepthLimit < params.MaxDepth {
+		return nil, errors.New("mutation depth must be non-negative, and the depth limit at least the maximum depth")
+	}
+	if params.PointMutation < 0 || params.SubtreeMutation < 0 || params.HoistMutation < 0 {
+		return nil, errors.New("mutation weights cannot be negative")
+	}
+	if params.Parsimony < 0 {
+		return nil, errors.New("parsimony cannot be negative")
+	}
+	if params.TarpeianRate < 0 || params.TarpeianRate > 1 {
+		return nil, errors.New("Tarpeian rate must be between 0 and 1, inclusive")
+	}
+	return &problem{params: *params}, nil
+}
@@ -0,0 +1,224 @@
+package gp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	num     Type = "num"
+	boolean Type = "bool"
+)
+
+// typedSet returns a primitive set with numbers and booleans,
+// where numbers are chosen with if.
+func typedSet() *PrimitiveSet {
+	ps := NewPrimitiveSet(num)
+	ps.AddPrimitive(Arithmetic(num)...)
+	ps.AddPrimitive(
+		Primitive{
+			Name:    "if",
+			Args:    []Type{boolean, num, num},
+			Returns: num,
+			Fn: func(args []interface{}) interface{} {
+				if args[0].(bool) {
+					return args[1]
+				}
+				return args[2]
+			},
+		},
+		Primitive{
+			Name:    "<",
+			Args:    []Type{num, num},
+			Returns: boolean,
+			Fn: func(args []interface{}) interface{} {
+				return args[0].(float64) < args[1].(float64)
+			},
+		},
+	)
+	ps.AddTerminal(
+		Variable("x", num),
+		Constant("1", num, float64(1)),
+		Constant("true", boolean, true),
+	)
+	return ps
+}
+
+func zero(Tree) (float64, error) {
+	return 0, nil
+}
+
+// checkTypes asserts that every child in t has the type its parent expects.
+func checkTypes(t *testing.T, tree Tree, root Type) {
+	var check func(i int, want Type) int
+	check = func(i int, want Type) int {
+		assert.Equal(t, want, tree[i].Type())
+		next := i + 1
+		if tree[i].Primitive != nil {
+			for _, arg := range tree[i].Primitive.Args {
+				next = check(next, arg)
+			}
+		}
+		return next
+	}
+	assert.Equal(t, len(tree), check(0, root))
+}
+
+func Test_Tree_Eval(t *testing.T) {
+	ps := typedSet()
+	var add, ifPrim *Primitive
+	for _, p := range ps.primitives[num] {
+		switch p.Name {
+		case "+":
+			add = p
+		case "if":
+			ifPrim = p
+		}
+	}
+	less := ps.primitives[boolean][0]
+	x, one := ps.terminals[num][0], ps.terminals[num][1]
+
+	// (if (< x 1) (+ x 1) x)
+	tree := Tree{
+		{Primitive: ifPrim},
+		{Primitive: less}, {Terminal: x}, {Terminal: one},
+		{Primitive: add}, {Terminal: x}, {Terminal: one},
+		{Terminal: x},
+	}
+	assert.Equal(t, float64(1), tree.Eval(Env{"x": float64(0)}))
+	assert.Equal(t, float64(5), tree.Eval(Env{"x": float64(5)}))
+	assert.Equal(t, "(if (< x 1) (+ x 1) x)", tree.String())
+	assert.Equal(t, 2, tree.Depth())
+	assert.Equal(t, 4, tree.subtree(1))
+}
+
+func Test_Arithmetic_DivisionByZero_ReturnsOne(t *testing.T) {
+	for _, p := range Arithmetic(num) {
+		if p.Name == "/" {
+			assert.Equal(t, float64(1), p.Fn([]interface{}{float64(3), float64(0)}))
+			assert.Equal(t, float64(1.5), p.Fn([]interface{}{float64(3), float64(2)}))
+		}
+	}
+}
+
+func Test_RampedHalfAndHalf_DepthsAndTypes(t *testing.T) {
+	ps := typedSet()
+	trees, err := ps.rampedHalfAndHalf(100, 2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, tree := range trees {
+		checkTypes(t, tree, num)
+		depth := 2 + (i/2)%4
+		if i%2 == 0 {
+			assert.Equal(t, depth, tree.Depth())
+		} else {
+			assert.True(t, tree.Depth() <= depth)
+		}
+	}
+}
+
+func Test_PrimitiveSet_MissingTerminal_Error(t *testing.T) {
+	ps := typedSet()
+	delete(ps.terminals, boolean)
+	_, err := (&Params{Set: ps, Fitness: zero}).Population(10)
+	assert.Error(t, err)
+}
+
+func Test_Operators_PreserveTypes(t *testing.T) {
+	ps := typedSet()
+	trees, err := ps.rampedHalfAndHalf(50, 2, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i+1 < len(trees); i++ {
+		a, b := subtreeCrossover(trees[i], trees[i+1])
+		checkTypes(t, a, num)
+		checkTypes(t, b, num)
+		assert.Equal(t, len(trees[i])+len(trees[i+1]), len(a)+len(b))
+
+		checkTypes(t, ps.pointMutation(trees[i], 0.5), num)
+		mutant, err := ps.subtreeMutation(trees[i], 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkTypes(t, mutant, num)
+		hoisted := ps.hoistMutation(trees[i])
+		checkTypes(t, hoisted, num)
+		assert.True(t, len(hoisted) <= len(trees[i]))
+	}
+}
+
+func Test_Mutate_DepthLimit_ReturnsParent(t *testing.T) {
+	params := Params{Set: typedSet(), Fitness: zero, MaxDepth: 3, DepthLimit: 3, SubtreeMutation: 1, MutationDepth: 6}
+	pop, err := params.Population(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ind := range pop {
+		for i := 0; i < 10; i++ {
+			mutant, err := ind.Mutate(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.True(t, mutant.(*Individual).Tree.Depth() <= 3)
+		}
+	}
+}
+
+func Test_Fitness_Parsimony(t *testing.T) {
+	params := Params{
+		Set:       typedSet(),
+		Fitness:   func(Tree) (float64, error) { return 10, nil },
+		Parsimony: 0.5,
+	}
+	pop, err := params.Population(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	score, err := pop[0].Fitness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 10-0.5*float64(len(pop[0].(*Individual).Tree)), score)
+}
+
+func Test_Fitness_Tarpeian_KillsLargePrograms(t *testing.T) {
+	params := Params{Set: typedSet(), Fitness: zero, TarpeianRate: 1, TarpeianScore: -100}
+	pop, err := params.Population(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ind := pop[0].(*Individual)
+	ind.problem.averageSize = float64(len(ind.Tree)) - 1
+	score, _ := ind.Fitness()
+	assert.Equal(t, float64(-100), score)
+
+	ind.problem.averageSize = float64(len(ind.Tree)) + 1
+	score, _ = ind.Fitness()
+	assert.Equal(t, float64(-100), score, "a program's score is drawn once")
+
+	same := &Individual{Tree: ind.Tree, problem: ind.problem}
+	same.problem.averageSize = float64(len(ind.Tree))
+	score, _ = same.Fitness()
+	assert.Equal(t, float64(0), score)
+}
+
+func Test_Params_Invalid_Error(t *testing.T) {
+	tests := map[string]Params{
+		"no set":             {Fitness: zero},
+		"no fitness":         {Set: typedSet()},
+		"inverted depths":    {Set: typedSet(), Fitness: zero, MinDepth: 5, MaxDepth: 2},
+		"limit below depth":  {Set: typedSet(), Fitness: zero, MaxDepth: 8, DepthLimit: 4},
+		"negative weight":    {Set: typedSet(), Fitness: zero, HoistMutation: -1},
+		"negative parsimony": {Set: typedSet(), Fitness: zero, Parsimony: -1},
+		"tarpeian rate":      {Set: typedSet(), Fitness: zero, TarpeianRate: 2},
+	}
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := params.Population(10)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package gp
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Individual is a program. It implements genetic.Individual
+// and genetic.PairCrossover.
+type Individual struct {
+	Tree Tree
+
+	problem *problem
+
+	// once guards score and err, the result of the first evaluation,
+	// so that the Tarpeian draw and the moving average of sizes are
+	// applied once per program, however often Fitness is called.
+	once  sync.Once
+	score float64
+	err   error
+}
+
+// Fitness implements genetic.Individual, applying parsimony
+// pressure and the Tarpeian method to the program's score. The
+// program is evaluated on the first call only, and later calls
+// return the same score.
+func (ind *Individual) Fitness() (float64, error) {
+	ind.once.Do(func() {
+		ind.score, ind.err = ind.evaluate()
+	})
+	return ind.score, ind.err
+}
+
+func (ind *Individual) evaluate() (float64, error) {
+	p := ind.problem
+	size := float64(len(ind.Tree))
+	p.mu.Lock()
+	larger := size > p.averageSize
+	p.averageSize += averageSizeWeight * (size - p.averageSize)
+	p.mu.Unlock()
+	if larger && p.params.TarpeianRate > 0 && rand.Float64() < p.params.TarpeianRate {
+		return p.params.TarpeianScore, nil
+	}
+
+	score, err := p.params.Fitness(ind.Tree)
+	if err != nil {
+		return 0, err
+	}
+	return score - p.params.Parsimony*size, nil
+}
+
+// Crossover implements genetic.Individual with subtree crossover.
+func (ind *Individual) Crossover(partner genetic.Individual) (genetic.Individual, error) {
+	child, _, err := ind.PairCrossover(partner)
+	return child, err
+}
+
+// PairCrossover implements genetic.PairCrossover with subtree
+// crossover. A child deeper than DepthLimit is replaced by a
+// copy of its parent.
+func (ind *Individual) PairCrossover(partner genetic.Individual) (genetic.Individual, genetic.Individual, error) {
+	mate, ok := partner.(*Individual)
+	if !ok {
+		return nil, nil, fmt.Errorf("expected Individual to be *gp.Individual, got %T", partner)
+	}
+	a, b := subtreeCrossover(ind.Tree, mate.Tree)
+	return ind.child(a, ind.Tree), ind.child(b, mate.Tree), nil
+}
+
+// Mutate implements genetic.Individual. One of point, subtree and hoist
+// mutation is chosen according to their weights. Point mutation changes
+// every node with the given probability, while the others happen with
+// it. A mutant deeper than DepthLimit is replaced by a copy of its
+// parent.
+func (ind *Individual) Mutate(rate float64) (genetic.Individual, error) {
+	p := ind.problem.params
+	total := p.PointMutation + p.SubtreeMutation + p.HoistMutation
+	spin := rand.Float64() * total
+	switch {
+	case spin < p.PointMutation:
+		return ind.child(p.Set.pointMutation(ind.Tree, rate), ind.Tree), nil
+	case rand.Float64() >= rate:
+		return ind.child(ind.Tree, ind.Tree), nil
+	case spin < p.PointMutation+p.SubtreeMutation:
+		t, err := p.Set.subtreeMutation(ind.Tree, p.MutationDepth)
+		if err != nil {
+			return nil, err
+		}
+		return ind.child(t, ind.Tree), nil
+	}
+	return ind.child(p.Set.hoistMutation(ind.Tree), ind.Tree), nil
+}
+
+// child returns an Individual with the given tree, or with a copy of
+// the parent's if the tree is deeper than the depth limit.
+func (ind *Individual) child(t, parent Tree) *Individual {
+	if t.Depth() > ind.problem.params.DepthLimit {
+		t = append(Tree{}, parent...)
+	}
+	return &Individual{Tree: t, problem: ind.problem}
+}
+
+func (ind *Individual) String() string {
+	return ind.Tree.String()
+}
@@ -0,0 +1,89 @@
+package gp
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// full returns a random tree of the given type in which every branch
+// reaches the given depth, unless the primitive set runs out of
+// primitives of the type needed.
+func (ps *PrimitiveSet) full(t Type, depth int) (Tree, error) {
+	return ps.generate(t, depth, func(t Type, d int) bool {
+		return d < depth && len(ps.primitives[t]) > 0
+	})
+}
+
+// grow returns a random tree of the given type with a depth
+// of at most the given one, and branches of varying depths.
+func (ps *PrimitiveSet) grow(t Type, depth int) (Tree, error) {
+	return ps.generate(t, depth, func(t Type, d int) bool {
+		prims, terms := len(ps.primitives[t]), len(ps.terminals[t])
+		return d < depth && rand.Intn(prims+terms) < prims
+	})
+}
+
+// generate builds a tree top-down, asking inner whether the
+// node of the given type at the given depth is a primitive.
+func (ps *PrimitiveSet) generate(t Type, depth int, inner func(t Type, depth int) bool) (Tree, error) {
+	var out Tree
+	var build func(t Type, d int) error
+	build = func(t Type, d int) error {
+		if inner(t, d) {
+			prims := ps.primitives[t]
+			p := prims[rand.Intn(len(prims))]
+			out = append(out, Node{Primitive: p})
+			for _, arg := range p.Args {
+				if err := build(arg, d+1); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		n, err := ps.terminal(t)
+		if err != nil {
+			return err
+		}
+		out = append(out, n)
+		return nil
+	}
+	if err := build(t, 0); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// terminal returns a random terminal node of the given type,
+// drawing a value for it if it's an ephemeral random constant.
+func (ps *PrimitiveSet) terminal(t Type) (Node, error) {
+	terms := ps.terminals[t]
+	if len(terms) == 0 {
+		return Node{}, fmt.Errorf("there are no terminals of type %q", t)
+	}
+	term := terms[rand.Intn(len(terms))]
+	n := Node{Terminal: term}
+	if term.Ephemeral != nil {
+		n.Value = term.Ephemeral()
+	}
+	return n, nil
+}
+
+// rampedHalfAndHalf returns n trees, spreading their maximum depths
+// evenly between min and max, and building half of the trees of every
+// depth with full and half with grow (Koza, 1992).
+func (ps *PrimitiveSet) rampedHalfAndHalf(n, min, max int) ([]Tree, error) {
+	out := make([]Tree, n)
+	for i := range out {
+		depth := min + (i/2)%(max-min+1)
+		var err error
+		if i%2 == 0 {
+			out[i], err = ps.full(ps.Root, depth)
+		} else {
+			out[i], err = ps.grow(ps.Root, depth)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,108 @@
+package gp
+
+import "math/rand"
+
+// internalBias is the probability with which crossover picks an inner
+// node rather than a leaf, as suggested by Koza. Picking uniformly
+// would mostly swap leaves, since most nodes of a tree are leaves.
+const internalBias = 0.9
+
+// point picks a random node of t, preferring inner nodes, and only
+// considering nodes of the given type unless it's empty.
+func (t Tree) point(typ Type) (int, bool) {
+	var inner, leaves []int
+	for i, n := range t {
+		if typ != "" && n.Type() != typ {
+			continue
+		}
+		if n.Primitive != nil {
+			inner = append(inner, i)
+		} else {
+			leaves = append(leaves, i)
+		}
+	}
+	switch {
+	case len(inner) > 0 && (len(leaves) == 0 || rand.Float64() < internalBias):
+		return inner[rand.Intn(len(inner))], true
+	case len(leaves) > 0:
+		return leaves[rand.Intn(len(leaves))], true
+	}
+	return 0, false
+}
+
+// subtreeCrossover swaps a random subtree of a with a random subtree of
+// the same type from b, and returns both children. If b has no subtree
+// of the type chosen in a, the children are copies of the parents.
+func subtreeCrossover(a, b Tree) (Tree, Tree) {
+	i, _ := a.point("")
+	j, ok := b.point(a[i].Type())
+	if !ok {
+		return append(Tree{}, a...), append(Tree{}, b...)
+	}
+	fromA, fromB := a[i:a.subtree(i)], b[j:b.subtree(j)]
+	return a.replace(i, fromB), b.replace(j, fromA)
+}
+
+// pointMutation replaces every node, with the given probability, by
+// another of the same type: primitives by primitives taking the same
+// arguments, and terminals by terminals, with a new value if they are
+// ephemeral random constants.
+func (ps *PrimitiveSet) pointMutation(t Tree, rate float64) Tree {
+	out := append(Tree{}, t...)
+	for i, n := range out {
+		if rand.Float64() >= rate {
+			continue
+		}
+		if n.Primitive == nil {
+			out[i], _ = ps.terminal(n.Type())
+			continue
+		}
+		var same []*Primitive
+		for _, p := range ps.primitives[n.Type()] {
+			if sameArgs(p.Args, n.Primitive.Args) {
+				same = append(same, p)
+			}
+		}
+		out[i] = Node{Primitive: same[rand.Intn(len(same))]}
+	}
+	return out
+}
+
+func sameArgs(a, b []Type) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// subtreeMutation replaces a random subtree
+// with a new one grown to at most the given depth.
+func (ps *PrimitiveSet) subtreeMutation(t Tree, depth int) (Tree, error) {
+	i := rand.Intn(len(t))
+	with, err := ps.grow(t[i].Type(), depth)
+	if err != nil {
+		return nil, err
+	}
+	return t.replace(i, with), nil
+}
+
+// hoistMutation returns a random subtree of t that has the type of a
+// whole program, which makes the program smaller (Kinnear, 1994).
+func (ps *PrimitiveSet) hoistMutation(t Tree) Tree {
+	var candidates []int
+	for i := 1; i < len(t); i++ {
+		if t[i].Type() == ps.Root {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return append(Tree{}, t...)
+	}
+	i := candidates[rand.Intn(len(candidates))]
+	return append(Tree{}, t[i:t.subtree(i)]...)
+}
@@ -0,0 +1,162 @@
+package gp
+
+import (
+	"fmt"
+	"math"
+)
+
+// Type names the type of value a node produces, so that only nodes of
+// matching types are ever combined. Untyped programs use a single Type
+// for everything.
+type Type string
+
+// Env holds the variables a program is evaluated with.
+type Env map[string]interface{}
+
+// Primitive is a function: an inner node of a program tree.
+type Primitive struct {
+	Name    string
+	Args    []Type
+	Returns Type
+
+	// Fn computes the node's value from the values of its children.
+	Fn func(args []interface{}) interface{}
+}
+
+// Terminal is a leaf of a program tree: a variable, a constant, or an
+// ephemeral random constant.
+type Terminal struct {
+	Name    string
+	Returns Type
+
+	// Value returns the terminal's value in the given environment.
+	// For ephemeral random constants it is ignored.
+	Value func(env Env) interface{}
+
+	// Ephemeral, if set, makes this an ephemeral random constant: every
+	// time the terminal is placed in a tree, Ephemeral draws a value
+	// that stays with that node.
+	Ephemeral func() interface{}
+}
+
+// Variable returns a Terminal that reads the named variable from
+// the environment.
+func Variable(name string, t Type) Terminal {
+	return Terminal{
+		Name:    name,
+		Returns: t,
+		Value:   func(env Env) interface{} { return env[name] },
+	}
+}
+
+// Constant returns a Terminal that always has the given value.
+func Constant(name string, t Type, value interface{}) Terminal {
+	return Terminal{
+		Name:    name,
+		Returns: t,
+		Value:   func(Env) interface{} { return value },
+	}
+}
+
+// EphemeralConstant returns an ephemeral random constant Terminal
+// whose values are drawn by gen.
+func EphemeralConstant(name string, t Type, gen func() interface{}) Terminal {
+	return Terminal{
+		Name:      name,
+		Returns:   t,
+		Ephemeral: gen,
+	}
+}
+
+// PrimitiveSet holds the primitives and terminals programs are built
+// from, indexed by the type they return.
+type PrimitiveSet struct {
+	// Root is the type a whole program returns.
+	Root Type
+
+	primitives map[Type][]*Primitive
+	terminals  map[Type][]*Terminal
+}
+
+// NewPrimitiveSet returns an empty PrimitiveSet for programs
+// returning the root type.
+func NewPrimitiveSet(root Type) *PrimitiveSet {
+	return &PrimitiveSet{
+		Root:       root,
+		primitives: make(map[Type][]*Primitive),
+		terminals:  make(map[Type][]*Terminal),
+	}
+}
+
+// AddPrimitive adds primitives to the set.
+func (ps *PrimitiveSet) AddPrimitive(prims ...Primitive) {
+	for _, p := range prims {
+		p := p
+		ps.primitives[p.Returns] = append(ps.primitives[p.Returns], &p)
+	}
+}
+
+// AddTerminal adds terminals to the set.
+func (ps *PrimitiveSet) AddTerminal(terms ...Terminal) {
+	for _, t := range terms {
+		t := t
+		ps.terminals[t.Returns] = append(ps.terminals[t.Returns], &t)
+	}
+}
+
+// validate checks that every type that can be asked for
+// can also be produced by a terminal, so trees can be finished.
+func (ps *PrimitiveSet) validate() error {
+	needed := map[Type]bool{ps.Root: true}
+	for _, prims := range ps.primitives {
+		for _, p := range prims {
+			if p.Fn == nil {
+				return fmt.Errorf("primitive %q has no function", p.Name)
+			}
+			for _, arg := range p.Args {
+				needed[arg] = true
+			}
+		}
+	}
+	for _, terms := range ps.terminals {
+		for _, t := range terms {
+			if t.Value == nil && t.Ephemeral == nil {
+				return fmt.Errorf("terminal %q has neither a value nor an ephemeral generator", t.Name)
+			}
+		}
+	}
+	for t := range needed {
+		if len(ps.terminals[t]) == 0 {
+			return fmt.Errorf("there are no terminals of type %q", t)
+		}
+	}
+	return nil
+}
+
+// Arithmetic returns the usual arithmetic primitives for symbolic
+// regression over float64 values of the given type: addition,
+// subtraction, multiplication, and protected division, which
+// returns 1 when dividing by zero.
+func Arithmetic(t Type) []Primitive {
+	binary := func(name string, fn func(a, b float64) float64) Primitive {
+		return Primitive{
+			Name:    name,
+			Args:    []Type{t, t},
+			Returns: t,
+			Fn: func(args []interface{}) interface{} {
+				return fn(args[0].(float64), args[1].(float64))
+			},
+		}
+	}
+	return []Primitive{
+		binary("+", func(a, b float64) float64 { return a + b }),
+		binary("-", func(a, b float64) float64 { return a - b }),
+		binary("*", func(a, b float64) float64 { return a * b }),
+		binary("/", func(a, b float64) float64 {
+			if math.Abs(b) < 1e-9 {
+				return 1
+			}
+			return a / b
+		}),
+	}
+}
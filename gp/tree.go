@@ -0,0 +1,131 @@
+package gp
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Node is one node of a program tree: either a Primitive or a Terminal.
+type Node struct {
+	Primitive *Primitive
+	Terminal  *Terminal
+
+	// Value holds the value of an ephemeral random constant.
+	Value interface{}
+}
+
+// Type returns the type of value the node produces.
+func (n Node) Type() Type {
+	if n.Primitive != nil {
+		return n.Primitive.Returns
+	}
+	return n.Terminal.Returns
+}
+
+func (n Node) arity() int {
+	if n.Primitive != nil {
+		return len(n.Primitive.Args)
+	}
+	return 0
+}
+
+func (n Node) String() string {
+	switch {
+	case n.Primitive != nil:
+		return n.Primitive.Name
+	case n.Terminal.Ephemeral != nil:
+		return fmt.Sprint(n.Value)
+	}
+	return n.Terminal.Name
+}
+
+// Tree is a program, stored as its nodes in prefix order. Storing it
+// flat makes copying trees and swapping subtrees cheap.
+type Tree []Node
+
+// Eval runs the program in the given environment and returns its value.
+func (t Tree) Eval(env Env) interface{} {
+	value, _ := t.eval(0, env)
+	return value
+}
+
+// eval evaluates the subtree starting at i, and
+// returns its value along with the index after it.
+func (t Tree) eval(i int, env Env) (interface{}, int) {
+	n := t[i]
+	if n.Primitive == nil {
+		if n.Terminal.Ephemeral != nil {
+			return n.Value, i + 1
+		}
+		return n.Terminal.Value(env), i + 1
+	}
+	args := make([]interface{}, len(n.Primitive.Args))
+	next := i + 1
+	for a := range args {
+		args[a], next = t.eval(next, env)
+	}
+	return n.Primitive.Fn(args), next
+}
+
+// subtree returns the end of the subtree starting at i,
+// such that t[i:end] is that subtree.
+func (t Tree) subtree(i int) int {
+	open := 1
+	for end := i; ; end++ {
+		open += t[end].arity() - 1
+		if open == 0 {
+			return end + 1
+		}
+	}
+}
+
+// Depth returns the depth of the tree; a single terminal has depth zero.
+func (t Tree) Depth() int {
+	depth, _ := t.depth(0)
+	return depth
+}
+
+func (t Tree) depth(i int) (int, int) {
+	max, next := 0, i+1
+	for a := 0; a < t[i].arity(); a++ {
+		var d int
+		d, next = t.depth(next)
+		if d+1 > max {
+			max = d + 1
+		}
+	}
+	return max, next
+}
+
+// replace returns a copy of t with the subtree starting
+// at i replaced by the given one.
+func (t Tree) replace(i int, with Tree) Tree {
+	end := t.subtree(i)
+	out := make(Tree, 0, len(t)-(end-i)+len(with))
+	out = append(out, t[:i]...)
+	out = append(out, with...)
+	return append(out, t[end:]...)
+}
+
+// String returns the program as an S-expression.
+func (t Tree) String() string {
+	out := bytes.NewBuffer(nil)
+	t.write(out, 0)
+	return out.String()
+}
+
+func (t Tree) write(out *bytes.Buffer, i int) int {
+	n := t[i]
+	if n.Primitive == nil {
+		out.WriteString(n.String())
+		return i + 1
+	}
+	out.WriteString("(" + n.String())
+	next := i + 1
+	for a := 0; a < n.arity(); a++ {
+		out.WriteString(" ")
+		next = t.write(out, next)
+	}
+	out.WriteString(")")
+	return next
+}
@@ -0,0 +1,140 @@
+package genetic
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+)
+
+// ScoredIndividual is an individual along with its fitness score.
+type ScoredIndividual struct {
+	Individual Individual
+	Score      float64
+}
+
+// Duplicate reports whether two individuals are the same solution.
+type Duplicate func(a, b Individual) bool
+
+// SameKey returns a Duplicate that treats individuals
+// with the same key as the same solution.
+func SameKey(key func(Individual) string) Duplicate {
+	return func(a, b Individual) bool {
+		return key(a) == key(b)
+	}
+}
+
+// WithinDistance returns a Duplicate that treats individuals that are
+// no further apart than the given distance as the same solution.
+func WithinDistance(distance func(a, b Individual) float64, threshold float64) Duplicate {
+	return func(a, b Individual) bool {
+		return distance(a, b) <= threshold
+	}
+}
+
+// deepEqual is the default Duplicate.
+func deepEqual(a, b Individual) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// hallOfFame keeps the fittest distinct individuals ever seen,
+// fittest first.
+type hallOfFame struct {
+	size      int
+	duplicate Duplicate
+	members   []indWithScore
+}
+
+func newHallOfFame(size int, duplicate Duplicate) *hallOfFame {
+	if duplicate == nil {
+		duplicate = deepEqual
+	}
+	return &hallOfFame{size: size, duplicate: duplicate}
+}
+
+// update considers the members of a scored population,
+// which must be sorted from fittest to least fit.
+func (h *hallOfFame) update(pop []indWithScore) {
+	for _, ind := range pop {
+		if len(h.members) == h.size && ind.score <= h.members[len(h.members)-1].score {
+			return
+		}
+		h.consider(ind)
+	}
+}
+
+// consider adds ind if it is fitter than the member it duplicates,
+// or, failing a duplicate, than the least fit member.
+func (h *hallOfFame) consider(ind indWithScore) {
+	for i, member := range h.members {
+		if member.id == ind.id && ind.id != 0 || h.duplicate(member.Individual, ind.Individual) {
+			if ind.score <= member.score {
+				return
+			}
+			h.members = append(h.members[:i], h.members[i+1:]...)
+			break
+		}
+	}
+	i := sort.Search(len(h.members), func(i int) bool { return h.members[i].score < ind.score })
+	h.members = append(h.members, indWithScore{})
+	copy(h.members[i+1:], h.members[i:])
+	h.members[i] = ind
+	if len(h.members) > h.size {
+		h.members = h.members[:h.size]
+	}
+}
+
+// reinstate puts the fittest n members back into the survivors, in
+// place of the least fit survivors, unless they are there already.
+// The survivors must be sorted from fittest to least fit, and are
+// returned the same way.
+func (h *hallOfFame) reinstate(survivors []indWithScore, n int) []indWithScore {
+	out := append([]indWithScore{}, survivors...)
+	replaced := 0
+	for _, member := range h.members {
+		if n == 0 || replaced == len(out) {
+			break
+		}
+		n--
+		present := false
+		for _, s := range out {
+			if s.id == member.id {
+				present = true
+				break
+			}
+		}
+		if present {
+			continue
+		}
+		replaced++
+		out[len(out)-replaced] = member
+	}
+	sort.Stable(sort.Reverse(pairs(out)))
+	return out
+}
+
+// HallOfFame returns the fittest distinct individuals seen so far, up to
+// Params.HallOfFame of them, fittest first. It's empty when
+// Params.HallOfFame is zero.
+func (c *Controller) HallOfFame() []ScoredIndividual {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := []ScoredIndividual{}
+	if c.hallOfFame == nil {
+		return out
+	}
+	for _, m := range c.hallOfFame.members {
+		out = append(out, ScoredIndividual{m.Individual, m.score})
+	}
+	return out
+}
+
+// BestEver returns the fittest individual seen so far, which, unlike
+// Fittest, may no longer be in the population.
+func (c *Controller) BestEver() (ScoredIndividual, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.bestEver == nil {
+		return ScoredIndividual{}, errors.New("no individual has been scored yet")
+	}
+	return ScoredIndividual{c.bestEver.Individual, c.bestEver.score}, nil
+}
@@ -0,0 +1,123 @@
+package genetic
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func scored(scores ...float64) []indWithScore {
+	out := make([]indWithScore, len(scores))
+	for i, score := range scores {
+		out[i] = indWithScore{Individual: fakeIndividual{id: int(score), fitness: score}, score: score, id: uint64(score)}
+	}
+	return out
+}
+
+func memberScores(h *hallOfFame) []float64 {
+	var out []float64
+	for _, m := range h.members {
+		out = append(out, m.score)
+	}
+	return out
+}
+
+func Test_HallOfFame_Update_KeepsFittest(t *testing.T) {
+	h := newHallOfFame(3, nil)
+	h.update(scored(5, 3, 1))
+	h.update(scored(6, 4, 2)[:2])
+	assert.Equal(t, []float64{6, 5, 4}, memberScores(h))
+}
+
+func Test_HallOfFame_Update_DuplicatesReplaced(t *testing.T) {
+	byFitness := SameKey(func(ind Individual) string {
+		return fmt.Sprint(ind.(fakeIndividual).fitness > 2)
+	})
+	h := newHallOfFame(3, byFitness)
+	h.update(scored(4, 3, 1))
+	assert.Equal(t, []float64{4, 1}, memberScores(h))
+	h.update(scored(5, 2))
+	assert.Equal(t, []float64{5, 2}, memberScores(h))
+}
+
+func Test_WithinDistance_Threshold(t *testing.T) {
+	same := WithinDistance(func(a, b Individual) float64 {
+		return a.(fakeIndividual).fitness - b.(fakeIndividual).fitness
+	}, 1)
+	assert.True(t, same(fakeIndividual{fitness: 2}, fakeIndividual{fitness: 1}))
+	assert.False(t, same(fakeIndividual{fitness: 3}, fakeIndividual{fitness: 1}))
+}
+
+func Test_HallOfFame_Reinstate_ReplacesLeastFit(t *testing.T) {
+	h := newHallOfFame(2, nil)
+	h.update(scored(9, 8))
+	survivors := h.reinstate(scored(5, 4, 3), 2)
+	assert.Equal(t, []float64{9, 8, 5}, []float64{survivors[0].score, survivors[1].score, survivors[2].score})
+
+	survivors = h.reinstate(h.members, 2)
+	assert.Equal(t, h.members, survivors)
+}
+
+// lossy returns params for a search whose offspring are all worse
+// than the initial population.
+func lossy() Params {
+	return Params{
+		Crossover:       1,
+		TargetFitness:   10,
+		SelectionMethod: Tournament(2),
+		InitPop:         []fakeIndividual{{id: 1, fitness: 1}, {id: 2, fitness: 2}, {id: 3, fitness: 3}},
+		Termination:     Termination{MaxGenerations: 2},
+		MutationOperators: []MutationOperator{{Name: "worse", Mutate: func(ind Individual, rate float64) (Individual, error) {
+			return fakeIndividual{}, nil
+		}}},
+	}
+}
+
+func Test_Controller_HallOfFame_DistinctBestEverSeen(t *testing.T) {
+	params := lossy()
+	params.HallOfFame = 5
+	ctrl, err := NewController(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ErrTerminated, ctrl.Run())
+
+	hall := ctrl.HallOfFame()
+	assert.Equal(t, []ScoredIndividual{
+		{fakeIndividual{id: 3, fitness: 3}, 3},
+		{fakeIndividual{id: 2, fitness: 2}, 2},
+		{fakeIndividual{id: 1, fitness: 1}, 1},
+		{fakeIndividual{}, 0},
+	}, hall)
+	best, err := ctrl.BestEver()
+	assert.NoError(t, err)
+	assert.Equal(t, 3.0, best.Score)
+	score, err := ctrl.population.FittestScore()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, score)
+}
+
+func Test_Controller_HallOfFame_ElitesReinstated(t *testing.T) {
+	params := lossy()
+	params.HallOfFame = 2
+	params.HallOfFameElites = 1
+	ctrl, err := NewController(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ErrTerminated, ctrl.Run())
+	fittest, err := ctrl.Fittest()
+	assert.NoError(t, err)
+	assert.Equal(t, fakeIndividual{id: 3, fitness: 3}, fittest)
+}
+
+func Test_Controller_HallOfFame_Disabled(t *testing.T) {
+	ctrl, err := NewController(Params{SelectionMethod: Tournament(2), InitPop: make([]fakeIndividual, 2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, ctrl.HallOfFame())
+	_, err = ctrl.BestEver()
+	assert.Error(t, err)
+}
@@ -0,0 +1,202 @@
+package genetic
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+)
+
+// HistoryFormat is the format of a HistoryWriter's stream.
+type HistoryFormat int
+
+const (
+	// HistoryCSV writes a header, and then a row per generation or, when
+	// the population is recorded, a row per individual, with the
+	// statistics of its generation repeated on every row.
+	HistoryCSV HistoryFormat = iota
+
+	// HistoryJSONLines writes a JSON object per generation, holding the
+	// population in a "population" array when it is recorded.
+	HistoryJSONLines
+)
+
+// Codec renders an individual for the history.
+type Codec func(Individual) (string, error)
+
+// HistoryWriter records the statistics of every generation of a search,
+// and optionally its scored population, to a stream. Every generation is
+// flushed as soon as it has been written, so a search that crashes still
+// leaves a usable record. Set it as Params.History.
+type HistoryWriter struct {
+	// Population, if true, records every individual of every
+	// generation, fittest first, along with its score.
+	Population bool
+
+	// Codec renders the individuals. The default is fmt.Sprint,
+	// which uses String() if the individual implements fmt.Stringer.
+	Codec Codec
+
+	w      io.Writer
+	format HistoryFormat
+
+	mu     sync.Mutex
+	csv    *csv.Writer
+	header bool
+	err    error
+}
+
+// NewHistoryWriter returns a HistoryWriter that
+// writes to w in the given format.
+func NewHistoryWriter(w io.Writer, format HistoryFormat) *HistoryWriter {
+	return &HistoryWriter{w: w, format: format}
+}
+
+// Err returns the first error the writer ran into. Once it
+// has failed, the writer doesn't write any more generations.
+func (h *HistoryWriter) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.err
+}
+
+// historyIndividual is a recorded member of the population.
+type historyIndividual struct {
+	Score      historyFloat `json:"score"`
+	Individual string       `json:"individual"`
+}
+
+// historyRecord is a line of JSON Lines history.
+type historyRecord struct {
+	Generation     int                 `json:"generation"`
+	Best           historyFloat        `json:"best"`
+	Mean           historyFloat        `json:"mean"`
+	Worst          historyFloat        `json:"worst"`
+	Diversity      historyFloat        `json:"diversity"`
+	PopulationSize int                 `json:"populationSize"`
+	Evaluations    int                 `json:"evaluations"`
+	ElapsedMs      float64             `json:"elapsedMs"`
+	CrossoverRate  historyFloat        `json:"crossoverRate"`
+	MutationRate   historyFloat        `json:"mutationRate"`
+	SuccessRate    historyFloat        `json:"successRate"`
+	Population     []historyIndividual `json:"population,omitempty"`
+}
+
+// historyFloat is a number that JSON can encode even when it's
+// infinite or NaN, in which case it's written as a string.
+type historyFloat float64
+
+func (f historyFloat) MarshalJSON() ([]byte, error) {
+	if math.IsInf(float64(f), 0) || math.IsNaN(float64(f)) {
+		return json.Marshal(fmt.Sprint(float64(f)))
+	}
+	return json.Marshal(float64(f))
+}
+
+var historyColumns = []string{"generation", "best", "mean", "worst", "diversity", "population_size",
+	"evaluations", "elapsed_ms", "crossover_rate", "mutation_rate", "success_rate"}
+
+// record writes a generation, and flushes it.
+func (h *HistoryWriter) record(stats Stats, pop *Population) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.err != nil {
+		return
+	}
+	if h.err = h.write(stats, pop); h.err != nil {
+		return
+	}
+	if f, ok := h.w.(interface{ Flush() error }); ok {
+		h.err = f.Flush()
+	}
+}
+
+func (h *HistoryWriter) write(stats Stats, pop *Population) error {
+	var individuals []historyIndividual
+	if h.Population {
+		codec := h.Codec
+		if codec == nil {
+			codec = func(ind Individual) (string, error) { return fmt.Sprint(ind), nil }
+		}
+		for _, ind := range pop.pop {
+			text, err := codec(ind.Individual)
+			if err != nil {
+				return fmt.Errorf("failed to encode individual: %s", err)
+			}
+			individuals = append(individuals, historyIndividual{historyFloat(ind.score), text})
+		}
+	}
+
+	switch h.format {
+	case HistoryCSV:
+		return h.writeCSV(stats, individuals)
+	case HistoryJSONLines:
+		data, err := json.Marshal(historyRecord{
+			Generation:     stats.Generation,
+			Best:           historyFloat(stats.Best),
+			Mean:           historyFloat(stats.Mean),
+			Worst:          historyFloat(stats.Worst),
+			Diversity:      historyFloat(stats.Diversity),
+			PopulationSize: stats.PopulationSize,
+			Evaluations:    stats.Evaluations,
+			ElapsedMs:      stats.Elapsed.Seconds() * 1000,
+			CrossoverRate:  historyFloat(stats.CrossoverRate),
+			MutationRate:   historyFloat(stats.MutationRate),
+			SuccessRate:    historyFloat(stats.SuccessRate),
+			Population:     individuals,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = h.w.Write(append(data, '\n'))
+		return err
+	}
+	return errors.New("unknown history format")
+}
+
+func (h *HistoryWriter) writeCSV(stats Stats, individuals []historyIndividual) error {
+	if h.csv == nil {
+		h.csv = csv.NewWriter(h.w)
+	}
+	if !h.header {
+		header := historyColumns
+		if h.Population {
+			header = append(append([]string{}, header...), "rank", "score", "individual")
+		}
+		if err := h.csv.Write(header); err != nil {
+			return err
+		}
+		h.header = true
+	}
+	float := func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+	row := []string{
+		strconv.Itoa(stats.Generation),
+		float(stats.Best),
+		float(stats.Mean),
+		float(stats.Worst),
+		float(stats.Diversity),
+		strconv.Itoa(stats.PopulationSize),
+		strconv.Itoa(stats.Evaluations),
+		float(stats.Elapsed.Seconds() * 1000),
+		float(stats.CrossoverRate),
+		float(stats.MutationRate),
+		float(stats.SuccessRate),
+	}
+	if !h.Population {
+		if err := h.csv.Write(row); err != nil {
+			return err
+		}
+	}
+	for i, ind := range individuals {
+		r := append(append([]string{}, row...), strconv.Itoa(i+1), float(float64(ind.Score)), ind.Individual)
+		if err := h.csv.Write(r); err != nil {
+			return err
+		}
+	}
+	h.csv.Flush()
+	return h.csv.Error()
+}
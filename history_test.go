@@ -0,0 +1,137 @@
+package genetic
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runWithHistory(t *testing.T, h *HistoryWriter) {
+	ctrl, err := NewController(Params{
+		Elitism:         1,
+		TargetFitness:   10,
+		SelectionMethod: Tournament(2),
+		InitPop:         []fakeIndividual{{id: 1, fitness: 3}, {id: 2, fitness: 1}, {id: 3, fitness: 2}},
+		Termination:     Termination{MaxGenerations: 2},
+		History:         h,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ErrTerminated, ctrl.Run())
+}
+
+func Test_HistoryWriter_CSV_RowPerGeneration(t *testing.T) {
+	out := &bytes.Buffer{}
+	h := NewHistoryWriter(out, HistoryCSV)
+	runWithHistory(t, h)
+	assert.NoError(t, h.Err())
+
+	rows, err := csv.NewReader(out).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 4)
+	assert.Equal(t, historyColumns, rows[0])
+	for i, row := range rows[1:] {
+		assert.Equal(t, fmt.Sprint(i), row[0])
+		assert.Equal(t, "3", row[1])
+	}
+}
+
+func Test_HistoryWriter_CSVPopulation_RowPerIndividual(t *testing.T) {
+	out := &bytes.Buffer{}
+	h := NewHistoryWriter(out, HistoryCSV)
+	h.Population = true
+	h.Codec = func(ind Individual) (string, error) {
+		return fmt.Sprintf("#%d", ind.(fakeIndividual).id), nil
+	}
+	runWithHistory(t, h)
+	assert.NoError(t, h.Err())
+
+	rows, err := csv.NewReader(out).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1+3*3)
+	assert.Equal(t, []string{"rank", "score", "individual"}, rows[0][len(historyColumns):])
+	rank := len(historyColumns)
+	assert.Equal(t, []string{"0", "1", "3", "#1"}, []string{rows[1][0], rows[1][rank], rows[1][rank+1], rows[1][rank+2]})
+	assert.Equal(t, []string{"0", "3", "1", "#2"}, []string{rows[3][0], rows[3][rank], rows[3][rank+1], rows[3][rank+2]})
+}
+
+func Test_HistoryWriter_JSONLines_ObjectPerGeneration(t *testing.T) {
+	out := &bytes.Buffer{}
+	h := NewHistoryWriter(out, HistoryJSONLines)
+	h.Population = true
+	runWithHistory(t, h)
+	assert.NoError(t, h.Err())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 3)
+	for i, line := range lines {
+		var record struct {
+			Generation int
+			Best       float64
+			Population []struct {
+				Score      float64
+				Individual string
+			}
+		}
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		assert.Equal(t, i, record.Generation)
+		assert.Equal(t, 3.0, record.Best)
+		assert.Len(t, record.Population, 3)
+		assert.True(t, record.Population[0].Score >= record.Population[1].Score)
+		assert.True(t, record.Population[1].Score >= record.Population[2].Score)
+		if i == 0 {
+			assert.Equal(t, fmt.Sprint(fakeIndividual{id: 1, fitness: 3}), record.Population[0].Individual)
+		}
+	}
+}
+
+func Test_HistoryWriter_JSONLines_NoPopulationOmitted(t *testing.T) {
+	out := &bytes.Buffer{}
+	runWithHistory(t, NewHistoryWriter(out, HistoryJSONLines))
+	assert.NotContains(t, out.String(), "population\":")
+}
+
+func Test_historyFloat_Infinite_String(t *testing.T) {
+	data, err := json.Marshal([]historyFloat{1.5, historyFloat(math.Inf(-1)), historyFloat(math.NaN())})
+	assert.NoError(t, err)
+	assert.Equal(t, `[1.5,"-Inf","NaN"]`, string(data))
+}
+
+func Test_HistoryWriter_Buffered_FlushedEveryGeneration(t *testing.T) {
+	out := &bytes.Buffer{}
+	h := NewHistoryWriter(bufio.NewWriter(out), HistoryCSV)
+	runWithHistory(t, h)
+	assert.Equal(t, 4, strings.Count(out.String(), "\n"))
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func Test_HistoryWriter_WriteFails_ErrKeptAndSearchContinues(t *testing.T) {
+	for _, format := range []HistoryFormat{HistoryCSV, HistoryJSONLines} {
+		h := NewHistoryWriter(failingWriter{}, format)
+		runWithHistory(t, h)
+		assert.EqualError(t, h.Err(), "disk full")
+	}
+}
+
+func Test_HistoryWriter_CodecFails_Err(t *testing.T) {
+	h := NewHistoryWriter(&bytes.Buffer{}, HistoryJSONLines)
+	h.Population = true
+	h.Codec = func(Individual) (string, error) { return "", errors.New("unencodable") }
+	runWithHistory(t, h)
+	assert.EqualError(t, h.Err(), "failed to encode individual: unencodable")
+}
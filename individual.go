@@ -6,3 +6,21 @@ type Individual interface {
 	Mutate(float64) (Individual, error)
 	Fitness() (float64, error)
 }
+
+// PairCrossover is an optional interface for Individuals whose
+// crossover naturally produces two complementary children, such as
+// ordered crossover on permutations. When the controller needs two
+// offspring from a pair of parents, it prefers this over calling
+// Crossover() twice.
+type PairCrossover interface {
+	PairCrossover(Individual) (Individual, Individual, error)
+}
+
+// MultiCrossover is an optional interface for Individuals that can
+// recombine with any number of partners and produce any number of
+// children, such as diagonal crossover or differential recombination.
+// The receiver is the first parent and the slice holds the others.
+// It is required when Params.ParentsPerMating is greater than two.
+type MultiCrossover interface {
+	MultiCrossover([]Individual) ([]Individual, error)
+}
@@ -0,0 +1,176 @@
+package genetic
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// Initializer builds an initial population of size individuals. It
+// should draw any randomness it needs from rng, the controller's
+// random source, so that seeded runs are reproducible. It may build
+// more than size, and the controller keeps the fittest size of them,
+// as it does with an InitPop larger than PopulationSize.
+type Initializer func(size int, rng *rand.Rand) ([]Individual, error)
+
+// Generated returns an Initializer that calls the generator once for
+// every individual. A Generator can't see the controller's source, so
+// the population is only as reproducible as the generator's own
+// randomness; use GeneratedWith for populations that follow Params.Seed.
+func Generated(g Generator) Initializer {
+	return func(size int, rng *rand.Rand) ([]Individual, error) {
+		out := make([]Individual, size)
+		for i := range out {
+			var err error
+			if out[i], err = g(); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+}
+
+// GeneratedWith returns an Initializer that calls g once for every
+// individual, with the controller's source to draw from.
+func GeneratedWith(g func(rng *rand.Rand) (Individual, error)) Initializer {
+	return func(size int, rng *rand.Rand) ([]Individual, error) {
+		out := make([]Individual, size)
+		for i := range out {
+			var err error
+			if out[i], err = g(rng); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+}
+
+// Seeded returns an Initializer that starts the population with known
+// solutions, such as the results of an earlier search or a heuristic,
+// and builds the rest with fill. There can't be more seeds than places
+// in the population.
+func Seeded(seeds []Individual, fill Initializer) Initializer {
+	return func(size int, rng *rand.Rand) ([]Individual, error) {
+		if len(seeds) > size {
+			return nil, fmt.Errorf("%d seeds don't fit in a population of %d", len(seeds), size)
+		}
+		out := append([]Individual{}, seeds...)
+		if len(out) == size {
+			return out, nil
+		}
+		rest, err := fill(size-len(out), rng)
+		if err != nil {
+			return nil, err
+		}
+		return append(out, rest...), nil
+	}
+}
+
+// Distinct returns an Initializer that rejects duplicates among the
+// individuals init builds, as judged by same, or reflect.DeepEqual if
+// it's nil. It replaces them from up to attempts more calls to init,
+// and fails if the population still isn't full.
+func Distinct(init Initializer, same Duplicate, attempts int) Initializer {
+	if same == nil {
+		same = deepEqual
+	}
+	return func(size int, rng *rand.Rand) ([]Individual, error) {
+		var out []Individual
+		for attempt := 0; attempt <= attempts; attempt++ {
+			batch, err := init(size, rng)
+			if err != nil {
+				return nil, err
+			}
+		candidates:
+			for _, ind := range batch {
+				for _, kept := range out {
+					if same(ind, kept) {
+						continue candidates
+					}
+				}
+				out = append(out, ind)
+				if len(out) == size {
+					return out, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("only found %d distinct individuals for a population of %d", len(out), size)
+	}
+}
+
+// Opposition returns an Initializer for opposition-based initialization.
+// Every individual init builds, which must be a RealVector, is paired
+// with its opposite, the point reflected through the centre of the box
+// between lower and upper. It builds both of every pair, twice as many
+// individuals as the population holds, and leaves the controller to
+// score them with the rest of the search and keep the fittest half.
+// Wrap init rather than Opposition in Distinct, which would only
+// keep the first size of them.
+func Opposition(init Initializer, lower, upper []float64) Initializer {
+	return func(size int, rng *rand.Rand) ([]Individual, error) {
+		if err := checkBounds(lower, upper); err != nil {
+			return nil, err
+		}
+		inds, err := init(size, rng)
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]Individual, 0, 2*len(inds))
+		for _, ind := range inds {
+			vec, ok := ind.(RealVector)
+			if !ok {
+				return nil, fmt.Errorf("%T must implement RealVector for opposition-based initialization", ind)
+			}
+			v := vec.Vector()
+			if len(v) != len(lower) {
+				return nil, fmt.Errorf("genome has %d dimensions, bounds have %d", len(v), len(lower))
+			}
+			opposite := make([]float64, len(v))
+			for i, x := range v {
+				opposite[i] = lower[i] + upper[i] - x
+			}
+			candidates = append(candidates, ind, vec.WithVector(opposite))
+		}
+		return candidates, nil
+	}
+}
+
+// LatinHypercube returns an Initializer that samples genomes for the
+// template from the box between lower and upper. Every dimension is cut
+// into as many equal strata as the population has places, and each
+// stratum holds exactly one individual's value for that dimension.
+func LatinHypercube(template RealVector, lower, upper []float64) Initializer {
+	return func(size int, rng *rand.Rand) ([]Individual, error) {
+		if err := checkBounds(lower, upper); err != nil {
+			return nil, err
+		}
+		genomes := make([][]float64, size)
+		for i := range genomes {
+			genomes[i] = make([]float64, len(lower))
+		}
+		for d := range lower {
+			width := (upper[d] - lower[d]) / float64(size)
+			for i, stratum := range rng.Perm(size) {
+				genomes[i][d] = lower[d] + (float64(stratum)+rng.Float64())*width
+			}
+		}
+		out := make([]Individual, size)
+		for i, genome := range genomes {
+			out[i] = template.WithVector(genome)
+		}
+		return out, nil
+	}
+}
+
+// checkBounds checks that lower and upper describe a box.
+func checkBounds(lower, upper []float64) error {
+	if len(lower) == 0 || len(lower) != len(upper) {
+		return errors.New("lower and upper bounds must have the same, nonzero number of dimensions")
+	}
+	for i := range lower {
+		if lower[i] > upper[i] {
+			return fmt.Errorf("lower bound %g is above upper bound %g in dimension %d", lower[i], upper[i], i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package genetic
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// counter returns a Generator of fakeIndividuals with
+// ids counting up from 0, modulo n.
+func counter(n int) Generator {
+	next := 0
+	return func() (Individual, error) {
+		ind := fakeIndividual{id: next % n}
+		next++
+		return ind, nil
+	}
+}
+
+func Test_Generated_GeneratorCalledForEach(t *testing.T) {
+	inds, err := Generated(counter(10))(3, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []Individual{fakeIndividual{id: 0}, fakeIndividual{id: 1}, fakeIndividual{id: 2}}, inds)
+}
+
+func Test_GeneratedWith_NewController_FollowsSeed(t *testing.T) {
+	random := GeneratedWith(func(rng *rand.Rand) (Individual, error) {
+		return fakeIndividual{id: rng.Intn(1000), fitness: rng.Float64()}, nil
+	})
+	var pops [][]ScoredIndividual
+	for i := 0; i < 2; i++ {
+		ctrl, err := NewController(Params{
+			Initializer:     random,
+			PopulationSize:  5,
+			SelectionMethod: Tournament(2),
+			Seed:            9,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pops = append(pops, ctrl.population.Sorted())
+	}
+	assert.Equal(t, pops[0], pops[1])
+
+	_, err := GeneratedWith(func(*rand.Rand) (Individual, error) {
+		return nil, errors.New("broken")
+	})(2, rand.New(rand.NewSource(1)))
+	assert.Error(t, err)
+}
+
+func Test_Seeded_SeedsFirstRestFilled(t *testing.T) {
+	seeds := []Individual{fakeIndividual{id: 7}, fakeIndividual{id: 8}}
+	inds, err := Seeded(seeds, Generated(counter(10)))(3, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []Individual{fakeIndividual{id: 7}, fakeIndividual{id: 8}, fakeIndividual{id: 0}}, inds)
+
+	_, err = Seeded(seeds, Generated(counter(10)))(1, nil)
+	assert.Error(t, err)
+}
+
+func Test_Distinct_DuplicatesReplaced(t *testing.T) {
+	// The first batch is 1, 0, 1 and the second 1, 2, 0
+	seeds := []Individual{fakeIndividual{id: 1}}
+	inds, err := Distinct(Seeded(seeds, Generated(counter(3))), nil, 1)(3, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []Individual{fakeIndividual{id: 1}, fakeIndividual{id: 0}, fakeIndividual{id: 2}}, inds)
+
+	_, err = Distinct(Seeded(seeds, Generated(counter(3))), nil, 0)(3, nil)
+	assert.Error(t, err)
+}
+
+func Test_Opposition_OppositesAdded(t *testing.T) {
+	far := func(size int, rng *rand.Rand) ([]Individual, error) {
+		return []Individual{fakeVector{9, 8}, fakeVector{2, 1}}, nil
+	}
+	inds, err := Opposition(far, []float64{0, 0}, []float64{10, 10})(2, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []Individual{fakeVector{9, 8}, fakeVector{1, 2}, fakeVector{2, 1}, fakeVector{8, 9}}, inds)
+
+	_, err = Opposition(Generated(counter(2)), []float64{0}, []float64{1})(2, nil)
+	assert.Error(t, err)
+	_, err = Opposition(far, []float64{0}, []float64{10})(2, nil)
+	assert.Error(t, err)
+}
+
+func Test_NewController_Opposition_FitterHalfKept(t *testing.T) {
+	far := func(size int, rng *rand.Rand) ([]Individual, error) {
+		return []Individual{fakeVector{9, 8}, fakeVector{2, 1}}, nil
+	}
+	ctrl, err := NewController(Params{
+		TargetFitness:   -5,
+		SelectionMethod: Tournament(2),
+		Initializer:     Opposition(far, []float64{0, 0}, []float64{10, 10}),
+		PopulationSize:  2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, ctrl.Run())
+
+	stats := ctrl.Stats()
+	assert.Equal(t, 2, stats[0].PopulationSize)
+	assert.Equal(t, 4, stats[0].Evaluations)
+	var kept []Individual
+	for _, ind := range ctrl.population.Sorted() {
+		kept = append(kept, ind.Individual)
+	}
+	assert.ElementsMatch(t, []Individual{fakeVector{1, 2}, fakeVector{2, 1}}, kept)
+}
+
+func Test_LatinHypercube_EveryStratumFilledOnce(t *testing.T) {
+	lower, upper := []float64{0, -10}, []float64{5, 10}
+	inds, err := LatinHypercube(fakeVector{}, lower, upper)(5, rand.New(rand.NewSource(1)))
+	assert.NoError(t, err)
+	assert.Len(t, inds, 5)
+	for d := range lower {
+		strata := map[int]bool{}
+		for _, ind := range inds {
+			x := ind.(fakeVector)[d]
+			assert.True(t, x >= lower[d] && x <= upper[d])
+			strata[int((x-lower[d])/(upper[d]-lower[d])*5)] = true
+		}
+		assert.Len(t, strata, 5)
+	}
+
+	_, err = LatinHypercube(fakeVector{}, []float64{1}, []float64{0})(5, rand.New(rand.NewSource(1)))
+	assert.Error(t, err)
+}
+
+func Test_New_WithInitializer_PopulationBuilt(t *testing.T) {
+	var pops [][]ScoredIndividual
+	for i := 0; i < 2; i++ {
+		ctrl, err := New(nil,
+			WithInitializer(LatinHypercube(fakeVector{}, []float64{-1, -1}, []float64{1, 1})),
+			WithPopulationSize(6),
+			WithTournament(2),
+			WithSeed(3),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 6, ctrl.population.Len())
+		pops = append(pops, ctrl.population.Sorted())
+	}
+	assert.Equal(t, pops[0], pops[1])
+}
+
+func Test_NewController_Initializer_Invalid(t *testing.T) {
+	init := Generated(counter(10))
+	_, err := NewController(Params{Initializer: init, SelectionMethod: Roulette()})
+	assert.True(t, errors.Is(err, ErrRequired), err)
+
+	_, err = NewController(Params{Initializer: init, PopulationSize: 2, InitPop: make([]fakeIndividual, 2), SelectionMethod: Roulette()})
+	assert.True(t, errors.Is(err, ErrConflict), err)
+
+	short := func(size int, rng *rand.Rand) ([]Individual, error) {
+		return []Individual{fakeIndividual{}}, nil
+	}
+	_, err = NewController(Params{Initializer: short, PopulationSize: 2, SelectionMethod: Roulette()})
+	assert.True(t, errors.Is(err, ErrConflict), err)
+
+	broken := errors.New("broken")
+	failing := func(size int, rng *rand.Rand) ([]Individual, error) {
+		return nil, broken
+	}
+	_, err = NewController(Params{Initializer: failing, PopulationSize: 2, SelectionMethod: Roulette()})
+	assert.True(t, errors.Is(err, broken), err)
+}
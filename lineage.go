@@ -0,0 +1,211 @@
+package genetic
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Record is an individual's entry in the lineage.
+type Record struct {
+	ID uint64
+
+	// Parents are the IDs of the individuals it was bred from. Members
+	// of the initial population, and individuals from Params.Generator,
+	// have none.
+	Parents []uint64
+
+	// Operator is how the individual was bred: "initial", "generator" or
+	// "breeding", or else "crossover" (or the name of the registered
+	// crossover operator) or "copy", followed by "+mutation" (or "+" and
+	// the name of the registered mutation operator).
+	Operator string
+
+	// Born is the generation the individual was bred in.
+	Born int
+
+	Score      float64
+	Individual Individual
+}
+
+// Lineage records every individual that joined the population of a
+// search, when Params.Lineage is set. It's safe to query while the
+// search runs.
+type Lineage struct {
+	mu      sync.RWMutex
+	records map[uint64]Record
+}
+
+func newLineage() *Lineage {
+	return &Lineage{records: make(map[uint64]Record)}
+}
+
+// add records the members of a population that aren't recorded yet,
+// taking their parents from the population's pedigree, which must
+// still remember them.
+func (l *Lineage) add(pop []indWithScore, family *pedigree) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, ind := range pop {
+		if _, ok := l.records[ind.id]; ok || ind.id == 0 {
+			continue
+		}
+		l.records[ind.id] = Record{
+			ID:         ind.id,
+			Parents:    append([]uint64(nil), family.parents[ind.id]...),
+			Operator:   ind.operator,
+			Born:       ind.born,
+			Score:      ind.score,
+			Individual: ind.Individual,
+		}
+	}
+}
+
+// Len returns how many individuals have been recorded.
+func (l *Lineage) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.records)
+}
+
+// Record returns the record of the individual with the given ID.
+func (l *Lineage) Record(id uint64) (Record, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	r, ok := l.records[id]
+	return r, ok
+}
+
+// Ancestry returns the records of the individual with the given ID and
+// of its ancestors up to depth generations of parents back, ordered by
+// ID, so parents come before their children. A negative depth means
+// there's no limit.
+func (l *Lineage) Ancestry(id uint64, depth int) ([]Record, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if _, ok := l.records[id]; !ok {
+		return nil, fmt.Errorf("individual %d isn't in the lineage", id)
+	}
+	seen := map[uint64]bool{id: true}
+	curr := []uint64{id}
+	for level := 0; depth < 0 || level < depth; level++ {
+		var next []uint64
+		for _, child := range curr {
+			for _, parent := range l.records[child].Parents {
+				if _, ok := l.records[parent]; ok && !seen[parent] {
+					seen[parent] = true
+					next = append(next, parent)
+				}
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+		curr = next
+	}
+	out := make([]Record, 0, len(seen))
+	for id := range seen {
+		out = append(out, l.records[id])
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// WriteDOT writes the ancestry of the individual with the given ID,
+// as Ancestry returns it, as a Graphviz digraph with edges from
+// parents to children.
+func (l *Lineage) WriteDOT(w io.Writer, id uint64, depth int) error {
+	records, err := l.Ancestry(id, depth)
+	if err != nil {
+		return err
+	}
+	included := make(map[uint64]bool, len(records))
+	for _, r := range records {
+		included[r.ID] = true
+	}
+	out := &strings.Builder{}
+	fmt.Fprintln(out, "digraph lineage {")
+	fmt.Fprintln(out, "  node [shape=box];")
+	for _, r := range records {
+		label := fmt.Sprintf("%d\ngeneration %d\n%s\nscore %g", r.ID, r.Born, r.Operator, r.Score)
+		fmt.Fprintf(out, "  n%d [label=%s];\n", r.ID, strconv.Quote(label))
+	}
+	for _, r := range records {
+		for _, parent := range r.Parents {
+			if included[parent] {
+				fmt.Fprintf(out, "  n%d -> n%d;\n", parent, r.ID)
+			}
+		}
+	}
+	fmt.Fprintln(out, "}")
+	_, err = io.WriteString(w, out.String())
+	return err
+}
+
+// Newick returns the ancestry of the individual with the given ID, up
+// to depth generations of parents back, as a Newick tree rooted at the
+// individual, whose children are its parents. An ancestor shared
+// through several parents has its own ancestry written the first time
+// it appears only, and is a leaf with just its label after that, so the
+// tree grows with the size of the ancestry rather than the number of
+// paths through it. Nodes are labelled with IDs, and branch lengths
+// are the generations between parent and child.
+func (l *Lineage) Newick(id uint64, depth int) (string, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if _, ok := l.records[id]; !ok {
+		return "", fmt.Errorf("individual %d isn't in the lineage", id)
+	}
+	out := &strings.Builder{}
+	l.newick(out, id, depth, map[uint64]bool{})
+	out.WriteString(";")
+	return out.String(), nil
+}
+
+func (l *Lineage) newick(out *strings.Builder, id uint64, depth int, written map[uint64]bool) {
+	r := l.records[id]
+	var parents []uint64
+	if depth != 0 && !written[id] {
+		for _, parent := range r.Parents {
+			if _, ok := l.records[parent]; ok {
+				parents = append(parents, parent)
+			}
+		}
+	}
+	if depth != 0 {
+		written[id] = true
+	}
+	if len(parents) > 0 {
+		out.WriteString("(")
+		for i, parent := range parents {
+			if i > 0 {
+				out.WriteString(",")
+			}
+			l.newick(out, parent, depth-1, written)
+			fmt.Fprintf(out, ":%d", r.Born-l.records[parent].Born)
+		}
+		out.WriteString(")")
+	}
+	fmt.Fprint(out, id)
+}
+
+// Lineage returns the lineage of the search, or
+// nil if Params.Lineage isn't set.
+func (c *Controller) Lineage() *Lineage {
+	return c.lineage
+}
+
+// FittestID returns the lineage ID of the fittest
+// individual in the current population.
+func (c *Controller) FittestID() (uint64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.population.pop) == 0 {
+		return 0, errors.New("population is empty")
+	}
+	return c.population.pop[0].id, nil
+}
@@ -0,0 +1,190 @@
+package genetic
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// family is a hand-built lineage: 3 is bred from 1 and 2,
+// and 4 from 3 and 2.
+func family() *Lineage {
+	f := newPedigree(1)
+	l := newLineage()
+	f.add(nil, 0)
+	f.add(nil, 0)
+	l.add([]indWithScore{
+		{id: 1, operator: "initial", score: 1},
+		{id: 2, operator: "initial", score: 2},
+	}, f)
+	f.add([]uint64{1, 2}, 1)
+	l.add([]indWithScore{
+		{id: 2, operator: "initial", score: 2},
+		{id: 3, born: 1, operator: "crossover+mutation", score: 3},
+	}, f)
+	f.add([]uint64{3, 2}, 2)
+	l.add([]indWithScore{{id: 4, born: 2, operator: "copy+mutation", score: 4}}, f)
+	return l
+}
+
+func ids(records []Record) []uint64 {
+	var out []uint64
+	for _, r := range records {
+		out = append(out, r.ID)
+	}
+	return out
+}
+
+func Test_Lineage_Ancestry_LimitedByDepth(t *testing.T) {
+	l := family()
+	assert.Equal(t, 4, l.Len())
+
+	records, err := l.Ancestry(4, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3, 4}, ids(records))
+
+	records, err = l.Ancestry(4, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3, 4}, ids(records))
+
+	records, err = l.Ancestry(4, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{4}, ids(records))
+
+	_, err = l.Ancestry(9, 1)
+	assert.Error(t, err)
+}
+
+func Test_Lineage_Record_Kept(t *testing.T) {
+	r, ok := family().Record(3)
+	assert.True(t, ok)
+	assert.Equal(t, Record{ID: 3, Parents: []uint64{1, 2}, Operator: "crossover+mutation", Born: 1, Score: 3}, r)
+	_, ok = family().Record(9)
+	assert.False(t, ok)
+}
+
+func Test_Lineage_Newick_Tree(t *testing.T) {
+	l := family()
+	tree, err := l.Newick(4, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, "((1:1,2:1)3:1,2:2)4;", tree)
+
+	tree, err = l.Newick(4, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "(3:1,2:2)4;", tree)
+
+	tree, err = l.Newick(1, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, "1;", tree)
+
+	_, err = l.Newick(9, 1)
+	assert.Error(t, err)
+}
+
+func Test_Lineage_Newick_SharedAncestorsOnce(t *testing.T) {
+	// Both members of every generation are bred from both members
+	// of the one before, so there are 2^40 paths to the founders
+	f := newPedigree(1)
+	l := newLineage()
+	prev := []uint64{f.add(nil, 0), f.add(nil, 0)}
+	l.add([]indWithScore{{id: prev[0]}, {id: prev[1]}}, f)
+	for gen := 1; gen <= 40; gen++ {
+		curr := []uint64{f.add(prev, gen), f.add(prev, gen)}
+		l.add([]indWithScore{{id: curr[0], born: gen}, {id: curr[1], born: gen}}, f)
+		prev = curr
+	}
+
+	tree, err := l.Newick(prev[0], -1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1+39*2, strings.Count(tree, "(")) // one per expanded node
+	assert.Equal(t, 2*(1+39*2), strings.Count(tree, ":"))
+}
+
+func Test_Lineage_WriteDOT_Graph(t *testing.T) {
+	out := &bytes.Buffer{}
+	assert.NoError(t, family().WriteDOT(out, 4, 1))
+	dot := out.String()
+	assert.Contains(t, dot, "digraph lineage {")
+	assert.Contains(t, dot, `n3 [label="3\ngeneration 1\ncrossover+mutation\nscore 3"];`)
+	assert.Contains(t, dot, "n3 -> n4;")
+	assert.Contains(t, dot, "n2 -> n4;")
+	assert.Contains(t, dot, "n2 -> n3;")
+	assert.NotContains(t, dot, "n1")
+}
+
+func Test_Controller_Lineage_NotTracked_Nil(t *testing.T) {
+	ctrl, err := NewController(Params{SelectionMethod: Tournament(2), InitPop: make([]fakeIndividual, 2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, ctrl.Lineage())
+}
+
+func Test_Controller_Lineage_EveryMemberRecorded(t *testing.T) {
+	ctrl, err := NewController(Params{
+		Elitism:         1,
+		Crossover:       1,
+		TargetFitness:   10,
+		SelectionMethod: Tournament(2),
+		InitPop:         []fakeIndividual{{id: 1, fitness: 1}, {id: 2, fitness: 2}, {id: 3, fitness: 3}},
+		Termination:     Termination{MaxGenerations: 3},
+		Lineage:         true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ErrTerminated, ctrl.Run())
+
+	l := ctrl.Lineage()
+	assert.Equal(t, 3+3*2, l.Len())
+	id, err := ctrl.FittestID()
+	assert.NoError(t, err)
+	fittest, ok := l.Record(id)
+	assert.True(t, ok)
+	assert.Equal(t, 3.0, fittest.Score)
+
+	for i := uint64(1); i <= uint64(l.Len()); i++ {
+		r, ok := l.Record(i)
+		assert.True(t, ok, i)
+		if i <= 3 {
+			assert.Equal(t, "initial", r.Operator)
+			assert.Empty(t, r.Parents)
+			continue
+		}
+		assert.Equal(t, "crossover+mutation", r.Operator)
+		assert.Len(t, r.Parents, 2)
+		assert.True(t, r.Born >= 1 && r.Born <= 3)
+		for _, parent := range r.Parents {
+			p, ok := l.Record(parent)
+			assert.True(t, ok)
+			assert.True(t, p.Born < r.Born)
+		}
+	}
+}
+
+func Test_Controller_Lineage_OperatorsNamed(t *testing.T) {
+	ctrl, err := NewController(Params{
+		Crossover:       1,
+		TargetFitness:   10,
+		SelectionMethod: Tournament(2),
+		InitPop:         make([]fakeIndividual, 2),
+		Termination:     Termination{MaxGenerations: 1},
+		Lineage:         true,
+		CrossoverOperators: []CrossoverOperator{{Name: "first", Crossover: func(parents []Individual, n int) ([]Individual, error) {
+			return parents[:1], nil
+		}}},
+		MutationOperators: []MutationOperator{{Name: "same", Mutate: func(ind Individual, rate float64) (Individual, error) {
+			return ind, nil
+		}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ErrTerminated, ctrl.Run())
+
+	r, ok := ctrl.Lineage().Record(3)
+	assert.True(t, ok)
+	assert.Equal(t, "first+same", r.Operator)
+}
@@ -0,0 +1,121 @@
+package local
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// Cooling gives the temperature of simulated annealing at the
+// given step, starting from the initial temperature.
+type Cooling func(initial float64, step int) float64
+
+// Geometric returns a Cooling that multiplies the
+// temperature by alpha, between 0 and 1, every step.
+func Geometric(alpha float64) Cooling {
+	return func(initial float64, step int) float64 {
+		return initial * math.Pow(alpha, float64(step))
+	}
+}
+
+// Linear returns a Cooling that lowers the temperature in
+// a straight line to zero over the given number of steps.
+func Linear(steps int) Cooling {
+	return func(initial float64, step int) float64 {
+		return initial * math.Max(0, 1-float64(step)/float64(steps))
+	}
+}
+
+// Logarithmic returns the Cooling of Geman and Geman (1984), which
+// divides the initial temperature by ln(step+2). It cools very slowly.
+func Logarithmic() Cooling {
+	return func(initial float64, step int) float64 {
+		return initial / math.Log(float64(step)+2)
+	}
+}
+
+// LundyMees returns the Cooling of Lundy and Mees (1986), where every
+// step turns the temperature T into T/(1+beta*T).
+func LundyMees(beta float64) Cooling {
+	return func(initial float64, step int) float64 {
+		return initial / (1 + float64(step)*beta*initial)
+	}
+}
+
+// AnnealingParams holds the parameters for simulated annealing.
+type AnnealingParams struct {
+	Params
+
+	// Cooling is the cooling schedule. The default is Geometric(0.99).
+	Cooling Cooling
+
+	// Temperature is the initial temperature. By default, it's chosen
+	// so that an average worsening among a sample of neighbours of the
+	// initial solution is accepted half of the time.
+	Temperature float64
+}
+
+// NewSimulatedAnnealing is the constructor for simulated annealing,
+// which tries a single neighbour every step, always moving to it if
+// it's no worse and otherwise with probability exp(Δ/T), where Δ is the
+// difference in scores and T the temperature. It returns an error if
+// any of the input parameters are not allowed.
+func NewSimulatedAnnealing(params AnnealingParams) (*Search, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	if params.Cooling == nil {
+		params.Cooling = Geometric(0.99)
+	}
+	if params.Temperature < 0 {
+		return nil, errors.New("initial temperature cannot be negative")
+	}
+	return newSearch(params.Params, &annealing{params: params}), nil
+}
+
+// temperatureSamples is how many neighbours are sampled
+// to choose the initial temperature.
+const temperatureSamples = 20
+
+type annealing struct {
+	params AnnealingParams
+}
+
+func (a *annealing) step(s *Search) error {
+	if a.params.Temperature == 0 {
+		if err := a.calibrate(s); err != nil {
+			return err
+		}
+	}
+	neighbours, scores, err := s.neighbours(1)
+	if err != nil {
+		return err
+	}
+	delta := scores[0] - s.currentScore
+	t := a.params.Cooling(a.params.Temperature, s.generation-1)
+	if delta >= 0 || (t > 0 && rand.Float64() < math.Exp(delta/t)) {
+		s.move(neighbours[0], scores[0])
+	}
+	return nil
+}
+
+// calibrate sets the initial temperature so that an average worsening
+// is accepted with probability one half.
+func (a *annealing) calibrate(s *Search) error {
+	_, scores, err := s.neighbours(temperatureSamples)
+	if err != nil {
+		return err
+	}
+	sum, count := float64(0), 0
+	for _, score := range scores {
+		if score < s.currentScore {
+			sum += s.currentScore - score
+			count++
+		}
+	}
+	a.params.Temperature = 1
+	if count > 0 {
+		a.params.Temperature = sum / float64(count) / math.Ln2
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package local
+
+import (
+	"errors"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// HillClimbingParams holds the parameters for hill climbing.
+type HillClimbingParams struct {
+	Params
+
+	// Steepest selects steepest ascent, which evaluates all Neighbors
+	// neighbours every step and moves to the best of them if it's an
+	// improvement. Otherwise, the search moves to the first improving
+	// neighbour, evaluating them Parallelism at a time.
+	Steepest bool
+
+	// Neighbors is how many neighbours are tried every step.
+	// The default is 10.
+	Neighbors int
+
+	// Patience is how many steps without improvement make the current
+	// solution a local optimum. The default is 1.
+	Patience int
+
+	// MaxRestarts limits the restarts from local optima, which need a
+	// Generator; zero means there's no limit besides Termination.
+	// Without a Generator, reaching a local optimum ends the search
+	// with genetic.ErrTerminated.
+	MaxRestarts int
+}
+
+// NewHillClimbing is the constructor for hill climbing. It returns an
+// error if any of the input parameters are not allowed.
+func NewHillClimbing(params HillClimbingParams) (*Search, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	if params.Neighbors == 0 {
+		params.Neighbors = 10
+	}
+	if params.Patience == 0 {
+		params.Patience = 1
+	}
+	if params.Neighbors < 1 || params.Patience < 1 || params.MaxRestarts < 0 {
+		return nil, errors.New("neighbours and patience must be positive, and maximum restarts non-negative")
+	}
+	return newSearch(params.Params, &hillClimbing{params: params}), nil
+}
+
+type hillClimbing struct {
+	params HillClimbingParams
+	idle   int
+}
+
+func (h *hillClimbing) step(s *Search) error {
+	improved := false
+	if h.params.Steepest {
+		neighbours, scores, err := s.neighbours(h.params.Neighbors)
+		if err != nil {
+			return err
+		}
+		best := 0
+		for i, score := range scores {
+			if score > scores[best] {
+				best = i
+			}
+		}
+		if scores[best] > s.currentScore {
+			s.move(neighbours[best], scores[best])
+			improved = true
+		}
+	} else {
+		batch := s.params.Parallelism
+		if batch < 1 {
+			batch = 1
+		}
+		for tried := 0; tried < h.params.Neighbors && !improved; tried += batch {
+			if batch > h.params.Neighbors-tried {
+				batch = h.params.Neighbors - tried
+			}
+			neighbours, scores, err := s.neighbours(batch)
+			if err != nil {
+				return err
+			}
+			improved = h.first(s, neighbours, scores)
+		}
+	}
+
+	if improved {
+		h.idle = 0
+		return nil
+	}
+	h.idle++
+	if h.idle < h.params.Patience {
+		return nil
+	}
+	h.idle = 0
+	if s.params.Generator == nil || (h.params.MaxRestarts > 0 && s.Restarts() >= h.params.MaxRestarts) {
+		return genetic.ErrTerminated
+	}
+	return s.restart()
+}
+
+// first moves to the first improving neighbour, if there is one.
+func (h *hillClimbing) first(s *Search, neighbours []genetic.Individual, scores []float64) bool {
+	for i, score := range scores {
+		if score > s.currentScore {
+			s.move(neighbours[i], score)
+			return true
+		}
+	}
+	return false
+}
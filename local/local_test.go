@@ -0,0 +1,189 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomjcleveland/genetic"
+)
+
+// bits is a OneMax individual: its fitness is its number of ones.
+type bits []bool
+
+func (b bits) Mutate(rate float64) (genetic.Individual, error) {
+	out := append(bits{}, b...)
+	i := rand.Intn(len(out))
+	out[i] = !out[i]
+	return out, nil
+}
+
+func (b bits) Crossover(genetic.Individual) (genetic.Individual, error) {
+	return b, nil
+}
+
+func (b bits) Fitness() (float64, error) {
+	score := float64(0)
+	for _, bit := range b {
+		if bit {
+			score++
+		}
+	}
+	return score, nil
+}
+
+func (b bits) String() string {
+	return fmt.Sprint([]bool(b))
+}
+
+// peak is a solution with no better neighbours.
+type peak struct{ bits }
+
+func (p peak) Mutate(rate float64) (genetic.Individual, error) {
+	return bits{}, nil
+}
+
+func randomBits() (genetic.Individual, error) {
+	out := make(bits, 20)
+	for i := range out {
+		out[i] = rand.Intn(2) == 0
+	}
+	return out, nil
+}
+
+func run(t *testing.T, s *Search) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFunc()
+	s.Start(ctx)
+	if err := s.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	fittest, err := s.Fittest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	score, _ := fittest.Fitness()
+	assert.Equal(t, float64(20), score)
+}
+
+func Test_HillClimbing_OneMax_Run(t *testing.T) {
+	for name, steepest := range map[string]bool{"first": false, "steepest": true} {
+		t.Run(name, func(t *testing.T) {
+			s, err := NewHillClimbing(HillClimbingParams{
+				Params:   Params{Generator: randomBits, TargetFitness: 20, Parallelism: 4},
+				Steepest: steepest,
+				Patience: 10,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			run(t, s)
+			stats := s.Stats()
+			assert.Equal(t, 0, stats[0].Generation)
+			for i := 1; i < len(stats); i++ {
+				assert.True(t, stats[i].Best >= stats[i-1].Best)
+			}
+		})
+	}
+}
+
+func Test_HillClimbing_LocalOptimum_Restarts(t *testing.T) {
+	s, err := NewHillClimbing(HillClimbingParams{
+		Params:      Params{Initial: peak{make(bits, 20)}, Generator: randomBits, TargetFitness: 100},
+		Neighbors:   1,
+		MaxRestarts: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, genetic.ErrTerminated, s.Run())
+	assert.Equal(t, 1, s.Restarts())
+}
+
+func Test_HillClimbing_NoGenerator_Terminates(t *testing.T) {
+	s, err := NewHillClimbing(HillClimbingParams{
+		Params: Params{Initial: peak{make(bits, 20)}, TargetFitness: 100},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, genetic.ErrTerminated, s.Run())
+	assert.Equal(t, 0, s.Restarts())
+}
+
+func Test_SimulatedAnnealing_OneMax_Run(t *testing.T) {
+	s, err := NewSimulatedAnnealing(AnnealingParams{
+		Params:  Params{Generator: randomBits, TargetFitness: 20},
+		Cooling: Geometric(0.95),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run(t, s)
+}
+
+func Test_Cooling(t *testing.T) {
+	assert.InDelta(t, 10*0.81, Geometric(0.9)(10, 2), 1e-9)
+	assert.InDelta(t, 5, Linear(10)(10, 5), 1e-9)
+	assert.InDelta(t, 0, Linear(10)(10, 20), 1e-9)
+	assert.InDelta(t, 10/math.Ln2, Logarithmic()(10, 0), 1e-9)
+	assert.InDelta(t, 10/(1+2*0.1*10), LundyMees(0.1)(10, 2), 1e-9)
+}
+
+func Test_TabuSearch_OneMax_Run(t *testing.T) {
+	s, err := NewTabuSearch(TabuParams{
+		Params: Params{Generator: randomBits, TargetFitness: 20, Parallelism: 4},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run(t, s)
+}
+
+func Test_TabuSearch_LeavesLocalOptimum(t *testing.T) {
+	// With all neighbours worse, tabu search still moves,
+	// while never going straight back
+	s, err := NewTabuSearch(TabuParams{
+		Params:    Params{Initial: bits{true, true, true}, TargetFitness: 100, Termination: genetic.Termination{MaxGenerations: 3}},
+		Neighbors: 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	visits := map[string]bool{}
+	s.params.Observer = func(genetic.Stats) {
+		visits[fmt.Sprint(s.current)] = true
+	}
+	assert.Equal(t, genetic.ErrTerminated, s.Run())
+	assert.Equal(t, 4, len(visits))
+}
+
+func Test_Search_Cancelled(t *testing.T) {
+	s, err := NewSimulatedAnnealing(AnnealingParams{
+		Params: Params{Generator: randomBits, TargetFitness: 100},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	cancelFunc()
+	s.Start(ctx)
+	assert.Equal(t, genetic.ErrContextCancelled, s.Wait())
+}
+
+func Test_Params_Invalid_Error(t *testing.T) {
+	_, err := NewHillClimbing(HillClimbingParams{})
+	assert.Error(t, err)
+	_, err = NewHillClimbing(HillClimbingParams{Params: Params{Generator: randomBits, Mutation: 2}})
+	assert.Error(t, err)
+	_, err = NewHillClimbing(HillClimbingParams{Params: Params{Generator: randomBits}, Neighbors: -1})
+	assert.Error(t, err)
+	_, err = NewSimulatedAnnealing(AnnealingParams{Params: Params{Generator: randomBits}, Temperature: -1})
+	assert.Error(t, err)
+	_, err = NewTabuSearch(TabuParams{Params: Params{Generator: randomBits}, Tenure: -1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,261 @@
+// Package local implements single-solution local searches: hill
+// climbing, simulated annealing and tabu search. They drive any
+// genetic.Individual through its Mutate and Fitness methods, so they
+// make baselines for the genetic algorithm on the same problem, and
+// share its Start, Wait and Fittest API. Mutate must not modify the
+// individual it is called on.
+package local
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Params holds the parameters every local search shares.
+type Params struct {
+	// Initial is the solution the search starts from.
+	Initial genetic.Individual
+
+	// Generator makes new starting points, for the initial solution if
+	// Initial isn't set and for restarts.
+	Generator genetic.Generator
+
+	// Mutation is the rate neighbours are made with. The default is 0.1.
+	Mutation float64
+
+	// TargetFitness is the fitness at which the search terminates.
+	TargetFitness float64
+
+	// Termination limits the search. Every step of the search
+	// counts as a generation.
+	Termination genetic.Termination
+
+	// Observer, if set, is told about every step.
+	Observer genetic.Observer
+
+	// Parallelism is how many goroutines evaluate neighbours.
+	Parallelism int
+}
+
+func (params *Params) validate() error {
+	if params.Initial == nil && params.Generator == nil {
+		return errors.New("either an initial solution or a generator is needed")
+	}
+	if params.Mutation == 0 {
+		params.Mutation = 0.1
+	}
+	if params.Mutation < 0 || params.Mutation > 1 {
+		return errors.New("mutation rate must be between 0 and 1, inclusive")
+	}
+	return nil
+}
+
+// strategy is a local search algorithm.
+type strategy interface {
+	// step moves the search on by one step, from s.current.
+	step(s *Search) error
+}
+
+// Search coordinates the running of a local search.
+type Search struct {
+	params   Params
+	strategy strategy
+
+	current      genetic.Individual
+	currentScore float64
+	generation   int
+	evaluations  int
+	start        time.Time
+
+	// scores holds the scores of the candidates evaluated this step.
+	scores []float64
+
+	// mu guards the fields below, which are read while
+	// the search runs in its own goroutine.
+	mu        sync.RWMutex
+	best      genetic.Individual
+	bestScore float64
+	restarts  int
+	stats     []genetic.Stats
+
+	err chan error
+}
+
+func newSearch(params Params, s strategy) *Search {
+	return &Search{
+		params:    params,
+		strategy:  s,
+		bestScore: math.Inf(-1),
+		err:       make(chan error),
+	}
+}
+
+// Run runs the search until a solution with the target
+// fitness is found, or the search terminates.
+func (s *Search) Run() error {
+	s.Start(context.Background())
+	return s.Wait()
+}
+
+// Start begins the search in a new goroutine, and returns immediately.
+// The context parameter can be used to prematurely cancel it.
+func (s *Search) Start(ctx context.Context) {
+	go func() {
+		s.err <- s.run(ctx)
+	}()
+}
+
+// Wait blocks until the search has finished.
+func (s *Search) Wait() error {
+	if err, ok := <-s.err; ok {
+		return err
+	}
+	return errors.New("error channel is closed")
+}
+
+// Fittest returns the fittest solution found so far.
+func (s *Search) Fittest() (genetic.Individual, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.best == nil {
+		return nil, errors.New("no solution has been evaluated yet")
+	}
+	return s.best, nil
+}
+
+// Stats returns the statistics of every step so far, starting with
+// the initial solution. Best is the best score found so far, while
+// Mean, Worst and Diversity describe the candidates of that step.
+func (s *Search) Stats() []genetic.Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]genetic.Stats{}, s.stats...)
+}
+
+// Restarts returns how many times the search has restarted.
+func (s *Search) Restarts() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.restarts
+}
+
+func (s *Search) run(ctx context.Context) error {
+	s.start = time.Now()
+	initial := s.params.Initial
+	if initial == nil {
+		var err error
+		if initial, err = s.params.Generator(); err != nil {
+			return err
+		}
+	}
+	score, err := initial.Fitness()
+	if err != nil {
+		return err
+	}
+	s.evaluations++
+	s.move(initial, score)
+	s.scores = []float64{score}
+	s.record()
+
+	for s.bestScore < s.params.TargetFitness {
+		select {
+		case <-ctx.Done():
+			return genetic.ErrContextCancelled
+		default:
+		}
+		if s.params.Termination.Done(s.stats) {
+			return genetic.ErrTerminated
+		}
+		s.generation++
+		s.scores = nil
+		if err := s.strategy.step(s); err != nil {
+			return err
+		}
+		s.record()
+	}
+	return nil
+}
+
+// neighbours mutates the current solution n times,
+// and evaluates the neighbours concurrently.
+func (s *Search) neighbours(n int) ([]genetic.Individual, []float64, error) {
+	out := make([]genetic.Individual, n)
+	for i := range out {
+		var err error
+		if out[i], err = s.current.Mutate(s.params.Mutation); err != nil {
+			return nil, nil, err
+		}
+	}
+	scores, err := genetic.EvaluateConcurrently(n, s.params.Parallelism, func(i int) (float64, error) {
+		return out[i].Fitness()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	s.evaluations += n
+	s.scores = append(s.scores, scores...)
+	return out, scores, nil
+}
+
+// move makes ind the current solution.
+func (s *Search) move(ind genetic.Individual, score float64) {
+	s.current, s.currentScore = ind, score
+	if score > s.bestScore {
+		s.mu.Lock()
+		s.best, s.bestScore = ind, score
+		s.mu.Unlock()
+	}
+}
+
+// restart moves to a new starting point from the generator.
+func (s *Search) restart() error {
+	ind, err := s.params.Generator()
+	if err != nil {
+		return err
+	}
+	score, err := ind.Fitness()
+	if err != nil {
+		return err
+	}
+	s.evaluations++
+	s.scores = append(s.scores, score)
+	s.mu.Lock()
+	s.restarts++
+	s.mu.Unlock()
+	s.move(ind, score)
+	return nil
+}
+
+// record adds the statistics of the current step,
+// and notifies the observer.
+func (s *Search) record() {
+	stats := genetic.Stats{
+		Generation:     s.generation,
+		Best:           s.bestScore,
+		Worst:          math.Inf(1),
+		PopulationSize: len(s.scores),
+		Evaluations:    s.evaluations,
+		Elapsed:        time.Since(s.start),
+	}
+	for _, score := range s.scores {
+		stats.Mean += score / float64(len(s.scores))
+		stats.Worst = math.Min(stats.Worst, score)
+	}
+	for _, score := range s.scores {
+		stats.Diversity += (score - stats.Mean) * (score - stats.Mean) / float64(len(s.scores))
+	}
+	stats.Diversity = math.Sqrt(stats.Diversity)
+
+	s.mu.Lock()
+	s.stats = append(s.stats, stats)
+	s.mu.Unlock()
+
+	if s.params.Observer != nil {
+		s.params.Observer(stats)
+	}
+}
@@ -0,0 +1,90 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// TabuParams holds the parameters for tabu search.
+type TabuParams struct {
+	Params
+
+	// Neighbors is how many neighbours are tried every step.
+	// The default is 10.
+	Neighbors int
+
+	// Tenure is how many steps a visited solution stays tabu.
+	// The default is 10.
+	Tenure int
+
+	// Key identifies solutions, so that revisits can be recognized. The
+	// default formats the solution with fmt.Sprint.
+	Key func(genetic.Individual) string
+}
+
+// NewTabuSearch is the constructor for tabu search, which moves to the
+// best neighbour every step, even if it's worse than the current
+// solution, as long as it hasn't been visited within the last Tenure
+// steps. A tabu neighbour is only allowed if it's better than any
+// solution found so far. It returns an error if any of the input
+// parameters are not allowed.
+func NewTabuSearch(params TabuParams) (*Search, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	if params.Neighbors == 0 {
+		params.Neighbors = 10
+	}
+	if params.Tenure == 0 {
+		params.Tenure = 10
+	}
+	if params.Key == nil {
+		params.Key = func(ind genetic.Individual) string { return fmt.Sprint(ind) }
+	}
+	if params.Neighbors < 1 || params.Tenure < 1 {
+		return nil, errors.New("neighbours and tenure must be positive")
+	}
+	return newSearch(params.Params, &tabu{params: params, visited: make(map[string]int)}), nil
+}
+
+type tabu struct {
+	params TabuParams
+
+	// visited holds the step at which every recent solution was visited.
+	visited map[string]int
+}
+
+func (t *tabu) step(s *Search) error {
+	if s.generation == 1 {
+		t.visited[t.params.Key(s.current)] = 0
+	}
+	neighbours, scores, err := s.neighbours(t.params.Neighbors)
+	if err != nil {
+		return err
+	}
+	chosen := -1
+	var chosenKey string
+	for i, score := range scores {
+		if chosen >= 0 && score <= scores[chosen] {
+			continue
+		}
+		key := t.params.Key(neighbours[i])
+		if at, ok := t.visited[key]; ok && s.generation-at <= t.params.Tenure && score <= s.bestScore {
+			continue
+		}
+		chosen, chosenKey = i, key
+	}
+	if chosen < 0 {
+		return nil
+	}
+	s.move(neighbours[chosen], scores[chosen])
+	t.visited[chosenKey] = s.generation
+	for key, at := range t.visited {
+		if s.generation-at > t.params.Tenure {
+			delete(t.visited, key)
+		}
+	}
+	return nil
+}
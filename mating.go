@@ -0,0 +1,248 @@
+package genetic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// incestAttempts is how many times IncestPrevention asks its
+// underlying scheme for unrelated parents before giving up and
+// accepting related ones.
+const incestAttempts = 10
+
+// MatingScheme chooses the parents for a single mating. It returns
+// the positions of n parents within the population, which is sorted
+// from fittest to least fit.
+type MatingScheme func(pop *Population, n int) ([]int, error)
+
+// query asks the selection methods and mating schemes of this package
+// to describe themselves rather than choose, so that NewController can
+// check them up front. They find it on the population they're given.
+type query struct {
+	// minPopulation is the most members any of them needs.
+	minPopulation int
+
+	// ancestry is how many generations of parents
+	// any of them looks back on.
+	ancestry int
+
+	// err is why one of them can never choose.
+	err error
+}
+
+func (q *query) need(members int) {
+	if members > q.minPopulation {
+		q.minPopulation = members
+	}
+}
+
+func (q *query) remember(generations int) {
+	if generations > q.ancestry {
+		q.ancestry = generations
+	}
+}
+
+func (q *query) fail(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// describe asks the selection methods and mating schemes of this
+// package that scheme is made of about themselves. Schemes and methods
+// of your own choose from the population as usual, and what they
+// choose is ignored.
+func (p *Population) describe(scheme MatingScheme, n int) query {
+	q := &query{}
+	probe := &Population{pop: p.pop, rng: rand.New(rand.NewSource(1)), family: newPedigree(0), query: q}
+	scheme(probe, n)
+	return *q
+}
+
+// position returns the position of the Individual a SelectionMethod
+// chose. Methods built by ByPosition say where it is; otherwise it's
+// the first member equal to it under ==.
+func (p *Population) position(ind Individual) (int, error) {
+	if i := p.chosen; i >= 0 && i < len(p.pop) {
+		if equal, ok := same(p.pop[i].Individual, ind); equal || !ok {
+			return i, nil
+		}
+	}
+	for i, member := range p.pop {
+		if equal, _ := same(member.Individual, ind); equal {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("selection method chose %T that isn't in the population; use ByPosition for genomes that can't be compared with ==", ind)
+}
+
+// same compares a and b with ==, and reports whether they could be
+// compared at all: comparing interfaces that hold slices, maps or
+// functions panics.
+func same(a, b Individual) (equal, ok bool) {
+	defer func() {
+		if recover() != nil {
+			equal, ok = false, false
+		}
+	}()
+	return a == b, true
+}
+
+// Distance measures how different two individuals are. It is used by
+// mating schemes that care about the similarity of parents.
+type Distance func(a, b Individual) (float64, error)
+
+// SelectionMating returns a MatingScheme that picks every parent
+// independently with the given SelectionMethod. This is the default
+// scheme when Params.MatingScheme is nil.
+func SelectionMating(method SelectionMethod) MatingScheme {
+	return func(pop *Population, n int) ([]int, error) {
+		if pop.query != nil {
+			method(pop)
+			return nil, nil
+		}
+		parents := make([]int, n)
+		for i := range parents {
+			pop.chosen = -1
+			ind, err := method(pop)
+			if err != nil {
+				return nil, err
+			}
+			if parents[i], err = pop.position(ind); err != nil {
+				return nil, err
+			}
+		}
+		return parents, nil
+	}
+}
+
+// RandomPairing returns a MatingScheme that picks parents uniformly at
+// random, ignoring fitness. Parents are distinct whenever the population
+// is large enough.
+func RandomPairing() MatingScheme {
+	return func(pop *Population, n int) ([]int, error) {
+		if n > len(pop.pop) {
+			parents := make([]int, n)
+			for i := range parents {
+				parents[i] = pop.rng.Intn(len(pop.pop))
+			}
+			return parents, nil
+		}
+		return pop.rng.Perm(len(pop.pop))[:n], nil
+	}
+}
+
+// PositiveAssortative returns a MatingScheme that picks the first parent
+// with the given SelectionMethod, then draws candidates with the same
+// method and mates the first parent with the most similar of them. A nil
+// Distance compares fitness scores.
+func PositiveAssortative(method SelectionMethod, candidates int, dist Distance) MatingScheme {
+	return assortative(method, candidates, dist, 1)
+}
+
+// NegativeAssortative is like PositiveAssortative, but mates the first
+// parent with the least similar candidate. This counteracts premature
+// convergence by favouring dissimilar parents.
+func NegativeAssortative(method SelectionMethod, candidates int, dist Distance) MatingScheme {
+	return assortative(method, candidates, dist, -1)
+}
+
+func assortative(method SelectionMethod, candidates int, dist Distance, sign float64) MatingScheme {
+	selectOne := SelectionMating(method)
+	return func(pop *Population, n int) ([]int, error) {
+		if pop.query != nil {
+			return selectOne(pop, 1)
+		}
+		if candidates < 1 {
+			return nil, errors.New("assortative mating needs at least one candidate")
+		}
+		parents, err := selectOne(pop, 1)
+		if err != nil {
+			return nil, err
+		}
+		for len(parents) < n {
+			pool, err := selectOne(pop, candidates)
+			if err != nil {
+				return nil, err
+			}
+			best, bestDistance := -1, math.MaxFloat64
+			for _, candidate := range pool {
+				d, err := pop.distance(parents[0], candidate, dist)
+				if err != nil {
+					return nil, err
+				}
+				if sign*d < bestDistance {
+					best, bestDistance = candidate, sign*d
+				}
+			}
+			parents = append(parents, best)
+		}
+		return parents, nil
+	}
+}
+
+// IncestPrevention wraps a MatingScheme so that it rejects parents which
+// are too alike. Parents closer than minDistance under dist, or sharing
+// an ancestor within the given number of generations, are rejected and
+// the scheme is asked again. A nil Distance or zero generations disables
+// the respective check. If no acceptable parents turn up after a few
+// attempts, the last ones are used anyway so that the search can't stall.
+func IncestPrevention(scheme MatingScheme, dist Distance, minDistance float64, generations int) MatingScheme {
+	return func(pop *Population, n int) ([]int, error) {
+		if pop.query != nil {
+			pop.query.remember(generations)
+			return scheme(pop, n)
+		}
+		var parents []int
+		for attempt := 0; attempt < incestAttempts; attempt++ {
+			var err error
+			parents, err = scheme(pop, n)
+			if err != nil {
+				return nil, err
+			}
+			incest, err := pop.incestuous(parents, dist, minDistance, generations)
+			if err != nil {
+				return nil, err
+			}
+			if !incest {
+				break
+			}
+		}
+		return parents, nil
+	}
+}
+
+// incestuous reports whether any two of the given members are too
+// alike to mate, according to the IncestPrevention rules.
+func (p *Population) incestuous(members []int, dist Distance, minDistance float64, generations int) (bool, error) {
+	for i := 0; i < len(members); i++ {
+		for j := i + 1; j < len(members); j++ {
+			a, b := members[i], members[j]
+			if generations > 0 && p.family.related(p.pop[a].id, p.pop[b].id, generations) {
+				return true, nil
+			}
+			if dist == nil {
+				continue
+			}
+			d, err := dist(p.pop[a].Individual, p.pop[b].Individual)
+			if err != nil {
+				return false, err
+			}
+			if d < minDistance {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// distance measures how different two members are, falling back
+// on the difference between their scores when dist is nil.
+func (p *Population) distance(a, b int, dist Distance) (float64, error) {
+	if dist == nil {
+		return math.Abs(p.pop[a].score - p.pop[b].score), nil
+	}
+	return dist(p.pop[a].Individual, p.pop[b].Individual)
+}
@@ -0,0 +1,229 @@
+package genetic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func scoredPopulation(t *testing.T, scores ...float64) *Population {
+	popIn := make([]fakeIndividual, len(scores))
+	for i, score := range scores {
+		popIn[i] = fakeIndividual{id: i, fitness: score}
+	}
+	pop, err := NewPopulation(popIn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pop.family = newPedigree(1)
+	for i := range pop.pop {
+		pop.pop[i].score = scores[i]
+		pop.pop[i].id = pop.family.add(nil, 0)
+	}
+	return pop
+}
+
+func Test_SelectionMating_SelectedIndividualsReturnedAsIndices(t *testing.T) {
+	pop := scoredPopulation(t, 5, 4, 3, 2, 1)
+
+	parents, err := SelectionMating(Tournament(5))(pop, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []int{0, 0}, parents)
+}
+
+// boxedIndividual is comparable, but comparing two
+// of them panics when genes holds a slice.
+type boxedIndividual struct {
+	genes interface{}
+	n     int
+}
+
+func (b boxedIndividual) Crossover(Individual) (Individual, error) { return b, nil }
+func (b boxedIndividual) Mutate(float64) (Individual, error)       { return b, nil }
+func (b boxedIndividual) Fitness() (float64, error)                { return float64(b.n), nil }
+
+func Test_Run_UncomparableGenes_NoPanic(t *testing.T) {
+	ctrl, err := NewController(Params{
+		Crossover:       1,
+		TargetFitness:   10,
+		SelectionMethod: Tournament(2),
+		InitPop:         []boxedIndividual{{[]int{1}, 1}, {[]int{2}, 2}, {[]int{3}, 3}},
+		Termination:     Termination{MaxGenerations: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ErrTerminated, ctrl.Run())
+}
+
+func Test_SelectionMating_EqualMembers_OwnPositions(t *testing.T) {
+	pop := scoredPopulation(t, 1, 1, 1)
+	for i := range pop.pop {
+		pop.pop[i].Individual = fakeIndividual{}
+	}
+
+	parents, err := SelectionMating(walkingSelection())(pop, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []int{0, 1, 2}, parents)
+}
+
+func Test_SelectionMating_OwnSelectionFunc_PositionsFound(t *testing.T) {
+	pop := scoredPopulation(t, 5, 4, 3)
+	last := func(p *Population) (Individual, error) {
+		return p.At(p.Len() - 1).Individual, nil
+	}
+
+	parents, err := SelectionMating(last)(pop, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 2}, parents)
+
+	winner, err := Tournament(3)(pop)
+	assert.NoError(t, err)
+	assert.Equal(t, fakeIndividual{id: 0, fitness: 5}, winner)
+}
+
+func Test_SelectionMating_OwnSelectionFuncUncomparable_Error(t *testing.T) {
+	pop, err := NewPopulation([]boxedIndividual{{[]int{1}, 1}, {[]int{2}, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := func(p *Population) (Individual, error) {
+		return p.At(0).Individual, nil
+	}
+
+	_, err = SelectionMating(first)(pop, 2)
+	assert.Error(t, err)
+}
+
+func Test_RandomPairing_ParentsDistinct(t *testing.T) {
+	pop := scoredPopulation(t, 5, 4, 3, 2, 1)
+
+	parents, err := RandomPairing()(pop, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.ElementsMatch(t, []int{0, 1, 2, 3, 4}, parents)
+}
+
+// walkingSelection returns a SelectionMethod that picks
+// each member of the population in turn.
+func walkingSelection() SelectionMethod {
+	next := 0
+	return ByPosition(func(p *Population) (int, error) {
+		i := next % len(p.pop)
+		next++
+		return i, nil
+	})
+}
+
+func Test_PositiveAssortative_MostSimilarCandidateChosen(t *testing.T) {
+	pop := scoredPopulation(t, 10, 1, 9, 0)
+
+	parents, err := PositiveAssortative(walkingSelection(), 3, nil)(pop, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []int{0, 2}, parents)
+}
+
+func Test_NegativeAssortative_LeastSimilarCandidateChosen(t *testing.T) {
+	pop := scoredPopulation(t, 10, 9, 1, 0)
+
+	parents, err := NegativeAssortative(walkingSelection(), 3, nil)(pop, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []int{0, 3}, parents)
+}
+
+func Test_IncestPrevention_SiblingsRejected(t *testing.T) {
+	pop := scoredPopulation(t, 0, 0, 0, 0)
+	mother, father := pop.pop[0].id, pop.pop[1].id
+	pop.pop[2].id = pop.family.add([]uint64{mother, father}, 1)
+	pop.pop[3].id = pop.family.add([]uint64{mother, father}, 1)
+	calls := 0
+	scheme := MatingScheme(func(p *Population, n int) ([]int, error) {
+		calls++
+		if calls == 1 {
+			return []int{2, 3}, nil
+		}
+		return []int{0, 1}, nil
+	})
+
+	parents, err := IncestPrevention(scheme, nil, 0, 1)(pop, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []int{0, 1}, parents)
+	assert.Equal(t, 2, calls)
+}
+
+func Test_IncestPrevention_SimilarParentsRejected(t *testing.T) {
+	pop := scoredPopulation(t, 0, 0, 0)
+	dist := func(a, b Individual) (float64, error) {
+		d := float64(a.(fakeIndividual).id - b.(fakeIndividual).id)
+		if d < 0 {
+			d = -d
+		}
+		return d, nil
+	}
+	calls := 0
+	scheme := MatingScheme(func(p *Population, n int) ([]int, error) {
+		calls++
+		if calls == 1 {
+			return []int{0, 1}, nil
+		}
+		return []int{0, 2}, nil
+	})
+
+	parents, err := IncestPrevention(scheme, dist, 2, 0)(pop, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []int{0, 2}, parents)
+}
+
+func Test_pedigree_prune_OldAncestorsForgotten(t *testing.T) {
+	family := newPedigree(2)
+	grandparent := family.add(nil, 0)
+	parent := family.add([]uint64{grandparent}, 1)
+	child := family.add([]uint64{parent}, 2)
+
+	assert.True(t, family.ancestors(child, 2)[grandparent])
+	family.prune(4)
+	assert.False(t, family.ancestors(child, 2)[grandparent])
+}
+
+func Test_pedigree_NoDepth_NothingRemembered(t *testing.T) {
+	family := newPedigree(0)
+	parent := family.add(nil, 0)
+	child := family.add([]uint64{parent}, 1)
+
+	assert.Equal(t, uint64(2), child)
+	assert.Empty(t, family.parents)
+	assert.False(t, family.related(parent, child, 1))
+}
+
+func Test_IncestPrevention_DepthKnownUpFront(t *testing.T) {
+	scheme := IncestPrevention(IncestPrevention(RandomPairing(), nil, 0, 3), nil, 0, 2)
+	pop := scoredPopulation(t, 0, 0, 0)
+	assert.Equal(t, 3, pop.describe(scheme, 2).ancestry)
+	assert.Equal(t, 0, pop.describe(RandomPairing(), 2).ancestry)
+
+	ctrl, err := NewController(Params{MatingScheme: scheme, InitPop: make([]fakeIndividual, 3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 3, ctrl.population.family.depth)
+}
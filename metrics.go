@@ -0,0 +1,296 @@
+package genetic
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds, in seconds, of the buckets of
+// the fitness latency histogram.
+var LatencyBuckets = []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10}
+
+// Metrics collects measurements of a running search for monitoring:
+// its progress, fitness evaluation latency, how busy the Parallelism
+// workers are, and errors. Set it as Params.Metrics of one controller,
+// and serve it with Prometheus's text exposition format through
+// ServeHTTP, or publish it with expvar.
+type Metrics struct {
+	// Labels are added to every Prometheus sample, to tell the
+	// searches of a service apart. Set them before serving.
+	Labels map[string]string
+
+	mu             sync.Mutex
+	generation     int
+	best           float64
+	mean           float64
+	worst          float64
+	populationSize int
+	evaluations    int
+	rate           float64
+	elapsed        time.Duration
+	workers        int
+	busy           time.Duration
+	capacity       time.Duration
+	latency        []uint64
+	latencySum     float64
+	latencyCount   uint64
+	fitnessErrors  uint64
+	searchErrors   uint64
+}
+
+// NewMetrics returns an empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{workers: 1}
+}
+
+// MetricsSnapshot holds the values of a Metrics at one moment.
+type MetricsSnapshot struct {
+	Generation     int
+	Best           float64
+	Mean           float64
+	Worst          float64
+	PopulationSize int
+	Evaluations    int
+
+	// EvaluationsPerSecond is the rate of the latest generation.
+	EvaluationsPerSecond float64
+
+	// Workers is Params.Parallelism, and Utilization the fraction of
+	// the workers' time spent evaluating fitness, while evaluating.
+	Workers     int
+	Utilization float64
+
+	// LatencyCounts counts the fitness evaluations that took up to
+	// each of LatencyBuckets, cumulatively, like a Prometheus histogram.
+	LatencyCounts []uint64
+	LatencySum    float64
+	LatencyCount  uint64
+
+	// FitnessErrors counts the errors returned by Fitness(), and
+	// SearchErrors the searches that stopped with an error.
+	FitnessErrors uint64
+	SearchErrors  uint64
+}
+
+// Snapshot returns the current values.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := MetricsSnapshot{
+		Generation:           m.generation,
+		Best:                 m.best,
+		Mean:                 m.mean,
+		Worst:                m.worst,
+		PopulationSize:       m.populationSize,
+		Evaluations:          m.evaluations,
+		EvaluationsPerSecond: m.rate,
+		Workers:              m.workers,
+		LatencySum:           m.latencySum,
+		LatencyCount:         m.latencyCount,
+		FitnessErrors:        m.fitnessErrors,
+		SearchErrors:         m.searchErrors,
+	}
+	if m.capacity > 0 {
+		out.Utilization = float64(m.busy) / float64(m.capacity)
+	}
+	if out.Workers < 1 {
+		out.Workers = 1
+	}
+	out.LatencyCounts = make([]uint64, len(LatencyBuckets))
+	count := uint64(0)
+	for i := range out.LatencyCounts {
+		if i < len(m.latency) {
+			count += m.latency[i]
+		}
+		out.LatencyCounts[i] = count
+	}
+	return out
+}
+
+// setWorkers records the number of workers that evaluate fitness.
+func (m *Metrics) setWorkers(workers int) {
+	if m == nil {
+		return
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	m.mu.Lock()
+	m.workers = workers
+	m.mu.Unlock()
+}
+
+// observe records the statistics of a generation.
+func (m *Metrics) observe(stats Stats) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if d := stats.Elapsed - m.elapsed; d > 0 && stats.Generation > m.generation {
+		m.rate = float64(stats.Evaluations-m.evaluations) / d.Seconds()
+	} else if stats.Elapsed > 0 {
+		m.rate = float64(stats.Evaluations) / stats.Elapsed.Seconds()
+	}
+	m.generation = stats.Generation
+	m.best = stats.Best
+	m.mean = stats.Mean
+	m.worst = stats.Worst
+	m.populationSize = stats.PopulationSize
+	m.evaluations = stats.Evaluations
+	m.elapsed = stats.Elapsed
+}
+
+// evaluated records a fitness evaluation.
+func (m *Metrics) evaluated(d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	seconds := d.Seconds()
+	i := sort.SearchFloat64s(LatencyBuckets, seconds)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.latency == nil {
+		m.latency = make([]uint64, len(LatencyBuckets))
+	}
+	if i < len(m.latency) {
+		m.latency[i]++
+	}
+	m.latencySum += seconds
+	m.latencyCount++
+	m.busy += d
+	if err != nil {
+		m.fitnessErrors++
+	}
+}
+
+// evaluating records how long the workers spent on a round of evaluation.
+func (m *Metrics) evaluating(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.capacity += d * time.Duration(m.workers)
+}
+
+// failed records a search that stopped with an error.
+func (m *Metrics) failed(err error) {
+	if m == nil || err == nil || err == ErrTerminated || err == ErrContextCancelled {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.searchErrors++
+}
+
+// ServeHTTP writes the metrics in Prometheus's text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WritePrometheus(w)
+}
+
+// WritePrometheus writes the metrics in Prometheus's
+// text exposition format.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	s := m.Snapshot()
+	out := &strings.Builder{}
+	metric := func(name, kind, help string) {
+		fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+	sample := func(name string, extra string, value float64) {
+		fmt.Fprintf(out, "%s%s %s\n", name, m.labels(extra), promFloat(value))
+	}
+	metric("genetic_generation", "gauge", "Current generation of the search.")
+	sample("genetic_generation", "", float64(s.Generation))
+	metric("genetic_fitness_best", "gauge", "Best fitness score of the current population.")
+	sample("genetic_fitness_best", "", s.Best)
+	metric("genetic_fitness_mean", "gauge", "Mean fitness score of the current population.")
+	sample("genetic_fitness_mean", "", s.Mean)
+	metric("genetic_fitness_worst", "gauge", "Worst fitness score of the current population.")
+	sample("genetic_fitness_worst", "", s.Worst)
+	metric("genetic_population_size", "gauge", "Size of the current population.")
+	sample("genetic_population_size", "", float64(s.PopulationSize))
+	metric("genetic_evaluations_total", "counter", "Fitness evaluations performed.")
+	sample("genetic_evaluations_total", "", float64(s.Evaluations))
+	metric("genetic_evaluations_per_second", "gauge", "Fitness evaluations per second in the latest generation.")
+	sample("genetic_evaluations_per_second", "", s.EvaluationsPerSecond)
+	metric("genetic_workers", "gauge", "Goroutines that evaluate fitness.")
+	sample("genetic_workers", "", float64(s.Workers))
+	metric("genetic_worker_utilization", "gauge", "Fraction of the workers' time spent evaluating fitness.")
+	sample("genetic_worker_utilization", "", s.Utilization)
+	metric("genetic_fitness_latency_seconds", "histogram", "Time taken by a fitness evaluation.")
+	for i, bound := range LatencyBuckets {
+		sample("genetic_fitness_latency_seconds_bucket", `le="`+promFloat(bound)+`"`, float64(s.LatencyCounts[i]))
+	}
+	sample("genetic_fitness_latency_seconds_bucket", `le="+Inf"`, float64(s.LatencyCount))
+	sample("genetic_fitness_latency_seconds_sum", "", s.LatencySum)
+	sample("genetic_fitness_latency_seconds_count", "", float64(s.LatencyCount))
+	metric("genetic_errors_total", "counter", "Errors from fitness evaluations and searches.")
+	sample("genetic_errors_total", `kind="fitness"`, float64(s.FitnessErrors))
+	sample("genetic_errors_total", `kind="search"`, float64(s.SearchErrors))
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// labels formats the label set of a sample.
+func (m *Metrics) labels(extra string) string {
+	var pairs []string
+	for name, value := range m.Labels {
+		value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, value))
+	}
+	sort.Strings(pairs)
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func promFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return fmt.Sprint(f)
+}
+
+// Publish publishes the metrics with expvar under the given name.
+// Like expvar.Publish, it panics if the name is already taken.
+func (m *Metrics) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		s := m.Snapshot()
+		// JSON can't encode infinite or NaN scores.
+		return map[string]interface{}{
+			"generation":           s.Generation,
+			"best":                 historyFloat(s.Best),
+			"mean":                 historyFloat(s.Mean),
+			"worst":                historyFloat(s.Worst),
+			"populationSize":       s.PopulationSize,
+			"evaluations":          s.Evaluations,
+			"evaluationsPerSecond": s.EvaluationsPerSecond,
+			"workers":              s.Workers,
+			"utilization":          s.Utilization,
+			"latencyBuckets":       LatencyBuckets,
+			"latencyCounts":        s.LatencyCounts,
+			"latencySum":           s.LatencySum,
+			"latencyCount":         s.LatencyCount,
+			"fitnessErrors":        s.FitnessErrors,
+			"searchErrors":         s.SearchErrors,
+		}
+	}))
+}
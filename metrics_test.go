@@ -0,0 +1,142 @@
+package genetic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowIndividual takes a millisecond to evaluate.
+type slowIndividual struct {
+	fakeIndividual
+}
+
+func (si slowIndividual) Crossover(Individual) (Individual, error) {
+	return si, nil
+}
+
+func (si slowIndividual) Mutate(float64) (Individual, error) {
+	return si, nil
+}
+
+func (si slowIndividual) Fitness() (float64, error) {
+	time.Sleep(time.Millisecond)
+	return si.fakeIndividual.Fitness()
+}
+
+func Test_Metrics_Run_Collected(t *testing.T) {
+	m := NewMetrics()
+	ctrl, err := NewController(Params{
+		TargetFitness:   10,
+		SelectionMethod: Tournament(2),
+		Crossover:       1,
+		Parallelism:     2,
+		InitPop:         []slowIndividual{{fakeIndividual{fitness: 3}}, {fakeIndividual{fitness: 1}}, {fakeIndividual{fitness: 2}}, {fakeIndividual{fitness: 2}}},
+		Termination:     Termination{MaxGenerations: 2},
+		Metrics:         m,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ErrTerminated, ctrl.Run())
+
+	s := m.Snapshot()
+	last := ctrl.Stats()[2]
+	assert.Equal(t, 2, s.Generation)
+	assert.Equal(t, last.Best, s.Best)
+	assert.Equal(t, last.Mean, s.Mean)
+	assert.Equal(t, 4, s.PopulationSize)
+	assert.Equal(t, last.Evaluations, s.Evaluations)
+	assert.True(t, s.EvaluationsPerSecond > 0)
+	assert.Equal(t, 2, s.Workers)
+	assert.True(t, s.Utilization > 0 && s.Utilization <= 1, s.Utilization)
+	assert.Equal(t, uint64(last.Evaluations), s.LatencyCount)
+	assert.Equal(t, s.LatencyCount, s.LatencyCounts[len(s.LatencyCounts)-1])
+	assert.Equal(t, uint64(0), s.LatencyCounts[0])
+	assert.True(t, s.LatencySum >= 0.001*float64(s.LatencyCount))
+	assert.Equal(t, uint64(0), s.FitnessErrors+s.SearchErrors)
+}
+
+func Test_Metrics_FitnessError_Counted(t *testing.T) {
+	m := NewMetrics()
+	ctrl, err := NewController(Params{
+		SelectionMethod: Tournament(2),
+		InitPop:         []fakeIndividual{{err: errors.New("broken")}, {}},
+		Metrics:         m,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, ctrl.Run())
+
+	s := m.Snapshot()
+	assert.True(t, s.FitnessErrors >= 1)
+	assert.Equal(t, uint64(1), s.SearchErrors)
+}
+
+func Test_Metrics_ServeHTTP_PrometheusText(t *testing.T) {
+	m := NewMetrics()
+	m.Labels = map[string]string{"search": `a"b`, "app": "x"}
+	m.observe(Stats{Generation: 3, Best: 2.5, Evaluations: 40, Elapsed: 2 * time.Second})
+	m.evaluated(2*time.Millisecond, nil)
+	m.evaluated(20*time.Millisecond, errors.New("broken"))
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	for _, line := range []string{
+		"# TYPE genetic_generation gauge",
+		`genetic_generation{app="x",search="a\"b"} 3`,
+		`genetic_fitness_best{app="x",search="a\"b"} 2.5`,
+		`genetic_evaluations_total{app="x",search="a\"b"} 40`,
+		`genetic_evaluations_per_second{app="x",search="a\"b"} 20`,
+		"# TYPE genetic_fitness_latency_seconds histogram",
+		`genetic_fitness_latency_seconds_bucket{app="x",search="a\"b",le="0.001"} 0`,
+		`genetic_fitness_latency_seconds_bucket{app="x",search="a\"b",le="0.005"} 1`,
+		`genetic_fitness_latency_seconds_bucket{app="x",search="a\"b",le="0.05"} 2`,
+		`genetic_fitness_latency_seconds_bucket{app="x",search="a\"b",le="+Inf"} 2`,
+		`genetic_fitness_latency_seconds_count{app="x",search="a\"b"} 2`,
+		`genetic_errors_total{app="x",search="a\"b",kind="fitness"} 1`,
+		`genetic_errors_total{app="x",search="a\"b",kind="search"} 0`,
+	} {
+		assert.Contains(t, strings.Split(body, "\n"), line)
+	}
+}
+
+func Test_Metrics_ZeroValue_Usable(t *testing.T) {
+	m := &Metrics{}
+	out := &bytes.Buffer{}
+	assert.NoError(t, m.WritePrometheus(out))
+	assert.Contains(t, out.String(), `genetic_fitness_latency_seconds_bucket{le="+Inf"} 0`)
+
+	m.evaluated(2*time.Millisecond, nil)
+	snapshot := m.Snapshot()
+	assert.Len(t, snapshot.LatencyCounts, len(LatencyBuckets))
+	assert.Equal(t, uint64(1), snapshot.LatencyCounts[len(LatencyBuckets)-1])
+	assert.Equal(t, 1, snapshot.Workers)
+}
+
+func Test_Metrics_Publish_Expvar(t *testing.T) {
+	m := NewMetrics()
+	m.observe(Stats{Generation: 1, Best: math.Inf(-1)})
+	// Names can only be published once per process.
+	name := fmt.Sprint("genetic_test_metrics_", time.Now().UnixNano())
+	m.Publish(name)
+
+	var values map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get(name).String()), &values))
+	assert.Equal(t, 1.0, values["generation"])
+	assert.Equal(t, "-Inf", values["best"])
+	assert.Panics(t, func() { m.Publish(name) })
+}
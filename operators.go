@@ -0,0 +1,323 @@
+package genetic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// CrossoverOperator is a named way of recombining parents. Registering
+// several of them with Params.CrossoverOperators lets the controller
+// choose between them for every mating.
+type CrossoverOperator struct {
+	Name string
+
+	// Crossover mates the parents and returns at most n children.
+	Crossover func(parents []Individual, n int) ([]Individual, error)
+}
+
+// MutationOperator is a named way of mutating an individual. Registering
+// several of them with Params.MutationOperators lets the controller
+// choose between them for every child.
+type MutationOperator struct {
+	Name string
+
+	// Mutate returns a mutated copy of ind, mutating at the given rate.
+	Mutate func(ind Individual, rate float64) (Individual, error)
+}
+
+// OperatorSelector chooses which of several operators to apply next,
+// and learns from the credit earned by the offspring each one produced.
+// The credit is how much an offspring's fitness improved on the fittest
+// of its parents, or zero if it didn't.
+type OperatorSelector interface {
+	Select(rng *rand.Rand) int
+	Reward(operator int, credit float64)
+}
+
+// OperatorSelection creates an OperatorSelector for the given number of
+// operators. Every controller gets its own selectors, one for crossover
+// and one for mutation, so a Params value can be shared between searches.
+type OperatorSelection func(operators int) (OperatorSelector, error)
+
+// OperatorStats describes how an operator fared during one generation.
+type OperatorStats struct {
+	Name string
+
+	// Uses is how many offspring the operator produced.
+	Uses int
+
+	// Credit is the total credit those offspring earned.
+	Credit float64
+}
+
+// FixedWeights returns an OperatorSelection that chooses operators at
+// random in proportion to the given weights, ignoring their success.
+// With no weights, every operator is equally likely.
+func FixedWeights(weights ...float64) OperatorSelection {
+	return func(operators int) (OperatorSelector, error) {
+		w := weights
+		if len(w) == 0 {
+			w = make([]float64, operators)
+			for i := range w {
+				w[i] = 1
+			}
+		}
+		if len(w) != operators {
+			return nil, fmt.Errorf("got %d weights for %d operators", len(w), operators)
+		}
+		total := float64(0)
+		for _, weight := range w {
+			if weight < 0 {
+				return nil, errors.New("operator weights cannot be negative")
+			}
+			total += weight
+		}
+		if total == 0 {
+			return nil, errors.New("operator weights cannot all be zero")
+		}
+		return &fixedWeights{weights: w, total: total}, nil
+	}
+}
+
+type fixedWeights struct {
+	weights []float64
+	total   float64
+}
+
+func (f *fixedWeights) Select(rng *rand.Rand) int {
+	return spin(rng, f.weights, f.total)
+}
+
+func (f *fixedWeights) Reward(int, float64) {}
+
+// ProbabilityMatching returns an OperatorSelection that chooses each
+// operator with a probability proportional to its estimated quality,
+// an exponential moving average of its credit with learning rate alpha.
+// No operator's probability falls below pMin.
+func ProbabilityMatching(pMin, alpha float64) OperatorSelection {
+	return func(operators int) (OperatorSelector, error) {
+		sel, err := newAdaptiveSelector(operators, pMin, alpha)
+		if err != nil {
+			return nil, err
+		}
+		return &probabilityMatching{sel}, nil
+	}
+}
+
+type probabilityMatching struct {
+	*adaptiveSelector
+}
+
+func (p *probabilityMatching) Reward(operator int, credit float64) {
+	p.learn(operator, credit)
+	total := float64(0)
+	for _, q := range p.quality {
+		total += q
+	}
+	for i, q := range p.quality {
+		if total == 0 {
+			p.probs[i] = 1 / float64(len(p.probs))
+		} else {
+			p.probs[i] = p.pMin + (1-float64(len(p.probs))*p.pMin)*q/total
+		}
+	}
+}
+
+// AdaptivePursuit returns an OperatorSelection that estimates operator
+// quality like ProbabilityMatching, but pursues the best operator: its
+// probability moves towards 1-(K-1)*pMin at rate beta, while the others
+// move towards pMin. It adapts faster than probability matching when
+// one operator is clearly better.
+func AdaptivePursuit(pMin, alpha, beta float64) OperatorSelection {
+	return func(operators int) (OperatorSelector, error) {
+		if !isProb(beta) {
+			return nil, errors.New("adaptive pursuit rate must be between 0 and 1, inclusive")
+		}
+		sel, err := newAdaptiveSelector(operators, pMin, alpha)
+		if err != nil {
+			return nil, err
+		}
+		return &adaptivePursuit{sel, beta}, nil
+	}
+}
+
+type adaptivePursuit struct {
+	*adaptiveSelector
+	beta float64
+}
+
+func (a *adaptivePursuit) Reward(operator int, credit float64) {
+	a.learn(operator, credit)
+	best := 0
+	for i, q := range a.quality {
+		if q > a.quality[best] {
+			best = i
+		}
+	}
+	pMax := 1 - float64(len(a.probs)-1)*a.pMin
+	for i := range a.probs {
+		target := a.pMin
+		if i == best {
+			target = pMax
+		}
+		a.probs[i] += a.beta * (target - a.probs[i])
+	}
+}
+
+// adaptiveSelector holds the state shared by probability
+// matching and adaptive pursuit.
+type adaptiveSelector struct {
+	pMin    float64
+	alpha   float64
+	quality []float64
+	probs   []float64
+}
+
+func newAdaptiveSelector(operators int, pMin, alpha float64) (*adaptiveSelector, error) {
+	if operators < 1 {
+		return nil, errors.New("there must be at least one operator")
+	}
+	if pMin < 0 || pMin*float64(operators) > 1 {
+		return nil, fmt.Errorf("minimum probability must be between 0 and 1/%d", operators)
+	}
+	if !isProb(alpha) {
+		return nil, errors.New("learning rate must be between 0 and 1, inclusive")
+	}
+	out := &adaptiveSelector{
+		pMin:    pMin,
+		alpha:   alpha,
+		quality: make([]float64, operators),
+		probs:   make([]float64, operators),
+	}
+	for i := range out.probs {
+		out.probs[i] = 1 / float64(operators)
+	}
+	return out, nil
+}
+
+func (a *adaptiveSelector) Select(rng *rand.Rand) int {
+	return spin(rng, a.probs, 1)
+}
+
+func (a *adaptiveSelector) learn(operator int, credit float64) {
+	a.quality[operator] += a.alpha * (credit - a.quality[operator])
+}
+
+// UCB returns an OperatorSelection that treats operators as the arms of
+// a multi-armed bandit and chooses them with the UCB1 rule: the operator
+// with the highest mean credit plus an exploration bonus of
+// c*sqrt(2*ln(N)/n), where n is how often it has been chosen and N is
+// how often any operator has. Every operator is tried once first.
+func UCB(c float64) OperatorSelection {
+	return func(operators int) (OperatorSelector, error) {
+		if operators < 1 {
+			return nil, errors.New("there must be at least one operator")
+		}
+		if c < 0 {
+			return nil, errors.New("exploration coefficient cannot be negative")
+		}
+		return &ucb{
+			c:      c,
+			counts: make([]int, operators),
+			totals: make([]float64, operators),
+		}, nil
+	}
+}
+
+type ucb struct {
+	c      float64
+	plays  int
+	counts []int
+	totals []float64
+}
+
+func (u *ucb) Select(rng *rand.Rand) int {
+	best, bestValue := 0, -math.MaxFloat64
+	for _, i := range rng.Perm(len(u.counts)) {
+		if u.counts[i] == 0 {
+			return i
+		}
+		value := u.totals[i]/float64(u.counts[i]) +
+			u.c*math.Sqrt(2*math.Log(float64(u.plays))/float64(u.counts[i]))
+		if value > bestValue {
+			best, bestValue = i, value
+		}
+	}
+	return best
+}
+
+func (u *ucb) Reward(operator int, credit float64) {
+	u.plays++
+	u.counts[operator]++
+	u.totals[operator] += credit
+}
+
+// spin picks an index at random, in proportion to the given weights.
+func spin(rng *rand.Rand, weights []float64, total float64) int {
+	position := rng.Float64() * total
+	spinWheel := float64(0)
+	for i, w := range weights {
+		spinWheel += w
+		if spinWheel >= position && w > 0 {
+			return i
+		}
+	}
+	return len(weights) - 1
+}
+
+// operatorSet tracks the operators of one kind that are
+// registered with a controller, and how they are doing.
+type operatorSet struct {
+	names    []string
+	selector OperatorSelector
+	stats    []OperatorStats
+}
+
+func newOperatorSet(names []string, selection OperatorSelection) (*operatorSet, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	if selection == nil {
+		selection = FixedWeights()
+	}
+	selector, err := selection(len(names))
+	if err != nil {
+		return nil, err
+	}
+	out := &operatorSet{names: names, selector: selector}
+	out.reset()
+	return out, nil
+}
+
+// reset starts a new generation's statistics.
+func (o *operatorSet) reset() {
+	o.stats = make([]OperatorStats, len(o.names))
+	for i, name := range o.names {
+		o.stats[i].Name = name
+	}
+}
+
+// reward credits an operator for one of its offspring.
+func (o *operatorSet) reward(operator int, credit float64) {
+	if o == nil || operator < 0 {
+		return
+	}
+	if credit < 0 {
+		credit = 0
+	}
+	o.selector.Reward(operator, credit)
+	o.stats[operator].Uses++
+	o.stats[operator].Credit += credit
+}
+
+// report returns this generation's statistics and starts the next.
+func (o *operatorSet) report() []OperatorStats {
+	if o == nil {
+		return nil
+	}
+	out := o.stats
+	o.reset()
+	return out
+}
@@ -0,0 +1,121 @@
+package genetic
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func selectionCounts(t *testing.T, sel OperatorSelector, rounds int, credits ...float64) []int {
+	rng := rand.New(rand.NewSource(1))
+	counts := make([]int, len(credits))
+	for i := 0; i < rounds; i++ {
+		op := sel.Select(rng)
+		counts[op]++
+		sel.Reward(op, credits[op])
+	}
+	return counts
+}
+
+func Test_FixedWeights_ZeroWeight_NeverSelected(t *testing.T) {
+	sel, err := FixedWeights(1, 0, 1)(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := selectionCounts(t, sel, 1000, 0, 0, 0)
+
+	assert.Equal(t, 0, counts[1])
+}
+
+func Test_FixedWeights_WrongNumberOfWeights_ErrNotNil(t *testing.T) {
+	_, err := FixedWeights(1, 2)(3)
+
+	assert.NotNil(t, err)
+}
+
+func Test_AdaptiveSelection_BestOperatorFavoured(t *testing.T) {
+	for name, selection := range map[string]OperatorSelection{
+		"probability matching": ProbabilityMatching(0.05, 0.3),
+		"adaptive pursuit":     AdaptivePursuit(0.05, 0.3, 0.3),
+		"UCB":                  UCB(0.1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			sel, err := selection(3)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			counts := selectionCounts(t, sel, 1000, 0.1, 1, 0)
+
+			assert.True(t, counts[1] > counts[0], "counts: %v", counts)
+			assert.True(t, counts[1] > counts[2], "counts: %v", counts)
+		})
+	}
+}
+
+func Test_ProbabilityMatching_MinimumProbabilityTooLarge_ErrNotNil(t *testing.T) {
+	_, err := ProbabilityMatching(0.5, 0.3)(3)
+
+	assert.NotNil(t, err)
+}
+
+func Test_Run_RegisteredOperators_UsageReported(t *testing.T) {
+	improve := MutationOperator{
+		Name: "improve",
+		Mutate: func(ind Individual, rate float64) (Individual, error) {
+			fi := ind.(fakeIndividual)
+			fi.fitness++
+			return fi, nil
+		},
+	}
+	keep := MutationOperator{
+		Name: "keep",
+		Mutate: func(ind Individual, rate float64) (Individual, error) {
+			return ind, nil
+		},
+	}
+	ctrl, err := NewController(Params{
+		Elitism:           1,
+		TargetFitness:     3,
+		SelectionMethod:   Tournament(2),
+		InitPop:           make([]fakeIndividual, 4),
+		MutationOperators: []MutationOperator{improve, keep},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ctrl.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := ctrl.Stats()
+	uses := 0
+	for _, gen := range stats[1:] {
+		assert.Equal(t, "improve", gen.MutationOperators[0].Name)
+		assert.Equal(t, "keep", gen.MutationOperators[1].Name)
+		assert.Equal(t, float64(0), gen.MutationOperators[1].Credit)
+		uses += gen.MutationOperators[0].Uses + gen.MutationOperators[1].Uses
+	}
+	assert.Equal(t, 3*(len(stats)-1), uses)
+}
+
+func Test_NewController_ManyParentsWithCrossoverOperators_NoErr(t *testing.T) {
+	first := CrossoverOperator{
+		Name: "first",
+		Crossover: func(parents []Individual, n int) ([]Individual, error) {
+			return parents[:n], nil
+		},
+	}
+	_, err := NewController(Params{
+		ParentsPerMating:   3,
+		SelectionMethod:    Roulette(),
+		InitPop:            make([]fakeIndividual, 3),
+		CrossoverOperators: []CrossoverOperator{first},
+	})
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,260 @@
+package genetic
+
+// Option sets a parameter of a Controller built by New.
+type Option func(*Params)
+
+// New creates a Controller for the initial population initPop, which must
+// be a slice of Individuals, configured with the given options, which are
+// applied in order. initPop is nil when WithInitializer builds the
+// population instead. One of WithSelection, WithTournament,
+// WithMatingScheme and WithBreeding is required.
+//
+// Every parameter is checked, alone and against the others and the
+// population, before New returns, so an invalid configuration fails
+// here rather than part way through a run. The error is then a
+// *ValidationError, which wraps one of ErrOutOfRange,
+// ErrExceedsPopulation, ErrRequired, ErrConflict and ErrUnsupported,
+// unless the parameter failed with an error of its own.
+func New(initPop interface{}, opts ...Option) (*Controller, error) {
+	params := Params{}
+	for _, opt := range opts {
+		opt(&params)
+	}
+	params.InitPop = initPop
+	return NewController(params)
+}
+
+// WithParams starts from the given parameters, to which later options
+// are applied. Its InitPop is ignored in favour of New's.
+func WithParams(p Params) Option {
+	return func(params *Params) {
+		*params = p
+	}
+}
+
+// WithElitism sets Params.Elitism, how many of the fittest
+// individuals are copied unchanged into every generation.
+func WithElitism(n int) Option {
+	return func(params *Params) {
+		params.Elitism = n
+	}
+}
+
+// WithMutation sets Params.Mutation, the mutation rate.
+func WithMutation(rate float64) Option {
+	return func(params *Params) {
+		params.Mutation = rate
+	}
+}
+
+// WithAdaptiveMutation sets Params.AdaptiveMutation.
+func WithAdaptiveMutation() Option {
+	return func(params *Params) {
+		params.AdaptiveMutation = true
+	}
+}
+
+// WithMutationSchedule sets Params.MutationSchedule.
+func WithMutationSchedule(schedule RateSchedule) Option {
+	return func(params *Params) {
+		params.MutationSchedule = schedule
+	}
+}
+
+// WithCrossover sets Params.Crossover, the crossover rate.
+func WithCrossover(rate float64) Option {
+	return func(params *Params) {
+		params.Crossover = rate
+	}
+}
+
+// WithCrossoverSchedule sets Params.CrossoverSchedule.
+func WithCrossoverSchedule(schedule RateSchedule) Option {
+	return func(params *Params) {
+		params.CrossoverSchedule = schedule
+	}
+}
+
+// WithMating sets Params.ParentsPerMating and Params.OffspringPerMating.
+func WithMating(parents, offspring int) Option {
+	return func(params *Params) {
+		params.ParentsPerMating = parents
+		params.OffspringPerMating = offspring
+	}
+}
+
+// WithTargetFitness sets Params.TargetFitness, the fitness
+// at which the search stops.
+func WithTargetFitness(target float64) Option {
+	return func(params *Params) {
+		params.TargetFitness = target
+	}
+}
+
+// WithTermination sets Params.Termination.
+func WithTermination(t Termination) Option {
+	return func(params *Params) {
+		params.Termination = t
+	}
+}
+
+// WithObserver sets Params.Observer.
+func WithObserver(observer Observer) Option {
+	return func(params *Params) {
+		params.Observer = observer
+	}
+}
+
+// WithHistory sets Params.History.
+func WithHistory(history *HistoryWriter) Option {
+	return func(params *Params) {
+		params.History = history
+	}
+}
+
+// WithHallOfFame sets Params.HallOfFame and Params.HallOfFameDuplicate.
+func WithHallOfFame(size int, duplicate Duplicate) Option {
+	return func(params *Params) {
+		params.HallOfFame = size
+		params.HallOfFameDuplicate = duplicate
+	}
+}
+
+// WithHallOfFameElites sets Params.HallOfFameElites.
+func WithHallOfFameElites(n int) Option {
+	return func(params *Params) {
+		params.HallOfFameElites = n
+	}
+}
+
+// WithLineage sets Params.Lineage.
+func WithLineage() Option {
+	return func(params *Params) {
+		params.Lineage = true
+	}
+}
+
+// WithMetrics sets Params.Metrics.
+func WithMetrics(metrics *Metrics) Option {
+	return func(params *Params) {
+		params.Metrics = metrics
+	}
+}
+
+// WithParallelism sets Params.Parallelism, how many
+// goroutines evaluate fitness.
+func WithParallelism(n int) Option {
+	return func(params *Params) {
+		params.Parallelism = n
+	}
+}
+
+// WithSeed sets Params.Seed.
+func WithSeed(seed int64) Option {
+	return func(params *Params) {
+		params.Seed = seed
+	}
+}
+
+// WithSelection sets Params.SelectionMethod.
+func WithSelection(method SelectionMethod) Option {
+	return func(params *Params) {
+		params.SelectionMethod = method
+	}
+}
+
+// WithTournament selects partners with Tournament(n).
+func WithTournament(n int) Option {
+	return func(params *Params) {
+		params.SelectionMethod = Tournament(n)
+	}
+}
+
+// WithMatingScheme sets Params.MatingScheme.
+func WithMatingScheme(scheme MatingScheme) Option {
+	return func(params *Params) {
+		params.MatingScheme = scheme
+	}
+}
+
+// WithPopulationSize sets Params.PopulationSize.
+func WithPopulationSize(n int) Option {
+	return func(params *Params) {
+		params.PopulationSize = n
+	}
+}
+
+// WithOffspring sets Params.Offspring, how many
+// offspring are bred every generation.
+func WithOffspring(n int) Option {
+	return func(params *Params) {
+		params.Offspring = n
+	}
+}
+
+// WithSurvivors sets Params.Survivors.
+func WithSurvivors(survivors SurvivorSelection) Option {
+	return func(params *Params) {
+		params.Survivors = survivors
+	}
+}
+
+// WithMaxAge sets Params.MaxAge.
+func WithMaxAge(generations int) Option {
+	return func(params *Params) {
+		params.MaxAge = generations
+	}
+}
+
+// WithSizing sets Params.Sizing, along with the bounds
+// Params.MinPopulationSize and Params.MaxPopulationSize.
+func WithSizing(policy SizingPolicy, min, max int) Option {
+	return func(params *Params) {
+		params.Sizing = policy
+		params.MinPopulationSize = min
+		params.MaxPopulationSize = max
+	}
+}
+
+// WithGenerator sets Params.Generator.
+func WithGenerator(generator Generator) Option {
+	return func(params *Params) {
+		params.Generator = generator
+	}
+}
+
+// WithCrossoverOperators sets Params.CrossoverOperators.
+func WithCrossoverOperators(ops ...CrossoverOperator) Option {
+	return func(params *Params) {
+		params.CrossoverOperators = ops
+	}
+}
+
+// WithMutationOperators sets Params.MutationOperators.
+func WithMutationOperators(ops ...MutationOperator) Option {
+	return func(params *Params) {
+		params.MutationOperators = ops
+	}
+}
+
+// WithOperatorSelection sets Params.OperatorSelection.
+func WithOperatorSelection(selection OperatorSelection) Option {
+	return func(params *Params) {
+		params.OperatorSelection = selection
+	}
+}
+
+// WithBreeding sets Params.Breeding.
+func WithBreeding(breeding Breeding) Option {
+	return func(params *Params) {
+		params.Breeding = breeding
+	}
+}
+
+// WithInitializer sets Params.Initializer, which builds a population
+// of the size given to WithPopulationSize.
+func WithInitializer(init Initializer) Option {
+	return func(params *Params) {
+		params.Initializer = init
+	}
+}
@@ -0,0 +1,108 @@
+package genetic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_New_Options_ParamsSet(t *testing.T) {
+	ctrl, err := New(make([]fakeIndividual, 4),
+		WithTournament(3),
+		WithElitism(1),
+		WithMutation(0.2),
+		WithParallelism(2),
+		WithTermination(Termination{MaxGenerations: 5}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := ctrl.Params()
+	assert.Equal(t, 1, params.Elitism)
+	assert.Equal(t, 0.2, params.Mutation)
+	assert.Equal(t, 2, params.Parallelism)
+	assert.Equal(t, 5, params.Termination.MaxGenerations)
+	assert.Equal(t, 4, params.PopulationSize)
+	assert.Equal(t, 3, params.Offspring)
+}
+
+func Test_New_WithParams_LaterOptionsApplied(t *testing.T) {
+	ctrl, err := New(make([]fakeIndividual, 4),
+		WithParams(Params{Elitism: 2, Crossover: 0.5, SelectionMethod: Roulette()}),
+		WithElitism(1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, ctrl.Params().Elitism)
+	assert.Equal(t, 0.5, ctrl.Params().Crossover)
+}
+
+func Test_New_Invalid_ValidationError(t *testing.T) {
+	testTable := []struct {
+		label    string
+		opts     []Option
+		field    string
+		sentinel error
+	}{
+		{"elitism larger than population", []Option{WithTournament(2), WithElitism(5)}, "Elitism", ErrExceedsPopulation},
+		{"tournament larger than population", []Option{WithTournament(5)}, "SelectionMethod", ErrExceedsPopulation},
+		{"tournament of none", []Option{WithTournament(0)}, "SelectionMethod", ErrOutOfRange},
+		{"negative parallelism", []Option{WithTournament(2), WithParallelism(-1)}, "Parallelism", ErrOutOfRange},
+		{"mutation rate above 1", []Option{WithTournament(2), WithMutation(1.5)}, "Mutation", ErrOutOfRange},
+		{"no selection method", nil, "SelectionMethod", ErrRequired},
+		{"negative stagnation", []Option{WithTournament(2), WithTermination(Termination{Stagnation: -1})}, "Termination.Stagnation", ErrOutOfRange},
+		{"sizing bounds reversed", []Option{WithTournament(2), WithSizing(nil, 5, 3)}, "MaxPopulationSize", ErrConflict},
+		{"too few offspring", []Option{WithTournament(2), WithElitism(1), WithOffspring(1)}, "Offspring", ErrConflict},
+		{"too many parents", []Option{WithTournament(2), WithMating(3, 1)}, "ParentsPerMating", ErrUnsupported},
+		{"more elites than hall of fame", []Option{WithTournament(2), WithHallOfFame(1, nil), WithHallOfFameElites(2)}, "HallOfFameElites", ErrConflict},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.label, func(t *testing.T) {
+			_, err := New(make([]fakeIndividual, 3), testCase.opts...)
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("got %v; want a *ValidationError", err)
+			}
+			assert.Equal(t, testCase.field, verr.Field)
+			assert.True(t, errors.Is(err, testCase.sentinel), err.Error())
+		})
+	}
+}
+
+func Test_NewController_TournamentTooLarge_ValidationError(t *testing.T) {
+	testTable := []struct {
+		label    string
+		params   Params
+		field    string
+		sentinel error
+	}{
+		{"selection method", Params{SelectionMethod: Tournament(50)}, "SelectionMethod", ErrExceedsPopulation},
+		{"assortative mating", Params{MatingScheme: IncestPrevention(PositiveAssortative(Tournament(50), 2, nil), nil, 0, 1)}, "MatingScheme", ErrExceedsPopulation},
+		{"sizing below tournament", Params{SelectionMethod: Tournament(3), Sizing: Doubling(5), Generator: counter(3)}, "MinPopulationSize", ErrConflict},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.label, func(t *testing.T) {
+			testCase.params.InitPop = make([]fakeIndividual, 10)
+			_, err := NewController(testCase.params)
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("got %v; want a *ValidationError", err)
+			}
+			assert.Equal(t, testCase.field, verr.Field)
+			assert.True(t, errors.Is(err, testCase.sentinel), err.Error())
+		})
+	}
+
+	_, err := NewController(Params{SelectionMethod: Tournament(3), Sizing: Doubling(5), Generator: counter(3), MinPopulationSize: 3, InitPop: make([]fakeIndividual, 10)})
+	assert.NoError(t, err)
+}
+
+func Test_NewController_Invalid_ValidationError(t *testing.T) {
+	_, err := NewController(Params{Elitism: 5, SelectionMethod: Roulette(), InitPop: make([]fakeIndividual, 3)})
+	assert.True(t, errors.Is(err, ErrExceedsPopulation))
+	assert.EqualError(t, err, "invalid Elitism: cannot be larger than the population size, 3")
+}
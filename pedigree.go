@@ -0,0 +1,85 @@
+package genetic
+
+// pedigree hands out the IDs of individuals and, when depth is above
+// zero, remembers who the parents of recently born individuals were, so
+// that mating schemes can tell whether two members of the population are
+// related. Entries are kept for depth generations, which is fixed when
+// the controller is created from the needs of its mating scheme.
+type pedigree struct {
+	lastID  uint64
+	depth   int
+	parents map[uint64][]uint64
+	born    map[uint64]int
+}
+
+func newPedigree(depth int) *pedigree {
+	return &pedigree{
+		depth:   depth,
+		parents: make(map[uint64][]uint64),
+		born:    make(map[uint64]int),
+	}
+}
+
+// add records the birth of an individual with the given parents
+// and returns its ID. IDs start at one; zero means "unknown".
+func (p *pedigree) add(parents []uint64, generation int) uint64 {
+	p.lastID++
+	if p.depth > 0 {
+		p.parents[p.lastID] = parents
+		p.born[p.lastID] = generation
+	}
+	return p.lastID
+}
+
+// ancestors returns the set of IDs that are at most depth generations
+// back in the ancestry of id, including id itself.
+func (p *pedigree) ancestors(id uint64, depth int) map[uint64]bool {
+	out := make(map[uint64]bool)
+	if id == 0 {
+		return out
+	}
+	out[id] = true
+	curr := []uint64{id}
+	for i := 0; i < depth; i++ {
+		var next []uint64
+		for _, child := range curr {
+			for _, parent := range p.parents[child] {
+				if !out[parent] {
+					out[parent] = true
+					next = append(next, parent)
+				}
+			}
+		}
+		curr = next
+	}
+	return out
+}
+
+// related reports whether a and b share an ancestor within
+// depth generations. An individual counts as its own ancestor.
+func (p *pedigree) related(a, b uint64, depth int) bool {
+	if a == 0 || b == 0 {
+		return false
+	}
+	ancestorsA := p.ancestors(a, depth)
+	for id := range p.ancestors(b, depth) {
+		if ancestorsA[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// prune forgets individuals born too long before the given
+// generation to matter to any ancestry query.
+func (p *pedigree) prune(generation int) {
+	if p.depth == 0 {
+		return
+	}
+	for id, born := range p.born {
+		if born < generation-p.depth {
+			delete(p.parents, id)
+			delete(p.born, id)
+		}
+	}
+}
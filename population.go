@@ -5,19 +5,34 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"math/rand"
 	"reflect"
+	"sort"
 )
 
 // Population holds all the individuals in the
 // current population, along with their scores.
 type Population struct {
 	pop []indWithScore
+
+	rng    *rand.Rand
+	family *pedigree
+	logger *log.Logger
+
+	// chosen is the position of the member the last SelectionMethod
+	// built by ByPosition chose, and query is set on the populations
+	// NewController describes mating schemes with.
+	chosen int
+	query  *query
 }
 
 // NewPopulation constructs a Population with fitness
 // scores of zero.
 func NewPopulation(pop interface{}) (*Population, error) {
-	out := &Population{}
+	out := &Population{
+		rng:    rand.New(rand.NewSource(rand.Int63())),
+		family: newPedigree(0),
+	}
 	val := reflect.ValueOf(pop)
 	if val.Kind() != reflect.Slice {
 		return nil, fmt.Errorf("value has type %q; expecting slice", val.Kind())
@@ -30,7 +45,7 @@ func NewPopulation(pop interface{}) (*Population, error) {
 		if !ok {
 			return nil, fmt.Errorf("%s does not satisfy interface Individual", val.Type())
 		}
-		out.pop = append(out.pop, indWithScore{ind, 0})
+		out.pop = append(out.pop, indWithScore{Individual: ind})
 	}
 	if len(out.pop) == 0 {
 		return nil, errors.New("cannot pass in empty slice")
@@ -38,12 +53,21 @@ func NewPopulation(pop interface{}) (*Population, error) {
 	return out, nil
 }
 
+// logf logs with the population's logger, or the standard one.
+func (p *Population) logf(format string, args ...interface{}) {
+	if p.logger == nil {
+		log.Printf(format, args...)
+		return
+	}
+	p.logger.Printf(format, args...)
+}
+
 // TargetMet returns true if any individual in the population
 // has met or exceeded the fitness target.
 func (p *Population) TargetMet(t float64) bool {
 	for _, ind := range p.pop {
 		if ind.score >= t {
-			log.Printf("Individual with score %.2f has exceeded target (%.2f)", ind.score, t)
+			p.logf("Individual with score %.2f has exceeded target (%.2f)", ind.score, t)
 			return true
 		}
 	}
@@ -106,32 +130,112 @@ func (p *Population) AvgFitness() (float64, error) {
 	return sum / float64(len(p.pop)), nil
 }
 
-func (p *Population) scoreAndSort(workers int) error {
-	newPop, err := calculateFitnessConcurrently(p.pop, workers)
-	if err != nil {
-		return err
+// Len returns the number of individuals in the population.
+func (p *Population) Len() int {
+	return len(p.pop)
+}
+
+// At returns the individual at position i, along with the score it was
+// given when the generation was evaluated, without calling Fitness
+// again. Scores are zero until the population has been evaluated. At
+// panics if i is out of range.
+func (p *Population) At(i int) ScoredIndividual {
+	return ScoredIndividual{p.pop[i].Individual, p.pop[i].score}
+}
+
+// Scores returns the score of every individual, in population order.
+func (p *Population) Scores() []float64 {
+	out := make([]float64, len(p.pop))
+	for i, ind := range p.pop {
+		out[i] = ind.score
 	}
-	p.pop = newPop
-	return nil
+	return out
 }
 
-func (p *Population) getAdaptiveMutationRate(ind Individual) (float64, error) {
-	currScore, err := ind.Fitness()
-	if err != nil {
-		return 0, err
+// Sorted returns every individual with its score, fittest first.
+// Individuals with equal scores keep their population order.
+func (p *Population) Sorted() []ScoredIndividual {
+	sorted := append([]indWithScore{}, p.pop...)
+	sort.Stable(sort.Reverse(pairs(sorted)))
+	out := make([]ScoredIndividual, len(sorted))
+	for i, ind := range sorted {
+		out[i] = ScoredIndividual{ind.Individual, ind.score}
 	}
-	avgFitness, err := p.AvgFitness()
-	if err != nil {
-		return 0, err
+	return out
+}
+
+// Top returns the k fittest individuals, fittest first,
+// or the whole population if it's smaller than k.
+func (p *Population) Top(k int) []ScoredIndividual {
+	sorted := p.Sorted()
+	if k < 0 {
+		k = 0
 	}
-	if currScore > avgFitness {
-		fittestScore, err := p.FittestScore()
-		if err != nil {
-			return 0, err
-		}
-		delta1 := fittestScore - currScore
-		delta2 := fittestScore - avgFitness
-		return delta1 / delta2, nil
+	if k < len(sorted) {
+		sorted = sorted[:k]
 	}
-	return 1, nil
+	return sorted
+}
+
+// Quantile returns the q-quantile of the scores, interpolating linearly
+// between the two nearest scores: 0 is the worst score, 0.5 the median
+// and 1 the best.
+func (p *Population) Quantile(q float64) (float64, error) {
+	if !isProb(q) {
+		return 0, errors.New("quantile must be between 0 and 1, inclusive")
+	}
+	if len(p.pop) == 0 {
+		return 0, errors.New("population is empty")
+	}
+	scores := p.Scores()
+	sort.Float64s(scores)
+	pos := q * float64(len(scores)-1)
+	lower := int(math.Floor(pos))
+	if lower == len(scores)-1 {
+		return scores[lower], nil
+	}
+	return scores[lower] + (pos-float64(lower))*(scores[lower+1]-scores[lower]), nil
+}
+
+// Sample returns the positions of k distinct individuals, drawn at
+// random with the population's random source, or of every individual,
+// shuffled, if the population is smaller than k.
+func (p *Population) Sample(k int) []int {
+	perm := p.rng.Perm(len(p.pop))
+	if k < 0 {
+		k = 0
+	}
+	if k < len(perm) {
+		perm = perm[:k]
+	}
+	return perm
+}
+
+// Rand returns the population's random source, which is the
+// controller's, seeded by Params.Seed if it's set. Selection methods
+// and other code called by the controller should draw from it rather
+// than the global source, so that seeded runs are reproducible. It
+// isn't safe for concurrent use.
+func (p *Population) Rand() *rand.Rand {
+	return p.rng
+}
+
+// next returns a new generation that shares this population's
+// random source and pedigree.
+func (p *Population) next(members []indWithScore) *Population {
+	return &Population{
+		pop:    members,
+		rng:    p.rng,
+		family: p.family,
+		logger: p.logger,
+	}
+}
+
+func (p *Population) scoreAndSort(workers int) error {
+	newPop, err := calculateFitnessConcurrently(p.pop, workers, nil)
+	if err != nil {
+		return err
+	}
+	p.pop = newPop
+	return nil
 }
@@ -155,3 +155,78 @@ func Test_scoreAndSort_PopulationScoredAndSorted(t *testing.T) {
 	assert.Equal(t, id4, pop.pop[1])
 	assert.Equal(t, indWithScore{Individual: fakeIndividual{}}, pop.pop[2])
 }
+
+func Test_At_CachedScoreReturned(t *testing.T) {
+	pop := scoredPopulation(t, 2, 5, 1)
+	pop.pop[1].score = 6
+
+	assert.Equal(t, 3, pop.Len())
+	assert.Equal(t, ScoredIndividual{fakeIndividual{id: 1, fitness: 5}, 6}, pop.At(1))
+	assert.Equal(t, []float64{2, 6, 1}, pop.Scores())
+}
+
+func Test_Sorted_FittestFirstTiesInOrder(t *testing.T) {
+	pop := scoredPopulation(t, 2, 5, 1, 5)
+
+	assert.Equal(t, []ScoredIndividual{
+		{fakeIndividual{id: 1, fitness: 5}, 5},
+		{fakeIndividual{id: 3, fitness: 5}, 5},
+		{fakeIndividual{id: 0, fitness: 2}, 2},
+		{fakeIndividual{id: 2, fitness: 1}, 1},
+	}, pop.Sorted())
+	assert.Equal(t, []float64{2, 5, 1, 5}, pop.Scores())
+	assert.Equal(t, pop.Sorted()[:2], pop.Top(2))
+	assert.Len(t, pop.Top(10), 4)
+	assert.Empty(t, pop.Top(-1))
+}
+
+func Test_Quantile_Interpolated(t *testing.T) {
+	pop := scoredPopulation(t, 4, 1, 3, 2)
+
+	for q, want := range map[float64]float64{0: 1, 0.5: 2.5, 1: 4, 0.25: 1.75} {
+		got, err := pop.Quantile(q)
+		assert.NoError(t, err)
+		assert.InDelta(t, want, got, 1e-9, q)
+	}
+	_, err := pop.Quantile(1.5)
+	assert.Error(t, err)
+}
+
+func Test_Sample_DistinctPositions(t *testing.T) {
+	pop := scoredPopulation(t, 1, 2, 3, 4, 5)
+
+	seen := map[int]bool{}
+	for _, i := range pop.Sample(3) {
+		assert.True(t, i >= 0 && i < 5)
+		seen[i] = true
+	}
+	assert.Len(t, seen, 3)
+	assert.Len(t, pop.Sample(10), 5)
+}
+
+func Test_Rand_ControllerSeedUsed(t *testing.T) {
+	var draws []int
+	for i := 0; i < 2; i++ {
+		var picked []int
+		best := ByPosition(func(pop *Population) (int, error) {
+			picked = append(picked, pop.Sample(1)[0], pop.Rand().Intn(100))
+			return 0, nil
+		})
+		ctrl, err := New(make([]fakeIndividual, 5),
+			WithSelection(best),
+			WithSeed(7),
+			WithTargetFitness(1),
+			WithTermination(Termination{MaxGenerations: 2}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, ErrTerminated, ctrl.Run())
+		if draws == nil {
+			draws = picked
+			continue
+		}
+		assert.Equal(t, draws, picked)
+	}
+	assert.NotEmpty(t, draws)
+}
@@ -0,0 +1,156 @@
+package genetic
+
+import "math"
+
+// RateContext is what a RateSchedule knows when it chooses a rate.
+type RateContext struct {
+	// Generation is the number of the generation being bred.
+	Generation int
+
+	// Base is the static rate from Params, i.e. Mutation or Crossover.
+	Base float64
+
+	// Previous is the average rate the schedule chose during the
+	// last generation, or Base during the first.
+	Previous float64
+
+	// Stats describes the current population.
+	Stats Stats
+
+	// Score is the score of the fittest parent involved. For mutation,
+	// that's the parent of the individual about to be mutated, which
+	// saves evaluating it before it has finished changing.
+	Score float64
+
+	// SuccessRate is the fraction of the last generation's offspring
+	// that were fitter than their fittest parent.
+	SuccessRate float64
+}
+
+// RateSchedule chooses a mutation or crossover rate. The controller
+// calls it for every mating or mutation, and clamps the result to
+// between 0 and 1. A NaN result keeps the previous generation's rate.
+type RateSchedule func(RateContext) float64
+
+// SrinivasPatnaik returns the adaptive RateSchedule of Srinivas and
+// Patnaik (1994). Individuals that are fitter than average get a rate
+// of high*(fmax-f)/(fmax-favg), which protects the best of them, while
+// the others get the low rate. A converged population, where the best
+// score equals the average, gets the high rate to restore diversity.
+// The original paper suggests high=low=1 for crossover and
+// high=low=0.5 for mutation.
+func SrinivasPatnaik(high, low float64) RateSchedule {
+	return func(ctx RateContext) float64 {
+		fmax, favg := ctx.Stats.Best, ctx.Stats.Mean
+		if ctx.Score < favg {
+			return low
+		}
+		if fmax <= favg {
+			return high
+		}
+		return high * (fmax - ctx.Score) / (fmax - favg)
+	}
+}
+
+// LinearAnnealing returns a RateSchedule that moves the rate in a
+// straight line from the given value to the other over the given
+// number of generations, and keeps it there afterwards.
+func LinearAnnealing(from, to float64, generations int) RateSchedule {
+	return func(ctx RateContext) float64 {
+		if ctx.Generation >= generations {
+			return to
+		}
+		return from + (to-from)*float64(ctx.Generation)/float64(generations)
+	}
+}
+
+// ExponentialAnnealing returns a RateSchedule that moves the rate
+// geometrically from the given value to the other over the given
+// number of generations, and keeps it there afterwards. There's no
+// geometric path to or from zero, so unless both rates are positive it
+// anneals linearly, like LinearAnnealing.
+func ExponentialAnnealing(from, to float64, generations int) RateSchedule {
+	if from <= 0 || to <= 0 {
+		return LinearAnnealing(from, to, generations)
+	}
+	return func(ctx RateContext) float64 {
+		if ctx.Generation >= generations {
+			return to
+		}
+		return from * math.Pow(to/from, float64(ctx.Generation)/float64(generations))
+	}
+}
+
+// OneFifthRule returns a RateSchedule following Rechenberg's 1/5th
+// success rule: when more than a fifth of the last generation's
+// offspring beat their parents, the rate is divided by factor, and when
+// fewer did, it is multiplied by it. The factor should be between 0 and
+// 1; 0.82 is the usual choice. The first generation uses Base.
+func OneFifthRule(factor float64) RateSchedule {
+	return func(ctx RateContext) float64 {
+		if ctx.Generation <= 1 {
+			return ctx.Base
+		}
+		switch {
+		case ctx.SuccessRate > 0.2:
+			return ctx.Previous / factor
+		case ctx.SuccessRate < 0.2:
+			return ctx.Previous * factor
+		}
+		return ctx.Previous
+	}
+}
+
+// DiversityControl returns a RateSchedule driven by the population's
+// diversity: the rate is max when Stats.Diversity is zero, falls
+// linearly as diversity rises, and reaches min at the target diversity.
+func DiversityControl(min, max, target float64) RateSchedule {
+	return func(ctx RateContext) float64 {
+		if target <= 0 || ctx.Stats.Diversity >= target {
+			return min
+		}
+		return max - (max-min)*ctx.Stats.Diversity/target
+	}
+}
+
+// rateTracker applies a RateSchedule and
+// averages the rates it chose each generation.
+type rateTracker struct {
+	schedule RateSchedule
+	base     float64
+	previous float64
+	sum      float64
+	count    int
+}
+
+func newRateTracker(schedule RateSchedule, base float64) *rateTracker {
+	return &rateTracker{schedule: schedule, base: base, previous: base}
+}
+
+// rate chooses a rate for the given context,
+// filling in the fields the tracker knows about.
+func (r *rateTracker) rate(ctx RateContext) float64 {
+	ctx.Base = r.base
+	ctx.Previous = r.previous
+	rate := r.base
+	if r.schedule != nil {
+		rate = r.schedule(ctx)
+		if math.IsNaN(rate) {
+			rate = r.previous
+		}
+		rate = math.Max(0, math.Min(1, rate))
+	}
+	r.sum += rate
+	r.count++
+	return rate
+}
+
+// report returns the average rate chosen since the last
+// report, which later contexts see as Previous.
+func (r *rateTracker) report() float64 {
+	if r.count > 0 {
+		r.previous = r.sum / float64(r.count)
+	}
+	r.sum, r.count = 0, 0
+	return r.previous
+}
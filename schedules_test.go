@@ -0,0 +1,80 @@
+package genetic
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SrinivasPatnaik_AboveAverage_RateScaledByDistanceFromBest(t *testing.T) {
+	schedule := SrinivasPatnaik(0.5, 0.4)
+	stats := Stats{Best: 10, Mean: 6}
+
+	assert.Equal(t, 0.25, schedule(RateContext{Stats: stats, Score: 8}))
+	assert.Equal(t, float64(0), schedule(RateContext{Stats: stats, Score: 10}))
+	assert.Equal(t, 0.4, schedule(RateContext{Stats: stats, Score: 2}))
+}
+
+func Test_SrinivasPatnaik_ConvergedPopulation_HighRate(t *testing.T) {
+	schedule := SrinivasPatnaik(0.5, 0.4)
+
+	assert.Equal(t, 0.5, schedule(RateContext{Stats: Stats{Best: 3, Mean: 3}, Score: 3}))
+}
+
+func Test_LinearAnnealing_RateInterpolated(t *testing.T) {
+	schedule := LinearAnnealing(1, 0, 10)
+
+	assert.Equal(t, float64(1), schedule(RateContext{Generation: 0}))
+	assert.Equal(t, 0.5, schedule(RateContext{Generation: 5}))
+	assert.Equal(t, float64(0), schedule(RateContext{Generation: 20}))
+}
+
+func Test_ExponentialAnnealing_RateInterpolatedGeometrically(t *testing.T) {
+	schedule := ExponentialAnnealing(1, 0.01, 10)
+
+	assert.InDelta(t, 0.1, schedule(RateContext{Generation: 5}), 1e-9)
+	assert.Equal(t, 0.01, schedule(RateContext{Generation: 10}))
+}
+
+func Test_ExponentialAnnealing_FromZero_Linear(t *testing.T) {
+	schedule := ExponentialAnnealing(0, 0.5, 10)
+
+	assert.Equal(t, float64(0), schedule(RateContext{Generation: 0}))
+	assert.Equal(t, 0.25, schedule(RateContext{Generation: 5}))
+	assert.Equal(t, 0.5, schedule(RateContext{Generation: 10}))
+}
+
+func Test_OneFifthRule_RateFollowsSuccess(t *testing.T) {
+	schedule := OneFifthRule(0.5)
+
+	assert.Equal(t, 0.3, schedule(RateContext{Generation: 1, Base: 0.3, Previous: 0.3}))
+	assert.Equal(t, 0.4, schedule(RateContext{Generation: 2, Previous: 0.2, SuccessRate: 0.5}))
+	assert.Equal(t, 0.1, schedule(RateContext{Generation: 2, Previous: 0.2, SuccessRate: 0.1}))
+}
+
+func Test_DiversityControl_LowDiversity_HighRate(t *testing.T) {
+	schedule := DiversityControl(0.1, 0.9, 2)
+
+	assert.Equal(t, 0.9, schedule(RateContext{Stats: Stats{Diversity: 0}}))
+	assert.InDelta(t, 0.5, schedule(RateContext{Stats: Stats{Diversity: 1}}), 1e-9)
+	assert.Equal(t, 0.1, schedule(RateContext{Stats: Stats{Diversity: 3}}))
+}
+
+func Test_rateTracker_RatesClampedAndAveraged(t *testing.T) {
+	scores := []float64{2, -1}
+	tracker := newRateTracker(func(ctx RateContext) float64 { return ctx.Score }, 0.3)
+
+	assert.Equal(t, 0.3, tracker.report())
+	for _, score := range scores {
+		tracker.rate(RateContext{Score: score})
+	}
+	assert.Equal(t, 0.5, tracker.report())
+}
+
+func Test_rateTracker_NaN_PreviousRateKept(t *testing.T) {
+	tracker := newRateTracker(func(RateContext) float64 { return math.NaN() }, 0.3)
+
+	assert.Equal(t, 0.3, tracker.rate(RateContext{}))
+	assert.Equal(t, 0.3, tracker.report())
+}
@@ -2,54 +2,100 @@ package genetic
 
 import (
 	"errors"
+	"fmt"
 	"math"
-	"math/rand"
 )
 
 // SelectionMethod is used to choose a second Individual
-// during crossover.
+// during crossover. Methods outside this package can read the
+// population's scores with Len, At, Sorted and Top, and draw
+// from its random source with Sample and Rand.
+//
+// The controller looks the chosen Individual up in the population, to
+// know whose offspring it breeds, which only works for genomes that can
+// be compared with ==. ByPosition builds SelectionMethods that tell the
+// controller the position of their choice instead, as the ones in this
+// package do. NewController calls the SelectionMethod, through the
+// MatingScheme, once on the initial population to learn about the
+// methods of this package it's made of, and ignores what it chooses.
 type SelectionMethod func(*Population) (Individual, error)
 
+// ByPosition returns a SelectionMethod that chooses the member at the
+// position choose returns, within the population sorted from fittest to
+// least fit.
+func ByPosition(choose func(pop *Population) (int, error)) SelectionMethod {
+	return byPosition(0, choose)
+}
+
+// byPosition is ByPosition for methods that need
+// a population of at least min members.
+func byPosition(min int, choose func(pop *Population) (int, error)) SelectionMethod {
+	return func(pop *Population) (Individual, error) {
+		if pop.query != nil {
+			pop.query.need(min)
+			return nil, nil
+		}
+		i, err := choose(pop)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= len(pop.pop) {
+			return nil, fmt.Errorf("selection method chose position %d of a population of %d", i, len(pop.pop))
+		}
+		pop.chosen = i
+		return pop.pop[i].Individual, nil
+	}
+}
+
 // Roulette returns a SelectionMethod that picks a partner for an individual
 // at random, weighting the likelihood of picking a particular partner
 // with that partner's fitness.
 func Roulette() SelectionMethod {
-	return func(pop *Population) (Individual, error) {
-		totalFitness, err := pop.TotalFitness()
-		if err != nil {
-			return nil, err
+	return ByPosition(func(pop *Population) (int, error) {
+		totalFitness := float64(0)
+		for _, score := range pop.Scores() {
+			totalFitness += score
 		}
-		position := rand.Float64() * totalFitness
+		position := pop.rng.Float64() * totalFitness
 		spinWheel := float64(0)
-		for _, curr := range pop.pop {
-			fitness, err := curr.Fitness()
-			if err != nil {
-				return nil, err
-			}
-			spinWheel += fitness
+		for i := 0; i < pop.Len(); i++ {
+			spinWheel += pop.At(i).Score
 			if spinWheel >= position {
-				return curr.Individual, nil
+				return i, nil
 			}
 		}
-		return pop.pop[len(pop.pop)-1].Individual, nil
-	}
+		return pop.Len() - 1, nil
+	})
 }
 
 // Tournament returns the tournament SelectionMethod, which uses
-// tournaments of size n to select a partner for crossover.
+// tournaments of size n to select a partner for crossover. The
+// contestants are drawn at random, and the fittest of them wins.
 func Tournament(n int) SelectionMethod {
-	return func(pop *Population) (Individual, error) {
-		if len(pop.pop) < n {
-			return nil, errors.New("tournament size is larger than population")
+	if n < 1 {
+		return func(pop *Population) (Individual, error) {
+			if pop.query != nil {
+				pop.query.fail(invalid("SelectionMethod", ErrOutOfRange, "tournament size must be at least 1"))
+				return nil, nil
+			}
+			return nil, errors.New("tournament has no contestants")
+		}
+	}
+	return byPosition(n, func(pop *Population) (int, error) {
+		if pop.Len() < n {
+			return 0, errors.New("tournament size is larger than population")
 		}
-		var winner Individual
+		winner := -1
 		max := -math.MaxFloat64
-		for i := 0; i < n; i++ {
-			if pop.pop[i].score > max {
-				winner = pop.pop[i].Individual
-				max = pop.pop[i].score
+		for _, i := range pop.Sample(n) {
+			if score := pop.At(i).Score; score > max {
+				winner = i
+				max = score
 			}
 		}
+		if winner < 0 {
+			return 0, errors.New("tournament has no contestants")
+		}
 		return winner, nil
-	}
+	})
 }
@@ -0,0 +1,140 @@
+package genetic
+
+import (
+	"math"
+	"sort"
+)
+
+// Generator creates a new, usually random, Individual.
+type Generator func() (Individual, error)
+
+// SizingPolicy chooses the population size for the next generation,
+// given the statistics of every generation so far. The controller keeps
+// the size between Params.MinPopulationSize and Params.MaxPopulationSize,
+// fills any extra places with Params.Generator, and drops the least fit
+// individuals when the population shrinks.
+type SizingPolicy func(history []Stats) int
+
+// AdaptiveSizing returns a SizingPolicy that grows the population by the
+// growth factor when the search stagnates, meaning the best score hasn't
+// improved for window generations, or when Diversity falls below
+// minDiversity. It shrinks the population by the shrink factor while the
+// best score improves in every one of the last window generations. After
+// each change in size, the population gets window generations to settle.
+func AdaptiveSizing(window int, minDiversity, growth, shrink float64) SizingPolicy {
+	return func(history []Stats) int {
+		curr := history[len(history)-1]
+		if window < 1 || settling(history, window) {
+			return curr.PopulationSize
+		}
+		if curr.Diversity < minDiversity || stagnant(history, window) {
+			return int(math.Ceil(float64(curr.PopulationSize) * growth))
+		}
+		if improving(history, window) {
+			return int(math.Floor(float64(curr.PopulationSize) * shrink))
+		}
+		return curr.PopulationSize
+	}
+}
+
+// Doubling returns the SizingPolicy of the parameter-less genetic
+// algorithm: whenever the population has converged, because the best
+// score hasn't improved for window generations, the population doubles
+// and the new half is filled with fresh individuals from the Generator.
+func Doubling(window int) SizingPolicy {
+	return func(history []Stats) int {
+		curr := history[len(history)-1]
+		if window < 1 || settling(history, window) || !stagnant(history, window) {
+			return curr.PopulationSize
+		}
+		return 2 * curr.PopulationSize
+	}
+}
+
+// settling reports whether the population size changed
+// during the last window generations.
+func settling(history []Stats, window int) bool {
+	if len(history) <= window {
+		return true
+	}
+	size := history[len(history)-1].PopulationSize
+	for _, stats := range history[len(history)-1-window:] {
+		if stats.PopulationSize != size {
+			return true
+		}
+	}
+	return false
+}
+
+// stagnant reports whether the best score has failed to
+// improve during the last window generations.
+func stagnant(history []Stats, window int) bool {
+	if len(history) <= window {
+		return false
+	}
+	return history[len(history)-1].Best <= history[len(history)-1-window].Best
+}
+
+// improving reports whether the best score improved in
+// each of the last window generations.
+func improving(history []Stats, window int) bool {
+	if len(history) <= window {
+		return false
+	}
+	for i := len(history) - window; i < len(history); i++ {
+		if history[i].Best <= history[i-1].Best {
+			return false
+		}
+	}
+	return true
+}
+
+// resize applies Params.Sizing to the survivors of a generation, which
+// must be sorted from fittest to least fit. New members are scored and
+// sorted into place; when shrinking, the least fit are dropped.
+func (c *Controller) resize(survivors []indWithScore) ([]indWithScore, error) {
+	history := append(c.Stats(), c.population.next(survivors).stats(c.generation, c.evaluations))
+	size := c.params.Sizing(history)
+	if size < c.params.MinPopulationSize {
+		size = c.params.MinPopulationSize
+	}
+	if c.params.MaxPopulationSize > 0 && size > c.params.MaxPopulationSize {
+		size = c.params.MaxPopulationSize
+	}
+	if size == len(survivors) {
+		return survivors, nil
+	}
+
+	// Keep the number of offspring in proportion to the population
+	scaled := int(math.Ceil(float64(c.params.Offspring) * float64(size) / float64(c.params.PopulationSize)))
+	if c.params.Survivors == MuCommaLambda && scaled < size-c.params.Elitism {
+		scaled = size - c.params.Elitism
+	}
+	if scaled < 1 {
+		scaled = 1
+	}
+	c.mu.Lock()
+	c.params.Offspring = scaled
+	c.params.PopulationSize = size
+	c.mu.Unlock()
+
+	if size < len(survivors) {
+		return survivors[:size], nil
+	}
+	var newcomers []indWithScore
+	for len(survivors)+len(newcomers) < size {
+		ind, err := c.params.Generator()
+		if err != nil {
+			return nil, err
+		}
+		newcomers = append(newcomers, c.birth(ind, nil, "generator"))
+	}
+	newcomers, err := calculateFitnessConcurrently(newcomers, c.params.Parallelism, c.params.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	c.evaluations += len(newcomers)
+	survivors = append(survivors, newcomers...)
+	sort.Stable(sort.Reverse(pairs(survivors)))
+	return survivors, nil
+}
@@ -0,0 +1,103 @@
+package genetic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sizingHistory(size int, best ...float64) []Stats {
+	var out []Stats
+	for i, b := range best {
+		out = append(out, Stats{Generation: i, Best: b, PopulationSize: size, Diversity: 1})
+	}
+	return out
+}
+
+func Test_AdaptiveSizing_Stagnation_PopulationGrows(t *testing.T) {
+	policy := AdaptiveSizing(2, 0, 1.5, 0.5)
+
+	assert.Equal(t, 15, policy(sizingHistory(10, 1, 2, 2, 2)))
+}
+
+func Test_AdaptiveSizing_DiversityCollapse_PopulationGrows(t *testing.T) {
+	policy := AdaptiveSizing(2, 0.5, 1.5, 0.5)
+	history := sizingHistory(10, 1, 2, 3, 3)
+	history[len(history)-1].Diversity = 0.1
+
+	assert.Equal(t, 15, policy(history))
+}
+
+func Test_AdaptiveSizing_QuickImprovement_PopulationShrinks(t *testing.T) {
+	policy := AdaptiveSizing(2, 0, 1.5, 0.5)
+
+	assert.Equal(t, 5, policy(sizingHistory(10, 1, 2, 3, 4)))
+}
+
+func Test_AdaptiveSizing_RecentlyResized_SizeUnchanged(t *testing.T) {
+	policy := AdaptiveSizing(2, 0, 1.5, 0.5)
+	history := sizingHistory(10, 1, 2, 2, 2)
+	history[1].PopulationSize = 5
+
+	assert.Equal(t, 10, policy(history))
+}
+
+func Test_Doubling_Converged_PopulationDoubles(t *testing.T) {
+	assert.Equal(t, 20, Doubling(2)(sizingHistory(10, 1, 1, 1)))
+	assert.Equal(t, 10, Doubling(2)(sizingHistory(10, 1, 2, 3)))
+}
+
+func Test_resize_Growth_NewcomersGeneratedAndSorted(t *testing.T) {
+	ctrl := &Controller{
+		params: Params{
+			PopulationSize:    2,
+			Offspring:         2,
+			MinPopulationSize: 2,
+			MaxPopulationSize: 3,
+			Sizing:            func([]Stats) int { return 10 },
+			Generator: func() (Individual, error) {
+				return fakeIndividual{fitness: 5}, nil
+			},
+		},
+		population: scoredPopulation(t, 9, 1),
+	}
+
+	survivors, err := ctrl.resize(ctrl.population.pop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []float64{9, 5, 1}, survivorScores(survivors))
+	assert.Equal(t, 3, ctrl.params.PopulationSize)
+	assert.Equal(t, 3, ctrl.params.Offspring)
+}
+
+func Test_Params_WhileResizing_SizesConsistent(t *testing.T) {
+	ctrl, err := NewController(Params{
+		SelectionMethod:   Tournament(2),
+		TargetFitness:     10,
+		InitPop:           []fakeIndividual{{fitness: 1}, {fitness: 1}},
+		Sizing:            Doubling(1),
+		MaxPopulationSize: 16,
+		Generator: func() (Individual, error) {
+			return fakeIndividual{fitness: 1}, nil
+		},
+		Termination: Termination{MaxGenerations: 10},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctrl.Start(context.Background())
+	for done := false; !done; {
+		select {
+		case <-ctrl.Done():
+			done = true
+		default:
+			params := ctrl.Params()
+			assert.True(t, params.PopulationSize >= 2 && params.PopulationSize <= 16)
+		}
+	}
+	assert.Equal(t, ErrTerminated, ctrl.Wait())
+	assert.Equal(t, 16, ctrl.Params().PopulationSize)
+}
@@ -0,0 +1,69 @@
+package genetic
+
+import (
+	"math"
+	"time"
+)
+
+// Stats summarizes a single generation of the search.
+type Stats struct {
+	// Generation is zero for the initial population.
+	Generation int
+
+	// Best, Mean and Worst are fitness scores of the population.
+	Best  float64
+	Mean  float64
+	Worst float64
+
+	// Diversity is the standard deviation of the population's fitness
+	// scores. It approaches zero as the population converges.
+	Diversity float64
+
+	// PopulationSize is how many individuals the generation had.
+	PopulationSize int
+
+	// Evaluations is how many fitness evaluations the search has
+	// performed so far, including this generation's.
+	Evaluations int
+
+	// Elapsed is how long the search has been running.
+	Elapsed time.Duration
+
+	// CrossoverOperators and MutationOperators report on the operators
+	// registered in Params, in the same order, for this generation only.
+	CrossoverOperators []OperatorStats
+	MutationOperators  []OperatorStats
+
+	// CrossoverRate and MutationRate are the average rates used to breed
+	// this generation, after applying any RateSchedule.
+	CrossoverRate float64
+	MutationRate  float64
+
+	// SuccessRate is the fraction of this generation's offspring that
+	// were fitter than their fittest parent.
+	SuccessRate float64
+}
+
+// stats summarizes a scored population. The population
+// must be sorted from fittest to least fit.
+func (p *Population) stats(generation, evaluations int) Stats {
+	out := Stats{
+		Generation:     generation,
+		PopulationSize: len(p.pop),
+		Evaluations:    evaluations,
+	}
+	if len(p.pop) == 0 {
+		return out
+	}
+	out.Best = p.pop[0].score
+	out.Worst = p.pop[len(p.pop)-1].score
+	for _, ind := range p.pop {
+		out.Mean += ind.score
+	}
+	out.Mean /= float64(len(p.pop))
+	for _, ind := range p.pop {
+		out.Diversity += (ind.score - out.Mean) * (ind.score - out.Mean)
+	}
+	out.Diversity = math.Sqrt(out.Diversity / float64(len(p.pop)))
+	return out
+}
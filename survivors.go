@@ -0,0 +1,70 @@
+package genetic
+
+import "sort"
+
+// SurvivorSelection decides which individuals make it from one
+// generation into the next.
+type SurvivorSelection int
+
+const (
+	// MuCommaLambda is (μ,λ) selection: the fittest μ of the λ offspring
+	// replace the parents, apart from the elite. With λ = μ-Elitism this
+	// is the classic generational genetic algorithm.
+	MuCommaLambda SurvivorSelection = iota
+
+	// MuPlusLambda is (μ+λ) selection: parents and offspring compete,
+	// and the fittest μ of them survive.
+	MuPlusLambda
+)
+
+// selectSurvivors builds the next generation from the current population
+// and the scored offspring, according to Params.Survivors and Params.MaxAge.
+// The result is sorted from fittest to least fit.
+func (c *Controller) selectSurvivors(offspring []indWithScore) []indWithScore {
+	size := c.params.PopulationSize
+
+	// Individuals past the age limit are only kept if there
+	// aren't enough young ones to go around.
+	var parents, retired []indWithScore
+	for _, ind := range c.population.pop {
+		if c.params.MaxAge > 0 && c.generation-ind.born > c.params.MaxAge {
+			retired = append(retired, ind)
+		} else {
+			parents = append(parents, ind)
+		}
+	}
+
+	elites := c.params.Elitism
+	if elites > len(parents) {
+		elites = len(parents)
+	}
+	if elites > size {
+		elites = size
+	}
+	survivors := append([]indWithScore{}, parents[:elites]...)
+
+	candidates := append([]indWithScore{}, offspring...)
+	if c.params.Survivors == MuPlusLambda {
+		candidates = append(candidates, parents[elites:]...)
+	}
+	sort.Stable(sort.Reverse(pairs(candidates)))
+	if len(candidates) > size-len(survivors) {
+		candidates = candidates[:size-len(survivors)]
+	}
+	survivors = append(survivors, candidates...)
+
+	if len(survivors) < size {
+		if c.params.Survivors != MuPlusLambda {
+			retired = append(retired, parents[elites:]...)
+			sort.Stable(sort.Reverse(pairs(retired)))
+		}
+		for _, ind := range retired {
+			if len(survivors) == size {
+				break
+			}
+			survivors = append(survivors, ind)
+		}
+	}
+	sort.Stable(sort.Reverse(pairs(survivors)))
+	return survivors
+}
@@ -0,0 +1,53 @@
+package genetic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func survivorScores(survivors []indWithScore) []float64 {
+	var out []float64
+	for _, ind := range survivors {
+		out = append(out, ind.score)
+	}
+	return out
+}
+
+func Test_selectSurvivors_MuCommaLambda_OnlyEliteAndBestOffspringSurvive(t *testing.T) {
+	ctrl := &Controller{
+		params:     Params{PopulationSize: 3, Elitism: 1, Survivors: MuCommaLambda},
+		population: scoredPopulation(t, 9, 8, 7),
+	}
+	offspring := []indWithScore{{score: 1}, {score: 3}, {score: 2}}
+
+	survivors := ctrl.selectSurvivors(offspring)
+
+	assert.Equal(t, []float64{9, 3, 2}, survivorScores(survivors))
+}
+
+func Test_selectSurvivors_MuPlusLambda_ParentsCompeteWithOffspring(t *testing.T) {
+	ctrl := &Controller{
+		params:     Params{PopulationSize: 3, Survivors: MuPlusLambda},
+		population: scoredPopulation(t, 9, 5, 1),
+	}
+	offspring := []indWithScore{{score: 6}, {score: 0}}
+
+	survivors := ctrl.selectSurvivors(offspring)
+
+	assert.Equal(t, []float64{9, 6, 5}, survivorScores(survivors))
+}
+
+func Test_selectSurvivors_MaxAge_OldParentsRemoved(t *testing.T) {
+	ctrl := &Controller{
+		params:     Params{PopulationSize: 2, Elitism: 1, Survivors: MuPlusLambda, MaxAge: 2},
+		population: scoredPopulation(t, 9, 5),
+		generation: 3,
+	}
+	ctrl.population.pop[1].born = 2
+	offspring := []indWithScore{{score: 1, born: 3}, {score: 0, born: 3}}
+
+	survivors := ctrl.selectSurvivors(offspring)
+
+	assert.Equal(t, []float64{5, 1}, survivorScores(survivors))
+}
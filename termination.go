@@ -0,0 +1,73 @@
+package genetic
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTerminated is returned by Run() or Wait() when the search has
+// stopped because it reached one of its Termination limits before an
+// acceptable solution was found.
+var ErrTerminated = errors.New("search terminated before reaching target fitness")
+
+// Termination limits how long a search can run, besides stopping once
+// the target fitness has been met. Zero values mean there's no limit.
+type Termination struct {
+	// MaxGenerations is the number of generations to breed.
+	MaxGenerations int
+
+	// MaxEvaluations is the number of fitness evaluations to perform.
+	// A generation that has started is always finished, so the search
+	// can overshoot by up to one generation.
+	MaxEvaluations int
+
+	// MaxDuration is how long the search may take.
+	MaxDuration time.Duration
+
+	// Stagnation is how many generations the best score can go
+	// without improving.
+	Stagnation int
+}
+
+// validate checks that none of the limits are negative.
+func (t Termination) validate() error {
+	limits := []struct {
+		field string
+		value int64
+	}{
+		{"MaxGenerations", int64(t.MaxGenerations)},
+		{"MaxEvaluations", int64(t.MaxEvaluations)},
+		{"MaxDuration", int64(t.MaxDuration)},
+		{"Stagnation", int64(t.Stagnation)},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			return invalid("Termination."+limit.field, ErrOutOfRange, "cannot be negative")
+		}
+	}
+	return nil
+}
+
+// Done reports whether a search with the given history of statistics,
+// one per generation, has reached any of the limits.
+func (t Termination) Done(history []Stats) bool {
+	if len(history) == 0 {
+		return false
+	}
+	curr := history[len(history)-1]
+	if t.MaxGenerations > 0 && curr.Generation >= t.MaxGenerations {
+		return true
+	}
+	if t.MaxEvaluations > 0 && curr.Evaluations >= t.MaxEvaluations {
+		return true
+	}
+	if t.MaxDuration > 0 && curr.Elapsed >= t.MaxDuration {
+		return true
+	}
+	return t.Stagnation > 0 && stagnant(history, t.Stagnation)
+}
+
+// Observer is called with the statistics of every generation as soon as
+// it has been scored, from the goroutine running the search. It should
+// return quickly, since the search waits for it.
+type Observer func(Stats)
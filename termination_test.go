@@ -0,0 +1,82 @@
+package genetic
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Termination_Done_TestTable(t *testing.T) {
+	history := []Stats{
+		{Generation: 0, Best: 1, Evaluations: 10},
+		{Generation: 1, Best: 2, Evaluations: 20},
+		{Generation: 2, Best: 2, Evaluations: 30, Elapsed: time.Second},
+	}
+	testTable := []struct {
+		label       string
+		termination Termination
+		done        bool
+	}{
+		{label: "no limits", termination: Termination{}, done: false},
+		{label: "generations reached", termination: Termination{MaxGenerations: 2}, done: true},
+		{label: "generations left", termination: Termination{MaxGenerations: 3}, done: false},
+		{label: "evaluations reached", termination: Termination{MaxEvaluations: 30}, done: true},
+		{label: "duration reached", termination: Termination{MaxDuration: time.Second}, done: true},
+		{label: "stagnated", termination: Termination{Stagnation: 1}, done: true},
+		{label: "still improving", termination: Termination{Stagnation: 2}, done: false},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.label, func(t *testing.T) {
+			assert.Equal(t, testCase.done, testCase.termination.Done(history))
+		})
+	}
+}
+
+func Test_Run_MaxGenerations_ErrTerminatedAndObserverCalled(t *testing.T) {
+	var observed []Stats
+	ctrl, err := NewController(Params{
+		TargetFitness:   1,
+		SelectionMethod: Tournament(2),
+		InitPop:         make([]fakeIndividual, 4),
+		Termination:     Termination{MaxGenerations: 3},
+		Observer:        func(s Stats) { observed = append(observed, s) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ctrl.Run()
+
+	assert.Equal(t, ErrTerminated, err)
+	assert.Len(t, observed, 4)
+	assert.Equal(t, ctrl.Stats(), observed)
+}
+
+func Test_EvaluateConcurrently_ScoresInOrder(t *testing.T) {
+	scores, err := EvaluateConcurrently(100, 7, func(i int) (float64, error) {
+		return float64(i), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, score := range scores {
+		assert.Equal(t, float64(i), score)
+	}
+}
+
+func Test_EvaluateConcurrently_FitnessFails_ErrReturned(t *testing.T) {
+	failure := errors.New("failure")
+
+	_, err := EvaluateConcurrently(100, 3, func(i int) (float64, error) {
+		if i == 50 {
+			return 0, failure
+		}
+		return 0, nil
+	})
+
+	assert.Equal(t, failure, err)
+}
@@ -0,0 +1,106 @@
+package tsp
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Tour is a closed tour, visiting the cities of an instance in order
+// and returning to the first. It implements genetic.Individual.
+type Tour struct {
+	// Order holds the cities, numbered from zero.
+	Order []int
+
+	instance *Instance
+}
+
+// NewTour returns the tour of the instance that
+// visits the cities in the given order.
+func (in *Instance) NewTour(order []int) (*Tour, error) {
+	if err := in.validate(order); err != nil {
+		return nil, err
+	}
+	return &Tour{Order: order, instance: in}, nil
+}
+
+// Length returns the length of the tour.
+func (t *Tour) Length() float64 {
+	return t.instance.Length(t.Order)
+}
+
+// Fitness implements genetic.Individual.
+// It's the negated length of the tour.
+func (t *Tour) Fitness() (float64, error) {
+	return -t.Length(), nil
+}
+
+// Crossover implements genetic.Individual with order crossover: the
+// child keeps a random segment of this tour in place, and visits the
+// other cities in the order the partner does, starting after the
+// segment.
+func (t *Tour) Crossover(partner genetic.Individual) (genetic.Individual, error) {
+	mate, ok := partner.(*Tour)
+	if !ok {
+		return nil, fmt.Errorf("expected Individual to be *tsp.Tour, got %T", partner)
+	}
+	n := len(t.Order)
+	child := make([]int, n)
+	added := make([]bool, n)
+	start, end := t.instance.segment(n)
+	for i := start; i < end; i++ {
+		child[i] = t.Order[i]
+		added[t.Order[i]] = true
+	}
+	next := end % n
+	for i := 0; i < n; i++ {
+		city := mate.Order[(end+i)%n]
+		if added[city] {
+			continue
+		}
+		child[next] = city
+		next = (next + 1) % n
+	}
+	return &Tour{Order: child, instance: t.instance}, nil
+}
+
+// Mutate implements genetic.Individual. For every city, with the given
+// probability, it reverses a random stretch of the tour, which is a
+// 2-opt move. The tour itself is left alone.
+func (t *Tour) Mutate(rate float64) (genetic.Individual, error) {
+	out := append([]int{}, t.Order...)
+	for range out {
+		if t.instance.source.Float64() < rate {
+			start, end := t.instance.segment(len(out))
+			for i, j := start, end-1; i < j; i, j = i+1, j-1 {
+				out[i], out[j] = out[j], out[i]
+			}
+		}
+	}
+	return &Tour{Order: out, instance: t.instance}, nil
+}
+
+// segment returns the bounds of a random, non-empty segment of a tour
+// of n cities.
+func (in *Instance) segment(n int) (start, end int) {
+	a, b := in.source.Intn(n), in.source.Intn(n)
+	if a > b {
+		a, b = b, a
+	}
+	return a, b + 1
+}
+
+// String returns the cities in order, numbered from one as in TSPLIB.
+func (t *Tour) String() string {
+	out := bytes.NewBuffer(nil)
+	out.WriteString("[")
+	for i, city := range t.Order {
+		if i > 0 {
+			out.WriteString(" ")
+		}
+		fmt.Fprint(out, city+1)
+	}
+	out.WriteString("]")
+	return out.String()
+}
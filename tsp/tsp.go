@@ -0,0 +1,190 @@
+// Package tsp provides the traveling salesman problem for genetic
+// algorithms: instances with precomputed distance matrices, loaded from
+// TSPLIB files or made from coordinates, closed tours that implement
+// genetic.Individual, and reporting of the gap to the optimal tour.
+package tsp
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Instance is a traveling salesman problem: a set of cities and the
+// distances between them. All of the tours of a search share one.
+type Instance struct {
+	Name string
+
+	// Coords holds the coordinates of the cities,
+	// if the instance has them.
+	Coords [][2]float64
+
+	// Distances holds the distance between every pair of cities.
+	Distances [][]float64
+
+	// OptimalTour and Optimum hold the best tour and its length,
+	// if they're known.
+	OptimalTour []int
+	Optimum     float64
+
+	// source is where the tours get their random numbers, once the
+	// instance is seeded.
+	source *source
+}
+
+// Seed makes the instance's random tours, and the tours bred from them,
+// draw their random numbers from a source with the given seed rather
+// than the global one, so that a search seeded with Params.Seed as well
+// can be repeated exactly. Call it before searching.
+func (in *Instance) Seed(seed int64) {
+	in.source = &source{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Euclidean returns an instance with the given coordinates,
+// where distances are Euclidean.
+func Euclidean(name string, coords [][2]float64) *Instance {
+	in := &Instance{Name: name, Coords: coords}
+	in.Distances = matrix(len(coords), func(i, j int) float64 {
+		return math.Hypot(coords[i][0]-coords[j][0], coords[i][1]-coords[j][1])
+	})
+	return in
+}
+
+// matrix returns an n×n distance matrix.
+func matrix(n int, distance func(i, j int) float64) [][]float64 {
+	out := make([][]float64, n)
+	for i := range out {
+		out[i] = make([]float64, n)
+		for j := range out[i] {
+			if i != j {
+				out[i][j] = distance(i, j)
+			}
+		}
+	}
+	return out
+}
+
+// Size returns the number of cities.
+func (in *Instance) Size() int {
+	return len(in.Distances)
+}
+
+// Length returns the length of the closed tour
+// visiting the cities in the given order.
+func (in *Instance) Length(order []int) float64 {
+	out := float64(0)
+	for i, city := range order {
+		out += in.Distances[city][order[(i+1)%len(order)]]
+	}
+	return out
+}
+
+// SetOptimalTour records the optimal tour, and sets Optimum to its length.
+func (in *Instance) SetOptimalTour(order []int) error {
+	if err := in.validate(order); err != nil {
+		return err
+	}
+	in.OptimalTour = order
+	in.Optimum = in.Length(order)
+	return nil
+}
+
+// validate checks that order visits every city exactly once.
+func (in *Instance) validate(order []int) error {
+	if len(order) != in.Size() {
+		return fmt.Errorf("tour visits %d cities, instance has %d", len(order), in.Size())
+	}
+	seen := make([]bool, len(order))
+	for _, city := range order {
+		if city < 0 || city >= len(order) || seen[city] {
+			return fmt.Errorf("tour visits city %d more than once, or it doesn't exist", city+1)
+		}
+		seen[city] = true
+	}
+	return nil
+}
+
+// Gap returns how much longer a tour of the given length is than the
+// optimal tour, as a fraction of the optimum. It's NaN if the optimum
+// isn't known.
+func (in *Instance) Gap(length float64) float64 {
+	if in.Optimum == 0 {
+		return math.NaN()
+	}
+	return (length - in.Optimum) / in.Optimum
+}
+
+// TargetFitness returns the fitness of tours that are within the given
+// gap of the optimum, for use as Params.TargetFitness.
+func (in *Instance) TargetFitness(gap float64) float64 {
+	return -in.Optimum * (1 + gap)
+}
+
+// Observer returns a genetic.Observer that writes the length of the
+// best tour of every generation to w, along with its gap to the
+// optimum if that's known.
+func (in *Instance) Observer(w io.Writer) genetic.Observer {
+	return func(stats genetic.Stats) {
+		length := -stats.Best
+		if in.Optimum == 0 {
+			fmt.Fprintf(w, "%s generation %d: best tour %.0f\n", in.Name, stats.Generation, length)
+			return
+		}
+		fmt.Fprintf(w, "%s generation %d: best tour %.0f, gap %.2f%%\n", in.Name, stats.Generation, length, 100*in.Gap(length))
+	}
+}
+
+// Generator returns a genetic.Generator of random tours.
+func (in *Instance) Generator() genetic.Generator {
+	return func() (genetic.Individual, error) {
+		return &Tour{Order: in.source.Perm(in.Size()), instance: in}, nil
+	}
+}
+
+// Population returns n random tours.
+func (in *Instance) Population(n int) []genetic.Individual {
+	out := make([]genetic.Individual, n)
+	for i := range out {
+		out[i] = &Tour{Order: in.source.Perm(in.Size()), instance: in}
+	}
+	return out
+}
+
+// source is a random source that is safe for concurrent use, as the
+// tours of an instance may be bred by several searches at once. A nil
+// source draws from the global one.
+type source struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func (s *source) Float64() float64 {
+	if s == nil {
+		return rand.Float64()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64()
+}
+
+func (s *source) Intn(n int) int {
+	if s == nil {
+		return rand.Intn(n)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Intn(n)
+}
+
+func (s *source) Perm(n int) []int {
+	if s == nil {
+		return rand.Perm(n)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Perm(n)
+}
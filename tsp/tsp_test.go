@@ -0,0 +1,169 @@
+package tsp
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomjcleveland/genetic"
+)
+
+func burma14(t *testing.T) *Instance {
+	in, err := Load("testdata/burma14.tsp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := in.LoadOptimalTour("testdata/burma14.opt.tour"); err != nil {
+		t.Fatal(err)
+	}
+	return in
+}
+
+func Test_Load_GEO_MatchesPublishedOptimum(t *testing.T) {
+	in := burma14(t)
+	assert.Equal(t, "burma14", in.Name)
+	assert.Equal(t, 14, in.Size())
+	assert.Equal(t, float64(3323), in.Optimum)
+	assert.Equal(t, 0, in.OptimalTour[0])
+	assert.Equal(t, in.Distances[2][5], in.Distances[5][2])
+}
+
+func Test_Parse_EUC2D_And_ATT(t *testing.T) {
+	coords := `
+TYPE : TSP
+DIMENSION : 3
+EDGE_WEIGHT_TYPE : %s
+NODE_COORD_SECTION
+1 0 0
+2 3 4
+3 0 10.4
+EOF`
+	in, err := Parse(strings.NewReader(strings.Replace(coords, "%s", "EUC_2D", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, float64(5), in.Distances[0][1])
+	assert.Equal(t, float64(10), in.Distances[0][2])
+	assert.Equal(t, 3, len(in.Coords))
+
+	in, err = Parse(strings.NewReader(strings.Replace(coords, "%s", "ATT", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sqrt(25/10) = 1.58, which rounds down, so ATT rounds up
+	assert.Equal(t, float64(2), in.Distances[0][1])
+	// sqrt(108.16/10) = 3.29
+	assert.Equal(t, float64(4), in.Distances[0][2])
+}
+
+func Test_Parse_Explicit(t *testing.T) {
+	full := [][]float64{
+		{0, 1, 2, 3},
+		{1, 0, 4, 5},
+		{2, 4, 0, 6},
+		{3, 5, 6, 0},
+	}
+	formats := map[string]string{
+		"FULL_MATRIX":    "0 1 2 3\n1 0 4 5\n2 4 0 6\n3 5 6 0",
+		"UPPER_ROW":      "1 2 3\n4 5\n6",
+		"LOWER_ROW":      "1\n2 4\n3 5 6",
+		"UPPER_DIAG_ROW": "0 1 2 3 0 4 5 0 6 0",
+		"LOWER_DIAG_ROW": "0 1 0 2 4 0 3 5 6 0",
+		"UPPER_COL":      "1 2 4 3 5 6",
+		"LOWER_COL":      "1 2 3 4 5 6",
+		"UPPER_DIAG_COL": "0 1 0 2 4 0 3 5 6 0",
+		"LOWER_DIAG_COL": "0 1 2 3 0 4 5 0 6 0",
+	}
+	for format, weights := range formats {
+		t.Run(format, func(t *testing.T) {
+			in, err := Parse(strings.NewReader("NAME: explicit\nTYPE: TSP\nDIMENSION: 4\nEDGE_WEIGHT_TYPE: EXPLICIT\nEDGE_WEIGHT_FORMAT: " + format + "\nEDGE_WEIGHT_SECTION\n" + weights + "\nEOF\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, full, in.Distances)
+			assert.Nil(t, in.Coords)
+		})
+	}
+}
+
+func Test_Parse_Invalid_Error(t *testing.T) {
+	tests := map[string]string{
+		"not a TSP":      "TYPE: ATSP\nDIMENSION: 1\nEDGE_WEIGHT_TYPE: EUC_2D\nNODE_COORD_SECTION\n1 0 0\n",
+		"bad dimension":  "TYPE: TSP\nDIMENSION: x\n",
+		"missing coords": "TYPE: TSP\nDIMENSION: 2\nEDGE_WEIGHT_TYPE: EUC_2D\nNODE_COORD_SECTION\n1 0 0\n",
+		"weight type":    "TYPE: TSP\nDIMENSION: 1\nEDGE_WEIGHT_TYPE: MAN_3D\nNODE_COORD_SECTION\n1 0 0\n",
+		"weight count":   "TYPE: TSP\nDIMENSION: 3\nEDGE_WEIGHT_TYPE: EXPLICIT\nEDGE_WEIGHT_FORMAT: UPPER_ROW\nEDGE_WEIGHT_SECTION\n1 2\n",
+		"weight format":  "TYPE: TSP\nDIMENSION: 2\nEDGE_WEIGHT_TYPE: EXPLICIT\nEDGE_WEIGHT_FORMAT: FUNCTION\nEDGE_WEIGHT_SECTION\n1\n",
+		"stray line":     "TYPE: TSP\n1 2 3\n",
+	}
+	for name, tsp := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tsp))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_Length_IsClosed(t *testing.T) {
+	in := Euclidean("square", [][2]float64{{0, 0}, {0, 1}, {1, 1}, {1, 0}})
+	assert.Equal(t, float64(4), in.Length([]int{0, 1, 2, 3}))
+	assert.InDelta(t, 2+2*math.Sqrt2, in.Length([]int{0, 2, 1, 3}), 1e-9)
+	if err := in.SetOptimalTour([]int{3, 2, 1, 0}); err != nil {
+		t.Fatal(err)
+	}
+	assert.InDelta(t, 0.25, in.Gap(5), 1e-9)
+	assert.Equal(t, float64(-5), in.TargetFitness(0.25))
+	assert.Error(t, in.SetOptimalTour([]int{0, 1, 1, 3}))
+}
+
+func Test_Tour_Operators_KeepPermutations(t *testing.T) {
+	in := burma14(t)
+	pop := in.Population(20)
+	for i := 0; i+1 < len(pop); i++ {
+		parent := pop[i].(*Tour)
+		before := append([]int{}, parent.Order...)
+
+		mutant, err := parent.Mutate(0.5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NoError(t, in.validate(mutant.(*Tour).Order))
+		assert.Equal(t, before, parent.Order)
+
+		child, err := parent.Crossover(pop[i+1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NoError(t, in.validate(child.(*Tour).Order))
+		assert.Equal(t, before, parent.Order)
+	}
+}
+
+func Test_Observer_ReportsGap(t *testing.T) {
+	in := burma14(t)
+	out := bytes.NewBuffer(nil)
+	in.Observer(out)(genetic.Stats{Generation: 7, Best: -3489.15})
+	assert.Equal(t, "burma14 generation 7: best tour 3489, gap 5.00%\n", out.String())
+}
+
+func Test_Instance_Seed_Repeatable(t *testing.T) {
+	var runs [][]string
+	for i := 0; i < 2; i++ {
+		in := burma14(t)
+		in.Seed(5)
+		pop := in.Population(2)
+		child, err := pop[0].Crossover(pop[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		mutant, err := child.Mutate(0.5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		runs = append(runs, []string{fmt.Sprint(pop), fmt.Sprint(child), fmt.Sprint(mutant)})
+	}
+	assert.Equal(t, runs[0], runs[1])
+}
@@ -0,0 +1,276 @@
+package tsp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Load reads a TSPLIB .tsp file. See Parse.
+func Load(path string) (*Instance, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads a symmetric traveling salesman problem in the TSPLIB
+// format (Reinelt, 1991). Distances may be given as coordinates with
+// EDGE_WEIGHT_TYPE EUC_2D, GEO or ATT, or as an EXPLICIT matrix in
+// any of the FULL_MATRIX, UPPER_ROW, LOWER_ROW, UPPER_DIAG_ROW,
+// LOWER_DIAG_ROW formats or their column-wise equivalents. Distances
+// are rounded the way TSPLIB specifies, so tour lengths match the
+// published optima.
+func Parse(r io.Reader) (*Instance, error) {
+	header := make(map[string]string)
+	var coords [][2]float64
+	var weights []float64
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line == "EOF" {
+			continue
+		}
+		if key, value, ok := keyword(line); ok {
+			section = ""
+			if strings.HasSuffix(key, "_SECTION") {
+				section = key
+				continue
+			}
+			header[key] = value
+			continue
+		}
+		fields := strings.Fields(line)
+		switch section {
+		case "NODE_COORD_SECTION":
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("expected a node and two coordinates, got %q", line)
+			}
+			values, err := numbers(fields[1:])
+			if err != nil {
+				return nil, err
+			}
+			coords = append(coords, [2]float64{values[0], values[1]})
+		case "EDGE_WEIGHT_SECTION":
+			values, err := numbers(fields)
+			if err != nil {
+				return nil, err
+			}
+			weights = append(weights, values...)
+		case "":
+			return nil, fmt.Errorf("unexpected line %q", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if t := header["TYPE"]; t != "TSP" {
+		return nil, fmt.Errorf("unsupported problem type %q", t)
+	}
+	n, err := strconv.Atoi(header["DIMENSION"])
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid dimension %q", header["DIMENSION"])
+	}
+	in := &Instance{Name: header["NAME"]}
+	if weightType := header["EDGE_WEIGHT_TYPE"]; weightType == "EXPLICIT" {
+		in.Distances, err = explicit(n, header["EDGE_WEIGHT_FORMAT"], weights)
+	} else {
+		in.Coords = coords
+		in.Distances, err = fromCoords(n, weightType, coords)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+// keyword splits a line that starts with a TSPLIB keyword
+// into the keyword and its value.
+func keyword(line string) (string, string, bool) {
+	key, value := line, ""
+	if i := strings.IndexAny(line, ": \t"); i >= 0 {
+		key, value = line[:i], strings.TrimSpace(line[i:])
+		value = strings.TrimSpace(strings.TrimPrefix(value, ":"))
+	}
+	if key == "" || strings.IndexFunc(key, func(r rune) bool { return (r < 'A' || r > 'Z') && r != '_' }) >= 0 {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+func numbers(fields []string) ([]float64, error) {
+	out := make([]float64, len(fields))
+	for i, field := range fields {
+		var err error
+		if out[i], err = strconv.ParseFloat(field, 64); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// nint rounds to the nearest integer, as TSPLIB does.
+func nint(x float64) float64 {
+	return math.Floor(x + 0.5)
+}
+
+func fromCoords(n int, weightType string, coords [][2]float64) ([][]float64, error) {
+	if len(coords) != n {
+		return nil, fmt.Errorf("expected %d coordinates, got %d", n, len(coords))
+	}
+	switch weightType {
+	case "EUC_2D":
+		return matrix(n, func(i, j int) float64 {
+			return nint(math.Hypot(coords[i][0]-coords[j][0], coords[i][1]-coords[j][1]))
+		}), nil
+	case "ATT":
+		return matrix(n, func(i, j int) float64 {
+			dx, dy := coords[i][0]-coords[j][0], coords[i][1]-coords[j][1]
+			r := math.Sqrt((dx*dx + dy*dy) / 10)
+			if t := nint(r); t < r {
+				return t + 1
+			}
+			return nint(r)
+		}), nil
+	case "GEO":
+		lat, long := make([]float64, n), make([]float64, n)
+		for i, c := range coords {
+			lat[i], long[i] = radians(c[0]), radians(c[1])
+		}
+		return matrix(n, func(i, j int) float64 {
+			const rrr = 6378.388
+			q1 := math.Cos(long[i] - long[j])
+			q2 := math.Cos(lat[i] - lat[j])
+			q3 := math.Cos(lat[i] + lat[j])
+			return math.Floor(rrr*math.Acos(0.5*((1+q1)*q2-(1-q1)*q3)) + 1)
+		}), nil
+	}
+	return nil, fmt.Errorf("unsupported edge weight type %q", weightType)
+}
+
+// radians converts TSPLIB's DDD.MM geographical
+// coordinates, degrees and minutes, to radians.
+func radians(x float64) float64 {
+	const pi = 3.141592
+	deg := math.Trunc(x)
+	return pi * (deg + 5*(x-deg)/3) / 180
+}
+
+func explicit(n int, format string, weights []float64) ([][]float64, error) {
+	out := make([][]float64, n)
+	for i := range out {
+		out[i] = make([]float64, n)
+	}
+	// Column-wise formats of one triangle list the
+	// same numbers as row-wise ones of the other
+	switch format {
+	case "UPPER_COL":
+		format = "LOWER_ROW"
+	case "LOWER_COL":
+		format = "UPPER_ROW"
+	case "UPPER_DIAG_COL":
+		format = "LOWER_DIAG_ROW"
+	case "LOWER_DIAG_COL":
+		format = "UPPER_DIAG_ROW"
+	}
+	var cells [][2]int
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			switch format {
+			case "FULL_MATRIX":
+			case "UPPER_ROW":
+				if j <= i {
+					continue
+				}
+			case "LOWER_ROW":
+				if j >= i {
+					continue
+				}
+			case "UPPER_DIAG_ROW":
+				if j < i {
+					continue
+				}
+			case "LOWER_DIAG_ROW":
+				if j > i {
+					continue
+				}
+			default:
+				return nil, fmt.Errorf("unsupported edge weight format %q", format)
+			}
+			cells = append(cells, [2]int{i, j})
+		}
+	}
+	if len(weights) != len(cells) {
+		return nil, fmt.Errorf("expected %d edge weights, got %d", len(cells), len(weights))
+	}
+	for k, cell := range cells {
+		i, j := cell[0], cell[1]
+		out[i][j], out[j][i] = weights[k], weights[k]
+	}
+	return out, nil
+}
+
+// LoadTour reads a TSPLIB .tour file, such as the .opt.tour files of
+// the optimal tours. See ParseTour.
+func LoadTour(path string) ([]int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseTour(f)
+}
+
+// ParseTour reads a tour in the TSPLIB format, returning the
+// cities numbered from zero rather than one.
+func ParseTour(r io.Reader) ([]int, error) {
+	var out []int
+	inTour := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if key, _, ok := keyword(line); ok {
+			inTour = key == "TOUR_SECTION"
+			continue
+		}
+		if !inTour {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			city, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, err
+			}
+			if city == -1 {
+				return out, nil
+			}
+			out = append(out, city-1)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, errors.New("no tour found")
+	}
+	return out, nil
+}
+
+// LoadOptimalTour reads the optimal tour from a TSPLIB
+// .opt.tour file, and records it with SetOptimalTour.
+func (in *Instance) LoadOptimalTour(path string) error {
+	tour, err := LoadTour(path)
+	if err != nil {
+		return err
+	}
+	return in.SetOptimalTour(tour)
+}
@@ -0,0 +1,107 @@
+package tune
+
+import (
+	"context"
+	"math"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// candidate is a configuration evolved by the meta-GA. Its genes lie
+// between 0 and 1, and map to values of the parameters.
+type candidate struct {
+	genes []float64
+	tuner *Tuner
+	ctx   context.Context
+}
+
+// Fitness runs the configuration with every seed, and returns its mean
+// best score. Configurations that can't be run, or whose runs were cut
+// short by the budget, get the lowest score.
+func (c *candidate) Fitness() (float64, error) {
+	r, ok := c.tuner.result(c.tuner.values(c.genes))
+	c.tuner.mu.Lock()
+	done := c.tuner.done[r]
+	c.tuner.mu.Unlock()
+	if !ok {
+		// Another candidate has the same configuration.
+		<-done
+	} else {
+		defer close(done)
+		for i := 0; i < c.tuner.params.Seeds; i++ {
+			if err := c.tuner.runConfig(c.ctx, r, c.tuner.params.Seed+int64(i)); err != nil {
+				return 0, err
+			}
+		}
+	}
+	c.tuner.mu.Lock()
+	defer c.tuner.mu.Unlock()
+	if r.Err != nil || len(r.Scores) < c.tuner.params.Seeds {
+		return -math.MaxFloat64, nil
+	}
+	return r.Mean, nil
+}
+
+// Crossover is uniform crossover. Like Mutate, it draws from the
+// tuner's source, which only the meta-GA's controller uses while it
+// breeds, so that tunings with the same Seed evolve alike.
+func (c *candidate) Crossover(mate genetic.Individual) (genetic.Individual, error) {
+	other := mate.(*candidate)
+	child := c.clone()
+	for i := range child.genes {
+		if c.tuner.rng.Intn(2) == 0 {
+			child.genes[i] = other.genes[i]
+		}
+	}
+	return child, nil
+}
+
+// Mutate moves every gene, with probability rate, by a normally
+// distributed step, keeping it between 0 and 1.
+func (c *candidate) Mutate(rate float64) (genetic.Individual, error) {
+	child := c.clone()
+	for i := range child.genes {
+		if c.tuner.rng.Float64() < rate {
+			child.genes[i] = math.Max(0, math.Min(1, child.genes[i]+0.1*c.tuner.rng.NormFloat64()))
+		}
+	}
+	return child, nil
+}
+
+func (c *candidate) clone() *candidate {
+	return &candidate{genes: append([]float64{}, c.genes...), tuner: c.tuner, ctx: c.ctx}
+}
+
+// evolve tunes with a genetic algorithm of Samples configurations,
+// which runs for Generations generations.
+func (t *Tuner) evolve(ctx context.Context) error {
+	pop := make([]genetic.Individual, t.params.Samples)
+	for i := range pop {
+		genes := make([]float64, len(t.params.Parameters))
+		for j := range genes {
+			genes[j] = t.rng.Float64()
+		}
+		pop[i] = &candidate{genes: genes, tuner: t, ctx: ctx}
+	}
+	c, err := genetic.NewController(genetic.Params{
+		Elitism:         1,
+		Mutation:        0.2,
+		Crossover:       0.8,
+		SelectionMethod: genetic.Tournament(2),
+		TargetFitness:   math.Inf(1),
+		Termination:     genetic.Termination{MaxGenerations: t.params.Generations},
+		Parallelism:     t.params.Parallelism,
+		Seed:            t.params.Seed,
+		InitPop:         pop,
+	})
+	if err != nil {
+		return err
+	}
+	c.Start(ctx)
+	switch err := c.Wait(); err {
+	case nil, genetic.ErrTerminated, genetic.ErrContextCancelled:
+		return nil
+	default:
+		return err
+	}
+}
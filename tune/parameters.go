@@ -0,0 +1,117 @@
+package tune
+
+import (
+	"math"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Parameter is one dimension of the search space: a range of values,
+// and how a value is applied to genetic.Params.
+type Parameter struct {
+	Name string
+
+	// Min and Max bound the values, inclusive.
+	Min, Max float64
+
+	// Integer rounds values to whole numbers.
+	Integer bool
+
+	// Values, if given, are the only values tried,
+	// instead of the range from Min to Max.
+	Values []float64
+
+	// Set applies a value to the parameters of a run.
+	Set func(params *genetic.Params, value float64)
+}
+
+// Elitism tunes Params.Elitism.
+func Elitism(min, max int) Parameter {
+	return Parameter{
+		Name:    "elitism",
+		Min:     float64(min),
+		Max:     float64(max),
+		Integer: true,
+		Set:     func(p *genetic.Params, v float64) { p.Elitism = int(v) },
+	}
+}
+
+// Mutation tunes Params.Mutation.
+func Mutation(min, max float64) Parameter {
+	return Parameter{
+		Name: "mutation",
+		Min:  min,
+		Max:  max,
+		Set:  func(p *genetic.Params, v float64) { p.Mutation = v },
+	}
+}
+
+// Crossover tunes Params.Crossover.
+func Crossover(min, max float64) Parameter {
+	return Parameter{
+		Name: "crossover",
+		Min:  min,
+		Max:  max,
+		Set:  func(p *genetic.Params, v float64) { p.Crossover = v },
+	}
+}
+
+// TournamentSize tunes the size of tournament selection,
+// replacing Params.SelectionMethod.
+func TournamentSize(min, max int) Parameter {
+	return Parameter{
+		Name:    "tournament",
+		Min:     float64(min),
+		Max:     float64(max),
+		Integer: true,
+		Set:     func(p *genetic.Params, v float64) { p.SelectionMethod = genetic.Tournament(int(v)) },
+	}
+}
+
+// PopulationSize tunes Params.PopulationSize, and
+// with it the size of the initial population.
+func PopulationSize(min, max int) Parameter {
+	return Parameter{
+		Name:    "population",
+		Min:     float64(min),
+		Max:     float64(max),
+		Integer: true,
+		Set:     func(p *genetic.Params, v float64) { p.PopulationSize = int(v) },
+	}
+}
+
+// value maps u, between 0 and 1, to a value of the parameter.
+func (p Parameter) value(u float64) float64 {
+	if len(p.Values) > 0 {
+		i := int(u * float64(len(p.Values)))
+		if i == len(p.Values) {
+			i--
+		}
+		return p.Values[i]
+	}
+	v := p.Min + u*(p.Max-p.Min)
+	if p.Integer {
+		v = math.Round(v)
+	}
+	return v
+}
+
+// grid returns the values a grid search tries: Values if they are
+// given, or else the given number of evenly spaced values.
+func (p Parameter) grid(steps int) []float64 {
+	if len(p.Values) > 0 {
+		return p.Values
+	}
+	if p.Min == p.Max || steps == 1 {
+		return []float64{p.value(0)}
+	}
+	var out []float64
+	for i := 0; i < steps; i++ {
+		v := p.value(float64(i) / float64(steps-1))
+		if len(out) > 0 && out[len(out)-1] == v {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,638 @@
+// Package tune searches for good genetic.Params for a problem, with a
+// grid search or a random search over ranges of parameters, or with a
+// genetic algorithm that evolves them. Every configuration is run with
+// several seeds, racing can discard poor configurations before all of
+// their runs, and the result is a report that ranks the configurations
+// by their mean best score.
+package tune
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tomjcleveland/genetic"
+)
+
+// Method is how configurations are chosen.
+type Method int
+
+const (
+	// Grid tries every combination of the parameters' grid values.
+	// It's the default.
+	Grid Method = iota
+
+	// Random tries configurations drawn uniformly from the ranges.
+	Random
+
+	// MetaGA evolves configurations with a genetic algorithm whose
+	// fitness is the mean best score of a configuration's runs.
+	MetaGA
+)
+
+// Params holds the parameters of a tuning.
+type Params struct {
+	// Base holds the parameters that aren't tuned. Its InitPop is
+	// replaced for every run, and its Seed by the seed of the run.
+	Base genetic.Params
+
+	// Population makes the initial population of a run.
+	Population func(n int) ([]genetic.Individual, error)
+
+	// Size is the population size when it isn't tuned.
+	// The default is 100.
+	Size int
+
+	// Parameters are the dimensions of the search.
+	Parameters []Parameter
+
+	// Method is how configurations are chosen.
+	Method Method
+
+	// Steps is how many values of every parameter a grid search tries,
+	// unless the parameter lists its Values. The default is 5.
+	Steps int
+
+	// Samples is how many configurations a random search tries, and the
+	// population size of the meta-GA. The default is 20.
+	Samples int
+
+	// Generations is how many generations the meta-GA breeds.
+	// The default is 10.
+	Generations int
+
+	// Seeds is how many runs every configuration gets.
+	// The default is 5.
+	Seeds int
+
+	// Seed is the seed of every configuration's first run, and the
+	// following runs use the following seeds, so configurations are
+	// compared on the same seeds. It also seeds the choice of
+	// configurations. The default is 1. Runs are only reproducible
+	// when the Individuals get their randomness from the seed too.
+	Seed int64
+
+	// MaxDuration and MaxEvaluations are the budget of the whole tuning.
+	// Runs are charged their evaluations every generation. Once either
+	// is spent no more runs start, runs that are under way are
+	// abandoned, and the tuning terminates with genetic.ErrTerminated.
+	MaxDuration    time.Duration
+	MaxEvaluations int
+
+	// Racing discards a configuration once its mean score is
+	// significantly lower than that of the best configuration, by a
+	// two-sample z-test. It applies to grid and random searches.
+	Racing bool
+
+	// MinRuns is how many runs a configuration gets before racing can
+	// discard it. The default is 3.
+	MinRuns int
+
+	// Confidence is the z-score of the racing test. The default is 2.
+	Confidence float64
+
+	// Parallelism is how many runs are performed at once.
+	// The default is one.
+	Parallelism int
+}
+
+func (params *Params) validate() error {
+	if params.Population == nil {
+		return errors.New("a population function is needed")
+	}
+	if len(params.Parameters) == 0 {
+		return errors.New("no parameters to tune")
+	}
+	for _, p := range params.Parameters {
+		if p.Set == nil {
+			return fmt.Errorf("parameter %q has no Set function", p.Name)
+		}
+		if len(p.Values) == 0 && p.Min > p.Max {
+			return fmt.Errorf("parameter %q has a minimum greater than its maximum", p.Name)
+		}
+	}
+	if params.Base.Termination == (genetic.Termination{}) {
+		return errors.New("every run needs a termination limit")
+	}
+	if params.Method < Grid || params.Method > MetaGA {
+		return fmt.Errorf("unknown method %d", params.Method)
+	}
+	if params.Size == 0 {
+		params.Size = 100
+	}
+	if params.Steps == 0 {
+		params.Steps = 5
+	}
+	if params.Samples == 0 {
+		params.Samples = 20
+	}
+	if params.Generations == 0 {
+		params.Generations = 10
+	}
+	if params.Seeds == 0 {
+		params.Seeds = 5
+	}
+	if params.Seed == 0 {
+		params.Seed = 1
+	}
+	if params.MinRuns == 0 {
+		params.MinRuns = 3
+	}
+	if params.Confidence == 0 {
+		params.Confidence = 2
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = 1
+	}
+	if params.Size < 1 || params.Steps < 1 || params.Samples < 1 || params.Generations < 1 ||
+		params.Seeds < 1 || params.MinRuns < 1 || params.Parallelism < 1 {
+		return errors.New("sizes and counts must be positive")
+	}
+	if params.Confidence < 0 || params.MaxDuration < 0 || params.MaxEvaluations < 0 {
+		return errors.New("confidence and budgets can't be negative")
+	}
+	return nil
+}
+
+// Result is the outcome of a configuration's runs.
+type Result struct {
+	// Values holds the value of every parameter, in the
+	// order of Params.Parameters.
+	Values []float64
+
+	// Scores holds the best score of every run, in the order of seeds.
+	Scores []float64
+
+	// Mean and StdDev summarize Scores.
+	Mean   float64
+	StdDev float64
+
+	// Evaluations is how many fitness evaluations the runs performed.
+	Evaluations int
+
+	// Eliminated says racing discarded the configuration.
+	Eliminated bool
+
+	// Err is why the configuration couldn't be run,
+	// such as a crossover rate above one.
+	Err error
+}
+
+// add records the best score of a run.
+func (r *Result) add(score float64, evaluations int) {
+	r.Scores = append(r.Scores, score)
+	r.Evaluations += evaluations
+	r.Mean = 0
+	for _, s := range r.Scores {
+		r.Mean += s
+	}
+	r.Mean /= float64(len(r.Scores))
+	r.StdDev = 0
+	if len(r.Scores) > 1 {
+		for _, s := range r.Scores {
+			r.StdDev += (s - r.Mean) * (s - r.Mean)
+		}
+		r.StdDev = math.Sqrt(r.StdDev / float64(len(r.Scores)-1))
+	}
+}
+
+// Tuner coordinates a tuning.
+type Tuner struct {
+	params Params
+	// rng, seeded with Params.Seed, draws the sampled configurations
+	// and the meta-GA's genes.
+	rng   *rand.Rand
+	start time.Time
+
+	// stop abandons the runs under way once the budget is spent.
+	stop context.CancelFunc
+
+	// mu guards the fields below.
+	mu      sync.Mutex
+	results []*Result
+	index   map[string]*Result
+	// done is closed once the meta-GA has run a configuration
+	// with every seed.
+	done        map[*Result]chan struct{}
+	runs        int
+	evaluations int
+	spent       bool
+
+	err chan error
+}
+
+// NewTuner validates the parameters, and returns a Tuner.
+func NewTuner(params Params) (*Tuner, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &Tuner{
+		params: params,
+		rng:    rand.New(rand.NewSource(params.Seed)),
+		index:  map[string]*Result{},
+		done:   map[*Result]chan struct{}{},
+		err:    make(chan error),
+	}, nil
+}
+
+// Run tunes until every configuration has been tried,
+// or the budget is spent.
+func (t *Tuner) Run() error {
+	t.Start(context.Background())
+	return t.Wait()
+}
+
+// Start begins the tuning in a new goroutine, and returns immediately.
+// The context parameter can be used to prematurely cancel it.
+func (t *Tuner) Start(ctx context.Context) {
+	go func() {
+		t.err <- t.run(ctx)
+	}()
+}
+
+// Wait blocks until the tuning has finished.
+func (t *Tuner) Wait() error {
+	if err, ok := <-t.err; ok {
+		return err
+	}
+	return errors.New("error channel is closed")
+}
+
+func (t *Tuner) run(ctx context.Context) error {
+	t.start = time.Now()
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	t.stop = stop
+	if t.params.MaxDuration > 0 {
+		timer := time.AfterFunc(t.params.MaxDuration, t.spend)
+		defer timer.Stop()
+	}
+
+	var err error
+	switch t.params.Method {
+	case Grid:
+		err = t.race(runCtx, t.grid())
+	case Random:
+		err = t.race(runCtx, t.sample())
+	case MetaGA:
+		err = t.evolve(runCtx)
+	}
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if ctx.Err() != nil {
+		return genetic.ErrContextCancelled
+	}
+	if t.spent {
+		return genetic.ErrTerminated
+	}
+	return nil
+}
+
+// spend marks the budget as spent, and abandons the runs under way.
+func (t *Tuner) spend() {
+	t.mu.Lock()
+	t.spent = true
+	t.mu.Unlock()
+	t.stop()
+}
+
+// grid returns every combination of the parameters' grid values.
+func (t *Tuner) grid() [][]float64 {
+	out := [][]float64{nil}
+	for _, p := range t.params.Parameters {
+		var next [][]float64
+		for _, values := range out {
+			for _, v := range p.grid(t.params.Steps) {
+				next = append(next, append(append([]float64{}, values...), v))
+			}
+		}
+		out = next
+	}
+	return out
+}
+
+// sample returns Samples configurations drawn at random.
+func (t *Tuner) sample() [][]float64 {
+	out := make([][]float64, t.params.Samples)
+	for i := range out {
+		genes := make([]float64, len(t.params.Parameters))
+		for j := range genes {
+			genes[j] = t.rng.Float64()
+		}
+		out[i] = t.values(genes)
+	}
+	return out
+}
+
+// values maps genes, between 0 and 1, to values of the parameters.
+func (t *Tuner) values(genes []float64) []float64 {
+	out := make([]float64, len(genes))
+	for i, p := range t.params.Parameters {
+		out[i] = p.value(genes[i])
+	}
+	return out
+}
+
+// result returns the result of a configuration, and whether it is new.
+// Configurations that were already tried share their result.
+func (t *Tuner) result(values []float64) (*Result, bool) {
+	key := fmt.Sprint(values)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if r, ok := t.index[key]; ok {
+		return r, false
+	}
+	r := &Result{Values: values}
+	t.index[key] = r
+	t.done[r] = make(chan struct{})
+	t.results = append(t.results, r)
+	return r, true
+}
+
+// race runs every configuration once per seed, a seed at a time, and
+// discards configurations that fall behind if Racing is set.
+func (t *Tuner) race(ctx context.Context, configs [][]float64) error {
+	var alive []*Result
+	for _, values := range configs {
+		if r, ok := t.result(values); ok {
+			alive = append(alive, r)
+		}
+	}
+	for i := 0; i < t.params.Seeds && len(alive) > 0; i++ {
+		seed := t.params.Seed + int64(i)
+		jobs := make(chan *Result)
+		errs := make(chan error, t.params.Parallelism)
+		var wg sync.WaitGroup
+		for w := 0; w < t.params.Parallelism; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for r := range jobs {
+					if err := t.runConfig(ctx, r, seed); err != nil {
+						errs <- err
+						return
+					}
+				}
+			}()
+		}
+		var err error
+	send:
+		for _, r := range alive {
+			select {
+			case jobs <- r:
+			case err = <-errs:
+				break send
+			case <-ctx.Done():
+				break send
+			}
+		}
+		close(jobs)
+		wg.Wait()
+		if err == nil && len(errs) > 0 {
+			err = <-errs
+		}
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		var next []*Result
+		for _, r := range alive {
+			if r.Err == nil {
+				next = append(next, r)
+			}
+		}
+		if t.params.Racing && i+1 >= t.params.MinRuns {
+			next = t.eliminate(next)
+		}
+		alive = next
+	}
+	return nil
+}
+
+// eliminate discards the configurations whose mean score is
+// significantly lower than the best's, and returns the rest.
+func (t *Tuner) eliminate(alive []*Result) []*Result {
+	if len(alive) == 0 {
+		return alive
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	best := alive[0]
+	for _, r := range alive {
+		if r.Mean > best.Mean {
+			best = r
+		}
+	}
+	out := []*Result{best}
+	for _, r := range alive {
+		if r == best {
+			continue
+		}
+		se := math.Sqrt(r.StdDev*r.StdDev/float64(len(r.Scores)) + best.StdDev*best.StdDev/float64(len(best.Scores)))
+		if r.Mean+t.params.Confidence*se < best.Mean {
+			r.Eliminated = true
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// runConfig performs a run of a configuration with the given seed, and
+// records its best score. A configuration that can't be run has its
+// error recorded instead. Only errors from the individuals themselves
+// are returned.
+func (t *Tuner) runConfig(ctx context.Context, r *Result, seed int64) error {
+	t.mu.Lock()
+	if t.params.MaxEvaluations > 0 && t.evaluations >= t.params.MaxEvaluations {
+		t.mu.Unlock()
+		t.spend()
+		return nil
+	}
+	t.mu.Unlock()
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	params := t.params.Base
+	for i, p := range t.params.Parameters {
+		p.Set(&params, r.Values[i])
+	}
+	params.Seed = seed
+	size := params.PopulationSize
+	if size == 0 {
+		size = t.params.Size
+	}
+	pop, err := t.params.Population(size)
+	if err != nil {
+		return fmt.Errorf("failed to make a population: %s", err)
+	}
+	params.InitPop = pop
+	charged := 0
+	if t.params.MaxEvaluations > 0 {
+		observer := params.Observer
+		params.Observer = func(stats genetic.Stats) {
+			if observer != nil {
+				observer(stats)
+			}
+			t.charge(stats.Evaluations - charged)
+			charged = stats.Evaluations
+		}
+	}
+	c, err := genetic.NewController(params)
+	if err != nil {
+		t.mu.Lock()
+		r.Err = err
+		t.mu.Unlock()
+		return nil
+	}
+	c.Start(ctx)
+	switch err := c.Wait(); err {
+	case nil, genetic.ErrTerminated:
+	case genetic.ErrContextCancelled:
+		return nil
+	default:
+		return err
+	}
+
+	stats := c.Stats()
+	score := math.Inf(-1)
+	for _, s := range stats {
+		score = math.Max(score, s.Best)
+	}
+	evaluations := stats[len(stats)-1].Evaluations
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r.add(score, evaluations)
+	t.runs++
+	t.evaluations += evaluations - charged
+	return nil
+}
+
+// charge adds the evaluations of a run under way to the
+// tuning's, and spends the budget once they reach MaxEvaluations.
+func (t *Tuner) charge(evaluations int) {
+	t.mu.Lock()
+	t.evaluations += evaluations
+	spent := t.evaluations >= t.params.MaxEvaluations
+	t.mu.Unlock()
+	if spent {
+		t.spend()
+	}
+}
+
+// Report returns the results so far.
+func (t *Tuner) Report() *Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := &Report{
+		Runs:        t.runs,
+		Evaluations: t.evaluations,
+		Elapsed:     time.Since(t.start),
+		parameters:  t.params.Parameters,
+		base:        t.params.Base,
+	}
+	for _, p := range t.params.Parameters {
+		out.Parameters = append(out.Parameters, p.Name)
+	}
+	for _, r := range t.results {
+		if len(r.Scores) == 0 && r.Err == nil {
+			continue
+		}
+		c := *r
+		c.Values = append([]float64{}, r.Values...)
+		c.Scores = append([]float64{}, r.Scores...)
+		out.Results = append(out.Results, c)
+	}
+	sort.SliceStable(out.Results, func(i, j int) bool {
+		a, b := out.Results[i], out.Results[j]
+		if (a.Err == nil) != (b.Err == nil) {
+			return a.Err == nil
+		}
+		if a.Eliminated != b.Eliminated {
+			return !a.Eliminated
+		}
+		if len(a.Scores) != len(b.Scores) {
+			return len(a.Scores) > len(b.Scores)
+		}
+		return a.Mean > b.Mean
+	})
+	return out
+}
+
+// Report ranks the configurations of a tuning. Configurations that ran
+// with every seed come first, ordered by their mean best score, followed
+// by the ones that didn't, and those that couldn't be run.
+type Report struct {
+	// Parameters names the parameters, in the order of Result.Values.
+	Parameters []string
+
+	Results []Result
+
+	// Runs and Evaluations count the runs and fitness evaluations
+	// of the whole tuning. Evaluations includes those of the runs
+	// abandoned when the budget was spent.
+	Runs        int
+	Evaluations int
+	Elapsed     time.Duration
+
+	parameters []Parameter
+	base       genetic.Params
+}
+
+// Best returns the best configuration.
+func (r *Report) Best() (Result, error) {
+	if len(r.Results) == 0 || r.Results[0].Err != nil {
+		return Result{}, errors.New("no configuration has been run")
+	}
+	return r.Results[0], nil
+}
+
+// Params returns the tuning's base parameters with
+// the values of the given result applied.
+func (r *Report) Params(result Result) genetic.Params {
+	out := r.base
+	for i, p := range r.parameters {
+		p.Set(&out, result.Values[i])
+	}
+	return out
+}
+
+// String formats the report as a table.
+func (r *Report) String() string {
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "%d runs, %d evaluations, %s\n", r.Runs, r.Evaluations, r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(out, "%4s", "rank")
+	for _, name := range r.Parameters {
+		fmt.Fprintf(out, " %12s", name)
+	}
+	fmt.Fprintf(out, " %12s %12s %4s\n", "mean", "stddev", "runs")
+	for i, result := range r.Results {
+		fmt.Fprintf(out, "%4d", i+1)
+		for _, v := range result.Values {
+			fmt.Fprintf(out, " %12.4g", v)
+		}
+		if result.Err != nil {
+			fmt.Fprintf(out, " %s\n", result.Err)
+			continue
+		}
+		fmt.Fprintf(out, " %12.6g %12.4g %4d", result.Mean, result.StdDev, len(result.Scores))
+		if result.Eliminated {
+			fmt.Fprint(out, " eliminated")
+		}
+		fmt.Fprintln(out)
+	}
+	return out.String()
+}
@@ -0,0 +1,260 @@
+package tune
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomjcleveland/genetic"
+	"github.com/tomjcleveland/genetic/benchmarks"
+)
+
+func params() Params {
+	return Params{
+		Base: genetic.Params{
+			Mutation:        0.02,
+			Crossover:       0.9,
+			SelectionMethod: genetic.Tournament(2),
+			TargetFitness:   1000,
+			Termination:     genetic.Termination{MaxGenerations: 5},
+		},
+		Population: benchmarks.OneMax(50).Population,
+		Size:       20,
+		Seeds:      3,
+	}
+}
+
+func Test_NewTuner_InvalidParams_Error(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Params)
+	}{
+		{"no population", func(p *Params) { p.Population = nil }},
+		{"no parameters", func(p *Params) { p.Parameters = nil }},
+		{"no set", func(p *Params) { p.Parameters = []Parameter{{Name: "x"}} }},
+		{"min above max", func(p *Params) { p.Parameters = []Parameter{Mutation(0.5, 0.1)} }},
+		{"no termination", func(p *Params) { p.Base.Termination = genetic.Termination{} }},
+		{"method", func(p *Params) { p.Method = 7 }},
+		{"seeds", func(p *Params) { p.Seeds = -1 }},
+		{"budget", func(p *Params) { p.MaxEvaluations = -1 }},
+	}
+	for _, test := range tests {
+		p := params()
+		p.Parameters = []Parameter{Elitism(0, 2)}
+		test.modify(&p)
+		_, err := NewTuner(p)
+		assert.Error(t, err, test.name)
+	}
+}
+
+func Test_Parameter_grid_Values(t *testing.T) {
+	assert.Equal(t, []float64{0, 0.25, 0.5, 0.75, 1}, Mutation(0, 1).grid(5))
+	assert.Equal(t, []float64{2, 3}, TournamentSize(2, 3).grid(5))
+	assert.Equal(t, []float64{0.1}, Crossover(0.1, 0.1).grid(5))
+	p := Elitism(0, 10)
+	p.Values = []float64{1, 4}
+	assert.Equal(t, []float64{1, 4}, p.grid(5))
+	assert.Equal(t, 4.0, p.value(1))
+	assert.Equal(t, 1.0, p.value(0.2))
+}
+
+func Test_Tuner_Grid_RankedReport(t *testing.T) {
+	p := params()
+	p.Parameters = []Parameter{Elitism(0, 2), TournamentSize(2, 3)}
+	p.Steps = 3
+	p.Parallelism = 2
+	tuner, err := NewTuner(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, tuner.Run())
+
+	report := tuner.Report()
+	assert.Equal(t, []string{"elitism", "tournament"}, report.Parameters)
+	assert.Len(t, report.Results, 6)
+	assert.Equal(t, 18, report.Runs)
+	for i, r := range report.Results {
+		assert.Len(t, r.Scores, 3)
+		assert.NoError(t, r.Err)
+		if i > 0 {
+			assert.True(t, report.Results[i-1].Mean >= r.Mean)
+		}
+	}
+	best, err := report.Best()
+	assert.NoError(t, err)
+	assert.Equal(t, report.Results[0], best)
+	assert.Equal(t, int(best.Values[0]), report.Params(best).Elitism)
+	assert.Contains(t, report.String(), "elitism")
+}
+
+func Test_Tuner_Random_ValuesInRange(t *testing.T) {
+	p := params()
+	p.Parameters = []Parameter{Mutation(0.01, 0.1), PopulationSize(10, 30)}
+	p.Method = Random
+	p.Samples = 4
+	p.Seeds = 1
+	tuner, err := NewTuner(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, tuner.Run())
+
+	report := tuner.Report()
+	assert.True(t, len(report.Results) > 0 && len(report.Results) <= 4)
+	for _, r := range report.Results {
+		assert.True(t, r.Values[0] >= 0.01 && r.Values[0] <= 0.1)
+		assert.True(t, r.Values[1] >= 10 && r.Values[1] <= 30)
+		assert.Equal(t, float64(int(r.Values[1])), r.Values[1])
+	}
+}
+
+func Test_Tuner_Racing_PoorConfigurationEliminated(t *testing.T) {
+	p := params()
+	size := PopulationSize(0, 0)
+	size.Values = []float64{2, 50}
+	p.Parameters = []Parameter{size}
+	p.Seeds = 6
+	p.Racing = true
+	p.MinRuns = 2
+	tuner, err := NewTuner(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, tuner.Run())
+
+	report := tuner.Report()
+	assert.Len(t, report.Results, 2)
+	assert.Equal(t, 50.0, report.Results[0].Values[0])
+	assert.Len(t, report.Results[0].Scores, 6)
+	assert.True(t, report.Results[1].Eliminated)
+	assert.True(t, len(report.Results[1].Scores) < 6)
+	assert.Contains(t, report.String(), "eliminated")
+}
+
+func Test_Tuner_InvalidConfiguration_ErrRecorded(t *testing.T) {
+	p := params()
+	crossover := Crossover(0, 0)
+	crossover.Values = []float64{2, 0.9}
+	p.Parameters = []Parameter{crossover}
+	tuner, err := NewTuner(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, tuner.Run())
+
+	report := tuner.Report()
+	assert.Len(t, report.Results, 2)
+	assert.NoError(t, report.Results[0].Err)
+	assert.Error(t, report.Results[1].Err)
+	assert.Empty(t, report.Results[1].Scores)
+
+	crossover.Values = []float64{2}
+	p.Parameters = []Parameter{crossover}
+	tuner, err = NewTuner(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, tuner.Run())
+	_, err = tuner.Report().Best()
+	assert.Error(t, err)
+}
+
+func Test_Tuner_EvaluationBudget_ErrTerminated(t *testing.T) {
+	p := params()
+	p.Parameters = []Parameter{Mutation(0, 0.1)}
+	p.Steps = 10
+	p.MaxEvaluations = 300
+	tuner, err := NewTuner(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, genetic.ErrTerminated, tuner.Run())
+
+	report := tuner.Report()
+	assert.True(t, report.Runs < 30)
+	assert.True(t, report.Evaluations >= 300)
+}
+
+func Test_Tuner_EvaluationBudgetWithinRun_RunAbandoned(t *testing.T) {
+	p := params()
+	p.Parameters = []Parameter{Mutation(0, 0.1)}
+	p.MaxEvaluations = 50
+	tuner, err := NewTuner(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, genetic.ErrTerminated, tuner.Run())
+
+	report := tuner.Report()
+	assert.Equal(t, 0, report.Runs)
+	assert.True(t, report.Evaluations >= 50)
+	assert.True(t, report.Evaluations < 120)
+}
+
+func Test_Tuner_MetaGA_RankedReport(t *testing.T) {
+	p := params()
+	p.Parameters = []Parameter{Mutation(0, 0.1), Elitism(0, 3)}
+	p.Method = MetaGA
+	p.Samples = 4
+	p.Generations = 2
+	p.Seeds = 2
+	p.Parallelism = 2
+	tuner, err := NewTuner(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, tuner.Run())
+
+	report := tuner.Report()
+	assert.NotEmpty(t, report.Results)
+	for i, r := range report.Results {
+		assert.Len(t, r.Scores, 2)
+		if i > 0 {
+			assert.True(t, report.Results[i-1].Mean >= r.Mean)
+		}
+	}
+}
+
+func Test_Tuner_MetaGA_SameSeed_SameConfigurations(t *testing.T) {
+	var reports []string
+	for i := 0; i < 2; i++ {
+		p := params()
+		p.Population = func(n int) ([]genetic.Individual, error) {
+			problem := benchmarks.OneMax(50)
+			problem.Seed(1)
+			return problem.Population(n)
+		}
+		p.Parameters = []Parameter{Mutation(0, 0.1), Crossover(0.5, 1)}
+		p.Method = MetaGA
+		p.Samples = 6
+		p.Generations = 4
+		p.Seeds = 1
+		tuner, err := NewTuner(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NoError(t, tuner.Run())
+		var values []string
+		for _, r := range tuner.Report().Results {
+			values = append(values, fmt.Sprint(r.Values, r.Mean))
+		}
+		reports = append(reports, strings.Join(values, "\n"))
+	}
+	assert.Equal(t, reports[0], reports[1])
+}
+
+func Test_Tuner_Cancelled_ErrContextCancelled(t *testing.T) {
+	p := params()
+	p.Parameters = []Parameter{Mutation(0, 0.1)}
+	tuner, err := NewTuner(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tuner.Start(ctx)
+	assert.Equal(t, genetic.ErrContextCancelled, tuner.Wait())
+	assert.True(t, strings.HasPrefix(tuner.Report().String(), "0 runs"))
+}
@@ -0,0 +1,56 @@
+package genetic
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors for the kinds of invalid parameters, which every
+// ValidationError wraps one of, for use with errors.Is.
+var (
+	// ErrOutOfRange means a parameter is outside the range of values
+	// it can take, such as a negative count or a rate above 1.
+	ErrOutOfRange = errors.New("out of range")
+
+	// ErrExceedsPopulation means a count is larger than the population.
+	ErrExceedsPopulation = errors.New("exceeds population size")
+
+	// ErrRequired means a required parameter is missing.
+	ErrRequired = errors.New("required")
+
+	// ErrConflict means a parameter contradicts another one.
+	ErrConflict = errors.New("conflicting parameters")
+
+	// ErrUnsupported means the individuals don't support
+	// what a parameter asks of them.
+	ErrUnsupported = errors.New("unsupported by individuals")
+)
+
+// ValidationError is returned by New and NewController
+// when a parameter is invalid.
+type ValidationError struct {
+	// Field is the name of the invalid field of Params,
+	// such as "Elitism" or "Termination.MaxGenerations".
+	Field string
+
+	// Reason describes what's wrong with it.
+	Reason string
+
+	// Err is one of the sentinel errors above, or the error that
+	// made the field invalid, such as a failing Breeding function.
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+// Unwrap returns the underlying error, for errors.Is and errors.As.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// invalid returns a ValidationError for field.
+func invalid(field string, err error, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Field: field, Reason: fmt.Sprintf(format, args...), Err: err}
+}